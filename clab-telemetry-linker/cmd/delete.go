@@ -17,13 +17,21 @@ var deleteCmd = &cobra.Command{
 			log.Fatalf("Error reading/creating config: %v\n", err)
 		}
 		helper := helpers.NewDefaultHelper()
-		command := command.NewDefaultSetCommand(Node, Interface, defaultConfig.GetValue(helper.GetDefaultClabNameKey()))
-		manager := impairments.NewDefaultSetter(defaultConfig, Node, Interface, helper, command)
+		interfaceName := getClabInterfaceName(defaultConfig)
+		if err := getTopologyValidator(defaultConfig).ValidateInterface(Node, interfaceName); err != nil {
+			log.Fatalln(err)
+		}
+		command, err := command.NewSetCommand(defaultConfig.GetValue(command.BackendKey), Node, interfaceName, defaultConfig.GetValue(helper.GetDefaultClabNameKey()))
+		if err != nil {
+			log.Fatalf("Error creating impairment backend: %v\n", err)
+		}
+		manager := impairments.NewDefaultSetter(defaultConfig, Node, interfaceName, helper, command)
 		// Delete is setting all values to 0
 		handleError(manager.SetDelay(0), manager, "Error setting delay")
 		handleError(manager.SetJitter(0), manager, "Error setting jitter")
 		handleError(manager.SetLoss(0), manager, "Error setting loss")
 		handleError(manager.SetRate(0), manager, "Error setting rate")
+		handleError(manager.SetBackgroundLoad(0), manager, "Error setting background load")
 		handleError(manager.ApplyImpairments(), manager, "Error applying impairments")
 		handleError(manager.WriteConfig(), manager, "Error writing config")
 	},
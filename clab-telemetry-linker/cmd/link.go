@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/spf13/cobra"
+)
+
+var (
+	EndpointA string
+	EndpointB string
+	Reverse   scenario.Impairments
+)
+
+// createLinkManager returns the link of the endpoint flags and a manager applying both endpoints in one transaction
+func createLinkManager() (link.Link, *link.DefaultManager) {
+	endpointA, err := link.ParseEndpoint(EndpointA)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	endpointB, err := link.ParseEndpoint(EndpointB)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defaultConfig, err := config.NewDefaultConfig()
+	if err != nil {
+		log.Fatalf("Error reading/creating config: %v\n", err)
+	}
+	mapper, err := naming.NewDefaultMapperFromConfig(defaultConfig)
+	if err != nil {
+		log.Fatalf("Error creating interface name mapper: %v\n", err)
+	}
+	endpointA.Interface = mapInterfaceName(mapper, endpointA.Interface)
+	endpointB.Interface = mapInterfaceName(mapper, endpointB.Interface)
+	link_ := link.Link{A: endpointA, B: endpointB}
+	if err := getTopologyValidator(defaultConfig).ValidateLink(link_); err != nil {
+		log.Fatalln(err)
+	}
+	applier := scenario.NewDefaultApplier(defaultConfig, helpers.NewDefaultHelper(), mapper, false)
+	return link_, link.NewDefaultManager(defaultConfig, mapper, applier)
+}
+
+// getReverseImpairments returns the impairments of the direction B to A, flags which are not given are taken from A to B
+func getReverseImpairments(cmd *cobra.Command, aToB scenario.Impairments) scenario.Impairments {
+	bToA := aToB
+	if cmd.Flags().Changed("reverse-delay") {
+		bToA.Delay = Reverse.Delay
+	}
+	if cmd.Flags().Changed("reverse-jitter") {
+		bToA.Jitter = Reverse.Jitter
+	}
+	if cmd.Flags().Changed("reverse-loss") {
+		bToA.Loss = Reverse.Loss
+	}
+	if cmd.Flags().Changed("reverse-rate") {
+		bToA.Rate = Reverse.Rate
+	}
+	if cmd.Flags().Changed("reverse-background-load") {
+		bToA.BackgroundLoad = Reverse.BackgroundLoad
+	}
+	return bToA
+}
+
+var setLinkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Set impairments on both endpoints of a link",
+	Run: func(cmd *cobra.Command, args []string) {
+		link, manager := createLinkManager()
+		aToB := scenario.Impairments{Delay: Delay, Jitter: Jitter, Loss: Loss, Rate: Rate, BackgroundLoad: BackgroundLoad}
+		bToA := getReverseImpairments(cmd, aToB)
+		for _, impairments := range []scenario.Impairments{aToB, bToA} {
+			if err := impairments.Validate(); err != nil {
+				log.Fatalf("Invalid impairments: %v\n", err)
+			}
+		}
+		if err := manager.Set(link, aToB, bToA); err != nil {
+			log.Fatalf("Error setting link impairments: %v\n", err)
+		}
+	},
+}
+
+var deleteLinkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Delete impairments on both endpoints of a link",
+	Run: func(cmd *cobra.Command, args []string) {
+		link, manager := createLinkManager()
+		if err := manager.Delete(link); err != nil {
+			log.Fatalf("Error deleting link impairments: %v\n", err)
+		}
+	},
+}
+
+func addEndpointFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&EndpointA, "endpoint-a", "a", "", "first endpoint of the link as node:interface e.g. XR-1:Gi0-0-0-0")
+	cmd.Flags().StringVarP(&EndpointB, "endpoint-b", "b", "", "second endpoint of the link as node:interface e.g. XR-2:Gi0-0-0-1")
+	markRequiredFlags(cmd, []string{"endpoint-a", "endpoint-b"})
+}
+
+func init() {
+	setCmd.AddCommand(setLinkCmd)
+	addEndpointFlags(setLinkCmd)
+	setLinkCmd.Flags().Uint64VarP(&Delay, "delay", "d", 0, "delay in ms of both directions, or of A to B if --reverse-delay is set")
+	setLinkCmd.Flags().Uint64VarP(&Jitter, "jitter", "j", 0, "jitter in ms of both directions, or of A to B if --reverse-jitter is set")
+	setLinkCmd.Flags().Float64VarP(&Loss, "loss", "l", 0, "packet loss in % of both directions, or of A to B if --reverse-loss is set")
+	setLinkCmd.Flags().Uint64VarP(&Rate, "rate", "r", 0, "rate in kbit/s of both directions, or of A to B if --reverse-rate is set")
+	setLinkCmd.Flags().Float64Var(&BackgroundLoad, "background-load", 0, "background load in % of both directions, or of A to B if --reverse-background-load is set")
+	setLinkCmd.Flags().Uint64Var(&Reverse.Delay, "reverse-delay", 0, "delay in ms of B to A")
+	setLinkCmd.Flags().Uint64Var(&Reverse.Jitter, "reverse-jitter", 0, "jitter in ms of B to A")
+	setLinkCmd.Flags().Float64Var(&Reverse.Loss, "reverse-loss", 0, "packet loss in % of B to A")
+	setLinkCmd.Flags().Uint64Var(&Reverse.Rate, "reverse-rate", 0, "rate in kbit/s of B to A")
+	setLinkCmd.Flags().Float64Var(&Reverse.BackgroundLoad, "reverse-background-load", 0, "background load in % of B to A")
+
+	deleteCmd.AddCommand(deleteLinkCmd)
+	addEndpointFlags(deleteLinkCmd)
+}
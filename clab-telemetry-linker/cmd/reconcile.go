@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/reconciler"
+	"github.com/spf13/cobra"
+)
+
+// driftExitCode is returned by a dry run which found drift, so scripts can distinguish it from errors
+const driftExitCode = 2
+
+var DryRun bool
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Re-apply the configured impairments where they differ from the lab",
+	Run: func(cmd *cobra.Command, args []string) {
+		defaultConfig, err := config.NewDefaultConfig()
+		if err != nil {
+			log.Fatalf("Error reading/creating config: %v\n", err)
+		}
+		drifts, reconcileErr := reconciler.NewDefaultReconciler(defaultConfig, helpers.NewDefaultHelper(), DryRun).Reconcile(Node)
+		if err := reconciler.WriteDrifts(os.Stdout, drifts, Output); err != nil {
+			log.Fatalf("Error writing drift: %v\n", err)
+		}
+		if reconcileErr != nil {
+			log.Fatalf("Error reconciling impairments: %v\n", reconcileErr)
+		}
+		for _, drift := range drifts {
+			if drift.Error != "" {
+				log.Fatalf("Unable to re-apply all impairments\n")
+			}
+		}
+		if DryRun && len(drifts) > 0 {
+			os.Exit(driftExitCode)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().StringVarP(&Node, "node", "n", "", "only reconcile this node instead of all nodes in the config")
+	reconcileCmd.Flags().BoolVar(&DryRun, "dry-run", false, "only report drift without re-applying the impairments")
+	reconcileCmd.Flags().StringVarP(&Output, "output", "o", output.Table, "output format: table, json or yaml")
+}
@@ -8,13 +8,14 @@ import (
 )
 
 var (
-	log       = logging.DefaultLogger.WithField("subsystem", "cmd")
-	Node      string
-	Interface string
-	Delay     uint64
-	Jitter    uint64
-	Loss      float64
-	Rate      uint64
+	log            = logging.DefaultLogger.WithField("subsystem", "cmd")
+	Node           string
+	Interface      string
+	Delay          uint64
+	Jitter         uint64
+	Loss           float64
+	Rate           uint64
+	BackgroundLoad float64
 )
 
 func markRequiredFlags(cmd *cobra.Command, flags []string) {
@@ -35,6 +36,9 @@ Example usage:
 	sudo clab-telemetry-linker set -n XR-1 -i Gi0-0-0-0 --delay 1ms --jitter 1ms --loss 5 --rate 100000 	
 	sudo clab-telemetry-linker show -n XR-1 	
 	sudo clab-telemetry-linker delete -n XR-1 -i Gi0-0-0-0
+	sudo clab-telemetry-linker set link -a XR-1:Gi0-0-0-0 -b XR-2:Gi0-0-0-1 --delay 10
+	sudo clab-telemetry-linker scenario apply -f scenario.yaml
+	sudo clab-telemetry-linker schedule run -f schedule.yaml
 	`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		if !helpers.NewDefaultHelper().IsRoot() {
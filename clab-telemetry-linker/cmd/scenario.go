@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ScenarioFile string
+	Replace      bool
+)
+
+var scenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "Manage the impairments of several nodes and interfaces at once",
+}
+
+var scenarioApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply all impairments of a scenario file or none of them",
+	Run: func(cmd *cobra.Command, args []string) {
+		defaultConfig, err := config.NewDefaultConfig()
+		if err != nil {
+			log.Fatalf("Error reading/creating config: %v\n", err)
+		}
+		loadedScenario, err := scenario.LoadScenario(ScenarioFile)
+		if err != nil {
+			log.Fatalf("Error loading scenario: %v\n", err)
+		}
+		mapper, err := naming.NewDefaultMapperFromConfig(defaultConfig)
+		if err != nil {
+			log.Fatalf("Error creating interface name mapper: %v\n", err)
+		}
+		changes, err := scenario.NewDefaultApplier(defaultConfig, helpers.NewDefaultHelper(), mapper, Replace).Apply(loadedScenario)
+		if err != nil {
+			log.Fatalf("Error applying scenario: %v\n", err)
+		}
+		log.Infof("Applied scenario %s to %d interfaces\n", ScenarioFile, len(changes))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scenarioCmd)
+	scenarioCmd.AddCommand(scenarioApplyCmd)
+	scenarioApplyCmd.Flags().StringVarP(&ScenarioFile, "file", "f", "", "scenario file containing the impairments of the nodes and interfaces")
+	scenarioApplyCmd.Flags().BoolVar(&Replace, "replace", false, "reset the impairments of configured interfaces which are not part of the scenario")
+	markRequiredFlags(scenarioApplyCmd, []string{"file"})
+}
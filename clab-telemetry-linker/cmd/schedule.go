@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+var ScheduleFile string
+
+// createScheduler loads the schedule file and maps its interface names like set
+func createScheduler(defaultConfig config.Config) *schedule.DefaultScheduler {
+	loadedSchedule, err := schedule.LoadSchedule(ScheduleFile)
+	if err != nil {
+		log.Fatalf("Error loading schedule: %v\n", err)
+	}
+	mapper, err := naming.NewDefaultMapperFromConfig(defaultConfig)
+	if err != nil {
+		log.Fatalf("Error creating interface name mapper: %v\n", err)
+	}
+	scheduler, err := schedule.NewDefaultScheduler(defaultConfig, helpers.NewDefaultHelper(), mapper, loadedSchedule)
+	if err != nil {
+		log.Fatalf("Error creating scheduler: %v\n", err)
+	}
+	return scheduler
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Change impairments over time",
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run a schedule until all profiles have ended or it is interrupted",
+	Run: func(cmd *cobra.Command, args []string) {
+		defaultConfig, err := config.NewDefaultConfig()
+		if err != nil {
+			log.Fatalf("Error reading/creating config: %v\n", err)
+		}
+		scheduler := createScheduler(defaultConfig)
+		signalChan := make(chan os.Signal, 1)
+		signal.Notify(signalChan, os.Interrupt)
+		go func() {
+			<-signalChan
+			log.Info("Received interrupt signal, stopping schedule")
+			scheduler.Stop()
+		}()
+		if err := scheduler.Run(); err != nil {
+			log.Fatalf("Error running schedule: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	scheduleRunCmd.Flags().StringVarP(&ScheduleFile, "file", "f", "", "schedule file containing the step, ramp and flap profiles")
+	markRequiredFlags(scheduleRunCmd, []string{"file"})
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +19,24 @@ func handleError(err error, manager *impairments.DefaultSetter, message string)
 	}
 }
 
+// mapInterfaceName maps telemetry interface names like GigabitEthernet0/0/0/0 to the containerlab name, other names are used as given
+func mapInterfaceName(mapper naming.Mapper, name string) string {
+	clabName, err := mapper.ToClabName(name)
+	if err != nil {
+		log.Debugf("Use interface name %s as given: %v\n", name, err)
+		return name
+	}
+	return clabName
+}
+
+func getClabInterfaceName(config config.Config) string {
+	mapper, err := naming.NewDefaultMapperFromConfig(config)
+	if err != nil {
+		log.Fatalf("Error creating interface name mapper: %v\n", err)
+	}
+	return mapInterfaceName(mapper, Interface)
+}
+
 var setCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set impairments on a containerlab interface",
@@ -27,12 +46,20 @@ var setCmd = &cobra.Command{
 			log.Fatalf("Error reading/creating config: %v\n", err)
 		}
 		helper := helpers.NewDefaultHelper()
-		command := command.NewDefaultSetCommand(Node, Interface, defaultConfig.GetValue(helper.GetDefaultClabNameKey()))
-		manager := impairments.NewDefaultSetter(defaultConfig, Node, Interface, helper, command)
+		interfaceName := getClabInterfaceName(defaultConfig)
+		if err := getTopologyValidator(defaultConfig).ValidateInterface(Node, interfaceName); err != nil {
+			log.Fatalln(err)
+		}
+		command, err := command.NewSetCommand(defaultConfig.GetValue(command.BackendKey), Node, interfaceName, defaultConfig.GetValue(helper.GetDefaultClabNameKey()))
+		if err != nil {
+			log.Fatalf("Error creating impairment backend: %v\n", err)
+		}
+		manager := impairments.NewDefaultSetter(defaultConfig, Node, interfaceName, helper, command)
 		handleError(manager.SetDelay(Delay), manager, "Error setting delay")
 		handleError(manager.SetJitter(Jitter), manager, "Error setting jitter")
 		handleError(manager.SetLoss(Loss), manager, "Error setting loss")
 		handleError(manager.SetRate(Rate), manager, "Error setting rate")
+		handleError(manager.SetBackgroundLoad(BackgroundLoad), manager, "Error setting background load")
 		handleError(manager.ApplyImpairments(), manager, "Error applying impairments")
 		handleError(manager.WriteConfig(), manager, "Error writing config")
 	},
@@ -46,6 +73,7 @@ func init() {
 	setCmd.Flags().Uint64VarP(&Jitter, "jitter", "j", 0, "outgoing delay variation (jitter) in ms")
 	setCmd.Flags().Float64VarP(&Loss, "loss", "l", 0, "packet loss in %")
 	setCmd.Flags().Uint64VarP(&Rate, "rate", "r", 0, "link rate / bandwidth in kbit/s")
+	setCmd.Flags().Float64VarP(&BackgroundLoad, "background-load", "b", 0, "synthetic background load added to the utilization counters in % of the rate")
 
 	markRequiredFlags(setCmd, []string{"node", "interface"})
 }
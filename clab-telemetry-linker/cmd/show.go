@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
 	"github.com/spf13/cobra"
 )
 
+var Output string
+
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show impairments on a containerlab node",
@@ -16,17 +21,28 @@ var showCmd = &cobra.Command{
 		if err != nil {
 			log.Fatalf("Error reading/creating config: %v\n", err)
 		}
+		if err := getTopologyValidator(defaultConfig).ValidateNode(Node); err != nil {
+			log.Fatalln(err)
+		}
 		helper := helpers.NewDefaultHelper()
-		command := command.NewDefaultShowCommand(Node, defaultConfig.GetValue(helper.GetDefaultClabNameKey()))
-		manager := impairments.NewDefaultViewer(Node, command)
-		if err := manager.ShowImpairments(); err != nil {
+		command, err := command.NewShowCommand(defaultConfig.GetValue(command.BackendKey), Node, defaultConfig.GetValue(helper.GetDefaultClabNameKey()))
+		if err != nil {
+			log.Fatalf("Error creating impairment backend: %v\n", err)
+		}
+		manager := impairments.NewDefaultViewer(defaultConfig, Node, helper, command)
+		records, err := manager.GetImpairments()
+		if err != nil {
 			log.Fatalf("Error showing impairments: %v\n", err)
 		}
+		if err := impairments.WriteRecords(os.Stdout, records, Output); err != nil {
+			log.Fatalf("Error writing impairments: %v\n", err)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(showCmd)
 	showCmd.Flags().StringVarP(&Node, "node", "n", "", "node to apply the impairment to ")
+	showCmd.Flags().StringVarP(&Output, "output", "o", output.Table, "output format: table, json or yaml")
 	markRequiredFlags(showCmd, []string{"node"})
 }
@@ -3,22 +3,184 @@ package cmd
 import (
 	"os"
 	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/hawkv6/clab-telemetry-linker/pkg/api"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/control"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/deadletter"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/processor"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/publisher"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/reconciler"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/service"
 	"github.com/spf13/cobra"
 )
 
 var (
-	KafkaBroker    string
-	ReceiverTopic  string
-	PublisherTopic string
+	KafkaBrokers      []string
+	ReceiverBrokers   []string
+	PublisherBrokers  []string
+	ReceiverTopic     string
+	PublisherTopic    string
+	GroupID           string
+	RebalanceStrategy string
+	From              string
+	DeadLetterTopic   string
+	DeadLetterFile    string
+	Passthrough       bool
+	PassthroughFilter struct {
+		IncludeMeasurements []string
+		ExcludeMeasurements []string
+		IncludeTags         []string
+		ExcludeTags         []string
+	}
+	KafkaSecurity      = kafka.NewSecurityConfig()
+	KafkaProducer      = kafka.NewProducerConfig()
+	ReconcileInterval  time.Duration
+	ReconcileDryRun    bool
+	APIListen          string
+	APITokenFile       string
+	GRPCListen         string
+	MetricsListen      string
+	PublisherTypes     []string
+	PublisherFile      string
+	PublisherKey       string
+	PublisherQueueSize int
+	OutputFormat       string
+	Influx             publisher.InfluxConfig
+	InfluxTokenFile    string
 )
 
+// getBrokers returns the cluster specific brokers if set, otherwise the common brokers
+func getBrokers(specificBrokers []string, flag string) []string {
+	if len(specificBrokers) > 0 {
+		return specificBrokers
+	}
+	if len(KafkaBrokers) == 0 {
+		log.Fatalf("Either --broker or --%s has to be set\n", flag)
+	}
+	return KafkaBrokers
+}
+
+// createDeadLetterWriter combines all configured dead-letter destinations, without any destination dead letters are discarded
+func createDeadLetterWriter() deadletter.Writer {
+	var writers []deadletter.Writer
+	if DeadLetterTopic != "" {
+		writers = append(writers, deadletter.NewKafkaWriter(getBrokers(PublisherBrokers, "publisher-broker"), DeadLetterTopic, KafkaSecurity))
+	}
+	if DeadLetterFile != "" {
+		writers = append(writers, deadletter.NewFileWriter(DeadLetterFile))
+	}
+	return deadletter.NewMultiWriter(writers...)
+}
+
+// createPassthroughFilter returns nil if pass-through is disabled
+func createPassthroughFilter() *consumer.PassthroughFilter {
+	if !Passthrough {
+		return nil
+	}
+	filter, err := consumer.NewPassthroughFilter(PassthroughFilter.IncludeMeasurements, PassthroughFilter.ExcludeMeasurements, PassthroughFilter.IncludeTags, PassthroughFilter.ExcludeTags)
+	if err != nil {
+		log.Fatalf("Error creating passthrough filter: %v\n", err)
+	}
+	return filter
+}
+
+// createSinkPublisher returns the Kafka publisher, the InfluxDB publisher which writes directly to the InfluxDB v2 write API
+// or the file publisher
+func createSinkPublisher(publisherType string, processedMsgChan chan consumer.Message) publisher.Publisher {
+	switch publisherType {
+	case "kafka":
+		if PublisherTopic == "" {
+			log.Fatalln("--publisher-topic has to be set for the kafka publisher")
+		}
+		encoder, err := publisher.NewEncoder(OutputFormat)
+		if err != nil {
+			log.Fatalf("Error creating encoder: %v\n", err)
+		}
+		if KafkaProducer.FlushTimeout <= 0 {
+			log.Fatalln("--producer-flush-timeout has to be positive")
+		}
+		keyTemplate, err := publisher.NewKeyTemplate(PublisherKey)
+		if err != nil {
+			log.Fatalf("Error parsing --publisher-key: %v\n", err)
+		}
+		return publisher.NewKafkaPublisher(getBrokers(PublisherBrokers, "publisher-broker"), PublisherTopic, KafkaSecurity, KafkaProducer, encoder, keyTemplate, processedMsgChan)
+	case "influx":
+		if Influx.URL == "" || Influx.Org == "" || Influx.Bucket == "" {
+			log.Fatalln("--influx-url, --influx-org and --influx-bucket have to be set for the influx publisher")
+		}
+		if OutputFormat != publisher.OutputFormatLineProtocol {
+			log.Fatalln("The influx publisher only supports --output-format " + publisher.OutputFormatLineProtocol)
+		}
+		if Influx.BatchSize <= 0 || Influx.FlushInterval <= 0 {
+			log.Fatalln("--influx-batch-size and --influx-flush-interval have to be positive")
+		}
+		Influx.Token = os.Getenv(publisher.InfluxTokenEnv)
+		if InfluxTokenFile != "" {
+			token, err := os.ReadFile(InfluxTokenFile)
+			if err != nil {
+				log.Fatalf("Error reading InfluxDB token file: %v\n", err)
+			}
+			Influx.Token = strings.TrimSpace(string(token))
+		}
+		return publisher.NewInfluxPublisher(Influx, processedMsgChan)
+	case "file":
+		if PublisherFile == "" {
+			log.Fatalln("--publisher-file has to be set for the file publisher")
+		}
+		return publisher.NewFilePublisher(PublisherFile, processedMsgChan)
+	default:
+		log.Fatalf("Unknown publisher %q, use kafka, influx or file\n", publisherType)
+		return nil
+	}
+}
+
+// createPublisher returns the publisher reading the processed messages, with several publisher types it is a fan-out publisher
+// which copies the messages to the queue of every sink, the sink publishers are returned by type to be supervised by the service
+func createPublisher(processedMsgChan chan consumer.Message) (publisher.Publisher, map[string]publisher.Publisher) {
+	if len(PublisherTypes) == 0 {
+		log.Fatalln("--publisher has to be set")
+	}
+	if len(PublisherTypes) == 1 {
+		return createSinkPublisher(PublisherTypes[0], processedMsgChan), nil
+	}
+	if PublisherQueueSize <= 0 {
+		log.Fatalln("--publisher-queue-size has to be positive")
+	}
+	fanoutPublisher := publisher.NewFanoutPublisher(processedMsgChan)
+	sinks := make(map[string]publisher.Publisher, len(PublisherTypes))
+	for _, publisherType := range PublisherTypes {
+		if _, ok := sinks[publisherType]; ok {
+			log.Fatalf("Publisher %q is given more than once\n", publisherType)
+		}
+		sinks[publisherType] = createSinkPublisher(publisherType, fanoutPublisher.AddSink(publisherType, PublisherQueueSize))
+	}
+	return fanoutPublisher, sinks
+}
+
+// readAPIToken returns the bearer token of the HTTP and gRPC API, without token file the APIs are unauthenticated
+func readAPIToken() string {
+	if APITokenFile == "" {
+		return ""
+	}
+	content, err := os.ReadFile(APITokenFile)
+	if err != nil {
+		log.Fatalf("Error reading API token file: %v\n", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		log.Fatalf("API token file %s is empty\n", APITokenFile)
+	}
+	return token
+}
+
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start processing the telemetry data",
@@ -30,19 +192,50 @@ var startCmd = &cobra.Command{
 		if err := defaultConfig.WatchConfigChange(); err != nil {
 			log.Fatalf("Error watching config change: %v\n", err)
 		}
+		if err := KafkaSecurity.LoadDefaults(defaultConfig); err != nil {
+			log.Fatalf("Error loading kafka security config: %v\n", err)
+		}
 		unprocessedMsgChan := make(chan consumer.Message)
 		processedMsgChan := make(chan consumer.Message)
-		consumer := consumer.NewKafkaConsumer(KafkaBroker, ReceiverTopic, unprocessedMsgChan)
-		if err := consumer.Init(); err != nil {
-			log.Fatalf("Error initializing receiver: %v\n", err)
+		initialOffset, err := consumer.ParseInitialOffset(From, time.Now())
+		if err != nil {
+			log.Fatalf("Error parsing start position: %v\n", err)
 		}
-		publisher := publisher.NewKafkaPublisher(KafkaBroker, PublisherTopic, processedMsgChan)
-		if err := publisher.Init(); err != nil {
-			log.Fatalf("Error initializing publisher: %v\n", err)
+		deadLetterWriter := createDeadLetterWriter()
+		if err := deadLetterWriter.Init(); err != nil {
+			log.Fatalf("Error initializing dead-letter writer: %v\n", err)
 		}
-		processor := processor.NewDefaultProcessor(defaultConfig, unprocessedMsgChan, processedMsgChan, helpers.NewDefaultHelper())
+		consumer := consumer.NewKafkaConsumer(getBrokers(ReceiverBrokers, "receiver-broker"), ReceiverTopic, GroupID, RebalanceStrategy, initialOffset, KafkaSecurity, createPassthroughFilter(), unprocessedMsgChan, deadLetterWriter)
+		publisher, sinks := createPublisher(processedMsgChan)
+		// the naming rules are read again whenever the watched config file changes
+		mapper, err := naming.NewReloadingMapper(defaultConfig, defaultConfig)
+		if err != nil {
+			log.Fatalf("Error creating interface name mapper: %v\n", err)
+		}
+		processor := processor.NewDefaultProcessor(defaultConfig, unprocessedMsgChan, processedMsgChan, helpers.NewDefaultHelper(), deadLetterWriter, mapper)
 
 		defaultService := service.NewDefaultService(defaultConfig, consumer, processor, publisher)
+		for _, publisherType := range PublisherTypes {
+			if sink, ok := sinks[publisherType]; ok {
+				defaultService.AddPublisher(publisherType, sink)
+			}
+		}
+		if ReconcileInterval > 0 {
+			defaultReconciler := reconciler.NewDefaultReconciler(defaultConfig, helpers.NewDefaultHelper(), ReconcileDryRun)
+			defaultService.AddTask("reconciler", reconciler.NewPeriodicReconciler(defaultReconciler, ReconcileInterval))
+		}
+		if ScheduleFile != "" {
+			defaultService.AddTask("scheduler", createScheduler(defaultConfig))
+		}
+		if APIListen != "" {
+			defaultService.AddTask("api", api.NewDefaultServer(defaultConfig, helpers.NewDefaultHelper(), mapper, APIListen, readAPIToken()))
+		}
+		if GRPCListen != "" {
+			defaultService.AddTask("grpc", control.NewDefaultServer(defaultConfig, defaultConfig, helpers.NewDefaultHelper(), mapper, GRPCListen, readAPIToken()))
+		}
+		if MetricsListen != "" {
+			defaultService.AddTask("metrics", metrics.NewHTTPServer(MetricsListen))
+		}
 		defaultService.Start()
 		signalChan := make(chan os.Signal, 1)
 		signal.Notify(signalChan, os.Interrupt)
@@ -50,13 +243,61 @@ var startCmd = &cobra.Command{
 		<-signalChan
 		log.Info("Received interrupt signal, shutting down")
 		defaultService.Stop()
+		if err := deadLetterWriter.Close(); err != nil {
+			log.Errorf("Error closing dead-letter writer: %v\n", err)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(startCmd)
-	startCmd.Flags().StringVarP(&KafkaBroker, "broker", "b", "", "kafka broker to connect to e.g. localhost:9092")
+	startCmd.Flags().StringSliceVarP(&KafkaBrokers, "broker", "b", nil, "kafka bootstrap brokers used for receiving and publishing e.g. kafka-1:9092,kafka-2:9092")
+	startCmd.Flags().StringSliceVar(&ReceiverBrokers, "receiver-broker", nil, "kafka bootstrap brokers of the cluster where messages are received (overrides --broker)")
+	startCmd.Flags().StringSliceVar(&PublisherBrokers, "publisher-broker", nil, "kafka bootstrap brokers of the cluster where messages are published (overrides --broker)")
 	startCmd.Flags().StringVarP(&ReceiverTopic, "receiver-topic", "r", "", "topic where messages are received")
 	startCmd.Flags().StringVarP(&PublisherTopic, "publisher-topic", "p", "", "topic where messages are received")
-	markRequiredFlags(startCmd, []string{"broker", "receiver-topic", "publisher-topic"})
+	startCmd.Flags().StringVarP(&GroupID, "group-id", "g", "clab-telemetry-linker", "kafka consumer group the linker instances join")
+	startCmd.Flags().StringVar(&RebalanceStrategy, "rebalance-strategy", "range", "partition assignment strategy of the consumer group: range, roundrobin or sticky")
+	startCmd.Flags().StringVar(&From, "from", "newest", "start position if the group has no committed offset: oldest, newest, or a RFC3339 timestamp / duration (e.g. 15m) to rewind to")
+	startCmd.Flags().StringVar(&DeadLetterTopic, "dead-letter-topic", "", "topic on the publisher cluster where unprocessable messages are written together with the reason")
+	startCmd.Flags().StringVar(&DeadLetterFile, "dead-letter-file", "", "file where unprocessable messages are appended as JSON lines together with the reason")
+	startCmd.Flags().BoolVar(&Passthrough, "passthrough", false, "publish measurements which are not modified by the linker unchanged")
+	startCmd.Flags().StringSliceVar(&PassthroughFilter.IncludeMeasurements, "passthrough-include-measurement", nil, "only pass through measurements whose name matches one of these patterns e.g. errors")
+	startCmd.Flags().StringSliceVar(&PassthroughFilter.ExcludeMeasurements, "passthrough-exclude-measurement", nil, "do not pass through measurements whose name matches one of these patterns")
+	startCmd.Flags().StringSliceVar(&PassthroughFilter.IncludeTags, "passthrough-include-tag", nil, "only pass through measurements with a tag matching one of these key=pattern filters e.g. source=XR-*")
+	startCmd.Flags().StringSliceVar(&PassthroughFilter.ExcludeTags, "passthrough-exclude-tag", nil, "do not pass through measurements with a tag matching one of these key=pattern filters")
+	startCmd.Flags().StringVar(&KafkaProducer.Acks, "producer-acks", KafkaProducer.Acks, "acknowledgements the kafka publisher waits for: none, leader or all")
+	startCmd.Flags().BoolVar(&KafkaProducer.Idempotent, "producer-idempotent", false, "produce idempotently, so retries do not duplicate messages (requires --producer-acks all)")
+	startCmd.Flags().IntVar(&KafkaProducer.Retries, "producer-retries", KafkaProducer.Retries, "number of retries of the kafka publisher before a message fails")
+	startCmd.Flags().DurationVar(&KafkaProducer.RetryBackoff, "producer-retry-backoff", KafkaProducer.RetryBackoff, "time the kafka publisher waits between retries")
+	startCmd.Flags().StringVar(&KafkaProducer.Compression, "producer-compression", KafkaProducer.Compression, "compression of the published messages: none, gzip, snappy, lz4 or zstd")
+	startCmd.Flags().DurationVar(&KafkaProducer.FlushTimeout, "producer-flush-timeout", KafkaProducer.FlushTimeout, "time the kafka publisher waits on shutdown until the pending messages are delivered")
+	startCmd.Flags().BoolVar(&KafkaSecurity.TLSEnabled, "kafka-tls", false, "connect to kafka using TLS")
+	startCmd.Flags().StringVar(&KafkaSecurity.CAFile, "kafka-tls-ca", "", "CA certificate file to verify the kafka brokers")
+	startCmd.Flags().StringVar(&KafkaSecurity.CertFile, "kafka-tls-cert", "", "client certificate file for kafka TLS authentication")
+	startCmd.Flags().StringVar(&KafkaSecurity.KeyFile, "kafka-tls-key", "", "client key file for kafka TLS authentication")
+	startCmd.Flags().BoolVar(&KafkaSecurity.InsecureSkipVerify, "kafka-tls-insecure-skip-verify", false, "skip the verification of the kafka broker certificates")
+	startCmd.Flags().StringVar(&KafkaSecurity.SASLMechanism, "kafka-sasl-mechanism", "", "SASL mechanism: PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512")
+	startCmd.Flags().StringVar(&KafkaSecurity.Username, "kafka-sasl-username", "", "SASL username (or env "+kafka.UsernameEnv+")")
+	startCmd.Flags().StringVar(&KafkaSecurity.PasswordFile, "kafka-sasl-password-file", "", "file containing the SASL password (or env "+kafka.PasswordEnv+")")
+	startCmd.Flags().DurationVar(&ReconcileInterval, "reconcile-interval", 0, "compare the config with the lab in this interval and re-apply drifted impairments e.g. 1m (0 disables)")
+	startCmd.Flags().BoolVar(&ReconcileDryRun, "reconcile-dry-run", false, "only log drift found by the periodic reconciliation")
+	startCmd.Flags().StringVar(&ScheduleFile, "schedule", "", "schedule file whose profiles are run once the service has started")
+	startCmd.Flags().StringVar(&APIListen, "api-listen", "", "address where the HTTP API to manage impairments is served e.g. :8080 (empty disables)")
+	startCmd.Flags().StringVar(&APITokenFile, "api-token-file", "", "file containing the bearer token required by the HTTP and gRPC API")
+	startCmd.Flags().StringVar(&GRPCListen, "grpc-listen", "", "address where the gRPC control API is served e.g. :9090 (empty disables)")
+	startCmd.Flags().StringVar(&MetricsListen, "metrics-listen", "", "address where the Prometheus metrics are served on /metrics e.g. :9100 (empty disables)")
+	startCmd.Flags().StringSliceVar(&PublisherTypes, "publisher", []string{"kafka"}, "where the processed messages are published: kafka, influx and/or file, several publishers are fed independently")
+	startCmd.Flags().StringVar(&PublisherKey, "publisher-key", "{source}/{interface_name|name}", "template of the key of messages published to kafka, {tag} is replaced by the tag value, {a|b} by the first non-empty tag (empty disables keys)")
+	startCmd.Flags().StringVar(&PublisherFile, "publisher-file", "", "file where the file publisher appends the processed messages as JSON lines")
+	startCmd.Flags().IntVar(&PublisherQueueSize, "publisher-queue-size", 1000, "messages queued per publisher if several publishers are set, further messages are dropped for this publisher")
+	startCmd.Flags().StringVar(&OutputFormat, "output-format", publisher.OutputFormatLineProtocol, "encoding of the published messages: line-protocol, json (Telegraf JSON) or protobuf (remote write like time series)")
+	startCmd.Flags().StringVar(&Influx.URL, "influx-url", "", "URL of the InfluxDB v2 API used by the influx publisher e.g. http://influxdb:8086")
+	startCmd.Flags().StringVar(&Influx.Org, "influx-org", "", "InfluxDB organization the influx publisher writes to")
+	startCmd.Flags().StringVar(&Influx.Bucket, "influx-bucket", "", "InfluxDB bucket the influx publisher writes to")
+	startCmd.Flags().StringVar(&InfluxTokenFile, "influx-token-file", "", "file containing the InfluxDB API token (or env "+publisher.InfluxTokenEnv+")")
+	startCmd.Flags().IntVar(&Influx.BatchSize, "influx-batch-size", 1000, "number of lines the influx publisher writes at once")
+	startCmd.Flags().DurationVar(&Influx.FlushInterval, "influx-flush-interval", time.Second, "interval in which the influx publisher writes incomplete batches")
+	startCmd.Flags().BoolVar(&Influx.Gzip, "influx-gzip", true, "compress the batches written by the influx publisher with gzip")
+	markRequiredFlags(startCmd, []string{"receiver-topic"})
 }
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/topology"
+	"github.com/spf13/cobra"
+)
+
+var (
+	TopologyFile string
+	Inspect      bool
+	Lab          string
+)
+
+// getTopologyValidator validates arguments against the imported topology, without topology all arguments are accepted
+func getTopologyValidator(config config.Config) topology.Validator {
+	importedTopology, err := topology.Load(config, helpers.NewDefaultHelper())
+	if err != nil {
+		log.Fatalf("Error reading topology: %v\n", err)
+	}
+	return topology.NewDefaultValidator(importedTopology)
+}
+
+var topologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: "Manage the nodes and links of the lab",
+}
+
+var topologyImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import nodes, links and the lab prefix from a containerlab topology file or a deployed lab",
+	Run: func(cmd *cobra.Command, args []string) {
+		if (TopologyFile == "") == !Inspect {
+			log.Fatalln("Either --topology-file or --inspect has to be set")
+		}
+		defaultConfig, err := config.NewDefaultConfig()
+		if err != nil {
+			log.Fatalf("Error reading/creating config: %v\n", err)
+		}
+		var importedTopology *topology.Topology
+		if Inspect {
+			importedTopology, err = topology.Inspect(Lab)
+		} else {
+			importedTopology, err = topology.LoadTopologyFile(TopologyFile)
+		}
+		if err != nil {
+			log.Fatalf("Error importing topology: %v\n", err)
+		}
+		if err := topology.Store(defaultConfig, helpers.NewDefaultHelper(), importedTopology); err != nil {
+			log.Fatalln(err)
+		}
+		if err := defaultConfig.WriteConfig(); err != nil {
+			log.Fatalf("Error writing config: %v\n", err)
+		}
+		log.Infof("Imported lab %s with %d nodes and %d links, clab name %q\n", importedTopology.Name, len(importedTopology.Nodes), len(importedTopology.Links), importedTopology.ClabName)
+	},
+}
+
+var topologyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the imported nodes and links",
+	Run: func(cmd *cobra.Command, args []string) {
+		defaultConfig, err := config.NewDefaultConfig()
+		if err != nil {
+			log.Fatalf("Error reading/creating config: %v\n", err)
+		}
+		importedTopology, err := topology.Load(defaultConfig, helpers.NewDefaultHelper())
+		if err != nil {
+			log.Fatalf("Error reading topology: %v\n", err)
+		}
+		if importedTopology == nil {
+			log.Fatalln("No topology imported, use topology import")
+		}
+		err = output.Write(os.Stdout, Output, importedTopology, func() [][]string {
+			table := [][]string{{"Endpoint A", "Endpoint B"}}
+			for _, topologyLink := range importedTopology.Links {
+				table = append(table, []string{topologyLink.A.String(), topologyLink.B.String()})
+			}
+			return table
+		})
+		if err != nil {
+			log.Fatalf("Error writing topology: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(topologyCmd)
+	topologyCmd.AddCommand(topologyImportCmd)
+	topologyCmd.AddCommand(topologyShowCmd)
+	topologyImportCmd.Flags().StringVarP(&TopologyFile, "topology-file", "t", "", "containerlab topology file e.g. hawkv6.clab.yml")
+	topologyImportCmd.Flags().BoolVar(&Inspect, "inspect", false, "import the nodes of a deployed lab with containerlab inspect, links are not imported")
+	topologyImportCmd.Flags().StringVar(&Lab, "lab", "", "name of the deployed lab to inspect, required if several labs are deployed")
+	topologyShowCmd.Flags().StringVarP(&Output, "output", "o", output.Table, "output format: table, json or yaml")
+}
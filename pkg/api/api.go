@@ -0,0 +1,8 @@
+package api
+
+var subsystem = "api"
+
+type Server interface {
+	Start()
+	Stop()
+}
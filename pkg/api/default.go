@@ -0,0 +1,398 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/topology"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiPrefix       = "/api/v1/"
+	shutdownTimeout = 5 * time.Second
+	nodesKey        = "nodes"
+	linksKey        = "links"
+)
+
+// errInvalidRequest marks errors caused by the request, they are returned with status 400
+var errInvalidRequest = errors.New("Invalid request")
+
+// errNotFound is returned with status 404
+var errNotFound = errors.New("Not found")
+
+// LinkImpairments is the request body of a link, without BToA the link is impaired symmetrically
+type LinkImpairments struct {
+	AToB scenario.Impairments  `json:"a_to_b"`
+	BToA *scenario.Impairments `json:"b_to_a,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type DefaultServer struct {
+	log          *logrus.Entry
+	config       config.Config
+	helper       helpers.Helper
+	mapper       naming.Mapper
+	applier      scenario.Applier
+	linkManager  link.Manager
+	newViewer    func(node string) (impairments.Viewer, error)
+	newValidator func() (topology.Validator, error)
+	token        string
+	server       *http.Server
+	// linkMutex serializes the link changes, which apply the impairments and store the link in two steps,
+	// single interfaces are serialized by the applier and the config guards its own accessors
+	linkMutex sync.Mutex
+}
+
+// NewDefaultServer serves the API on address, with a token every request has to send it as bearer token
+func NewDefaultServer(config config.Config, helper helpers.Helper, mapper naming.Mapper, address, token string) *DefaultServer {
+	applier := scenario.NewDefaultApplier(config, helper, mapper, false)
+	server := &DefaultServer{
+		log:         logging.DefaultLogger.WithField("subsystem", subsystem),
+		config:      config,
+		helper:      helper,
+		mapper:      mapper,
+		applier:     applier,
+		linkManager: link.NewDefaultManager(config, mapper, applier),
+		newViewer: func(node string) (impairments.Viewer, error) {
+			showCommand, err := command.NewShowCommand(config.GetValue(command.BackendKey), node, config.GetValue(helper.GetDefaultClabNameKey()))
+			if err != nil {
+				return nil, err
+			}
+			return impairments.NewDefaultViewer(config, node, helper, showCommand), nil
+		},
+		newValidator: func() (topology.Validator, error) {
+			importedTopology, err := topology.Load(config, helper)
+			if err != nil {
+				return nil, err
+			}
+			return topology.NewDefaultValidator(importedTopology), nil
+		},
+		token: token,
+	}
+	server.server = &http.Server{Addr: address, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	return server
+}
+
+func (server *DefaultServer) writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	if err := json.NewEncoder(writer).Encode(value); err != nil {
+		server.log.Debugf("Error writing response: %v\n", err)
+	}
+}
+
+func (server *DefaultServer) writeError(writer http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errInvalidRequest):
+		status = http.StatusBadRequest
+	case errors.Is(err, errNotFound):
+		status = http.StatusNotFound
+	}
+	server.writeJSON(writer, status, errorResponse{Error: err.Error()})
+}
+
+func (server *DefaultServer) isAuthorized(request *http.Request) bool {
+	if server.token == "" {
+		return true
+	}
+	token, found := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(server.token)) == 1
+}
+
+// splitPath returns the unescaped segments after the API prefix, interface names like GigabitEthernet0%2F0%2F0%2F0 are escaped
+func splitPath(request *http.Request) ([]string, error) {
+	path, found := strings.CutPrefix(request.URL.EscapedPath(), apiPrefix)
+	if !found {
+		return nil, errNotFound
+	}
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidRequest, err)
+		}
+		segments[i] = unescaped
+	}
+	return segments, nil
+}
+
+func (server *DefaultServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !server.isAuthorized(request) {
+		server.writeJSON(writer, http.StatusUnauthorized, errorResponse{Error: "Missing or invalid bearer token"})
+		return
+	}
+	segments, err := splitPath(request)
+	if err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	server.log.Debugf("%s %s\n", request.Method, request.URL.Path)
+	route := fmt.Sprintf("%s %s", request.Method, segments[0])
+	switch {
+	case route == "GET nodes" && len(segments) == 1:
+		server.listNodes(writer)
+	case route == "GET nodes" && len(segments) == 3 && segments[2] == "interfaces":
+		server.listInterfaces(writer, segments[1])
+	case route == "GET nodes" && len(segments) == 4 && segments[2] == "interfaces":
+		server.getInterface(writer, segments[1], segments[3])
+	case route == "PUT nodes" && len(segments) == 4 && segments[2] == "interfaces":
+		server.setInterface(writer, request, segments[1], segments[3])
+	case route == "DELETE nodes" && len(segments) == 4 && segments[2] == "interfaces":
+		server.deleteInterface(writer, segments[1], segments[3])
+	case route == "GET links" && len(segments) == 1:
+		server.listLinks(writer)
+	case route == "PUT links" && len(segments) == 3:
+		server.setLink(writer, request, segments[1], segments[2])
+	case route == "DELETE links" && len(segments) == 3:
+		server.deleteLink(writer, segments[1], segments[2])
+	default:
+		server.writeError(writer, fmt.Errorf("%w: %s %s", errNotFound, request.Method, request.URL.Path))
+	}
+}
+
+func (server *DefaultServer) getInterfaceName(name string) string {
+	clabName, err := server.mapper.ToClabName(name)
+	if err != nil {
+		return name
+	}
+	return clabName
+}
+
+// validate checks the node and the interface against the imported topology, an empty interface only checks the node
+func (server *DefaultServer) validate(node, interface_ string) error {
+	validator, err := server.newValidator()
+	if err != nil {
+		return err
+	}
+	if interface_ == "" {
+		err = validator.ValidateNode(node)
+	} else {
+		err = validator.ValidateInterface(node, interface_)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	return nil
+}
+
+func decodeBody(request *http.Request, value interface{}) error {
+	decoder := json.NewDecoder(request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	return nil
+}
+
+func (server *DefaultServer) listNodes(writer http.ResponseWriter) {
+	nodes := make(map[string]interface{})
+	if err := server.config.Unmarshal(nodesKey, &nodes); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	server.writeJSON(writer, http.StatusOK, names)
+}
+
+func (server *DefaultServer) getRecords(node string) ([]impairments.Record, error) {
+	if err := server.validate(node, ""); err != nil {
+		return nil, err
+	}
+	viewer, err := server.newViewer(node)
+	if err != nil {
+		return nil, err
+	}
+	return viewer.GetImpairments()
+}
+
+func (server *DefaultServer) listInterfaces(writer http.ResponseWriter, node string) {
+	records, err := server.getRecords(node)
+	if err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	if records == nil {
+		records = []impairments.Record{}
+	}
+	server.writeJSON(writer, http.StatusOK, records)
+}
+
+func (server *DefaultServer) getInterface(writer http.ResponseWriter, node, name string) {
+	records, err := server.getRecords(node)
+	if err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	interface_ := server.getInterfaceName(name)
+	for _, record := range records {
+		if record.Interface == interface_ {
+			server.writeJSON(writer, http.StatusOK, record)
+			return
+		}
+	}
+	server.writeError(writer, fmt.Errorf("%w: interface %s on node %s", errNotFound, interface_, node))
+}
+
+// apply sets the impairments in one transaction of the applier, a failed interface is restored to the previous impairments
+func (server *DefaultServer) apply(node, name string, values scenario.Impairments) error {
+	if err := values.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	interface_ := server.getInterfaceName(name)
+	if err := server.validate(node, interface_); err != nil {
+		return err
+	}
+	_, err := server.applier.Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{node: {interface_: values}}})
+	return err
+}
+
+func (server *DefaultServer) setInterface(writer http.ResponseWriter, request *http.Request, node, name string) {
+	values := scenario.Impairments{}
+	if err := decodeBody(request, &values); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	if err := server.apply(node, name, values); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	server.log.Infof("Set impairments of %s on node %s\n", name, node)
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (server *DefaultServer) deleteInterface(writer http.ResponseWriter, node, name string) {
+	if err := server.apply(node, name, scenario.Impairments{}); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	server.log.Infof("Deleted impairments of %s on node %s\n", name, node)
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (server *DefaultServer) listLinks(writer http.ResponseWriter) {
+	stored := make(map[string]link.Link)
+	if err := server.config.Unmarshal(linksKey, &stored); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	keys := make([]string, 0, len(stored))
+	for key := range stored {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	links := make([]link.Link, 0, len(keys))
+	for _, key := range keys {
+		links = append(links, stored[key])
+	}
+	server.writeJSON(writer, http.StatusOK, links)
+}
+
+// getLink parses and validates the node:interface endpoints of the path
+func (server *DefaultServer) getLink(a, b string) (link.Link, error) {
+	endpointA, err := link.ParseEndpoint(a)
+	if err != nil {
+		return link.Link{}, fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	endpointB, err := link.ParseEndpoint(b)
+	if err != nil {
+		return link.Link{}, fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	endpointA.Interface = server.getInterfaceName(endpointA.Interface)
+	endpointB.Interface = server.getInterfaceName(endpointB.Interface)
+	validator, err := server.newValidator()
+	if err != nil {
+		return link.Link{}, err
+	}
+	linkToValidate := link.Link{A: endpointA, B: endpointB}
+	if err := validator.ValidateLink(linkToValidate); err != nil {
+		return link.Link{}, fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+	return linkToValidate, nil
+}
+
+func (server *DefaultServer) setLink(writer http.ResponseWriter, request *http.Request, a, b string) {
+	values := LinkImpairments{}
+	if err := decodeBody(request, &values); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	if values.BToA == nil {
+		values.BToA = &values.AToB
+	}
+	for _, direction := range []scenario.Impairments{values.AToB, *values.BToA} {
+		if err := direction.Validate(); err != nil {
+			server.writeError(writer, fmt.Errorf("%w: %v", errInvalidRequest, err))
+			return
+		}
+	}
+	linkToSet, err := server.getLink(a, b)
+	if err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	server.linkMutex.Lock()
+	defer server.linkMutex.Unlock()
+	if err := server.linkManager.Set(linkToSet, values.AToB, *values.BToA); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (server *DefaultServer) deleteLink(writer http.ResponseWriter, a, b string) {
+	linkToDelete, err := server.getLink(a, b)
+	if err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	server.linkMutex.Lock()
+	defer server.linkMutex.Unlock()
+	if err := server.linkManager.Delete(linkToDelete); err != nil {
+		server.writeError(writer, err)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// Start serves the API until Stop is called
+func (server *DefaultServer) Start() {
+	server.log.Infof("Serving API on %s\n", server.server.Addr)
+	if err := server.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		server.log.Errorf("Error serving API: %v\n", err)
+	}
+}
+
+func (server *DefaultServer) Stop() {
+	server.log.Infoln("Stopping API")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.server.Shutdown(ctx); err != nil {
+		server.log.Errorf("Error stopping API: %v\n", err)
+	}
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var defaultMapper, _ = naming.NewDefaultMapper(nil)
+
+type staticViewer struct {
+	records []impairments.Record
+	err     error
+}
+
+func (viewer staticViewer) GetImpairments() ([]impairments.Record, error) {
+	return viewer.records, viewer.err
+}
+
+type mocks struct {
+	config      *config.MockConfig
+	applier     *scenario.MockApplier
+	linkManager *link.MockManager
+}
+
+func newTestServer(ctrl *gomock.Controller, token string, viewer impairments.Viewer) (*DefaultServer, mocks) {
+	m := mocks{
+		config:      config.NewMockConfig(ctrl),
+		applier:     scenario.NewMockApplier(ctrl),
+		linkManager: link.NewMockManager(ctrl),
+	}
+	server := NewDefaultServer(m.config, helpers.NewMockHelper(ctrl), defaultMapper, "127.0.0.1:0", token)
+	server.applier = m.applier
+	server.linkManager = m.linkManager
+	server.newViewer = func(node string) (impairments.Viewer, error) {
+		return viewer, nil
+	}
+	server.newValidator = func() (topology.Validator, error) {
+		return topology.NewDefaultValidator(&topology.Topology{
+			Nodes: []string{"XR-1", "XR-2"},
+			Links: []link.Link{{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}}},
+		}), nil
+	}
+	return server, m
+}
+
+func TestNewDefaultServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	assert.NotNil(t, NewDefaultServer(config.NewMockConfig(ctrl), helpers.NewMockHelper(ctrl), defaultMapper, ":8080", ""))
+}
+
+func TestDefaultServer_ServeHTTP(t *testing.T) {
+	records := []impairments.Record{
+		{Interface: "Gi0-0-0-0", Configured: &command.Impairments{Delay: 10}, Live: &command.Impairments{Delay: 10}, InSync: true},
+	}
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		token      string
+		header     string
+		viewer     staticViewer
+		setup      func(m mocks)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "Test list nodes",
+			method: http.MethodGet,
+			path:   "/api/v1/nodes",
+			setup: func(m mocks) {
+				m.config.EXPECT().Unmarshal("nodes", gomock.Any()).DoAndReturn(func(key string, value interface{}) error {
+					nodes := value.(*map[string]interface{})
+					(*nodes)["XR-2"] = nil
+					(*nodes)["XR-1"] = nil
+					return nil
+				})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `["XR-1","XR-2"]`,
+		},
+		{
+			name:       "Test list interfaces",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes/XR-1/interfaces",
+			viewer:     staticViewer{records: records},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"interface":"Gi0-0-0-0","configured":{"delay":10,"jitter":0,"packet_loss":0,"rate":0},"live":{"delay":10,"jitter":0,"packet_loss":0,"rate":0},"in_sync":true}]`,
+		},
+		{
+			name:       "Test list interfaces of unknown node",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes/XR-3/interfaces",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Test get interface with escaped telemetry name",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes/XR-1/interfaces/GigabitEthernet0%2F0%2F0%2F0",
+			viewer:     staticViewer{records: records},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"interface":"Gi0-0-0-0","configured":{"delay":10,"jitter":0,"packet_loss":0,"rate":0},"live":{"delay":10,"jitter":0,"packet_loss":0,"rate":0},"in_sync":true}`,
+		},
+		{
+			name:       "Test get interface without impairments",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-1",
+			viewer:     staticViewer{records: records},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Test get interface fails",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-0",
+			viewer:     staticViewer{err: fmt.Errorf("container not running")},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"container not running"}`,
+		},
+		{
+			name:   "Test set interface",
+			method: http.MethodPut,
+			path:   "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-0",
+			body:   `{"delay":10,"loss":1.5}`,
+			setup: func(m mocks) {
+				m.applier.EXPECT().Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+					"XR-1": {"Gi0-0-0-0": {Delay: 10, Loss: 1.5}},
+				}}).Return(nil, nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Test set interface with unknown field",
+			method:     http.MethodPut,
+			path:       "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-0",
+			body:       `{"latency":10}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Test set interface with invalid loss",
+			method:     http.MethodPut,
+			path:       "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-0",
+			body:       `{"loss":101}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Test set interface fails",
+			method: http.MethodPut,
+			path:   "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-0",
+			body:   `{"delay":10}`,
+			setup: func(m mocks) {
+				m.applier.EXPECT().Apply(gomock.Any()).Return(nil, fmt.Errorf("tc failed, all changes are rolled back"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "Test delete interface",
+			method: http.MethodDelete,
+			path:   "/api/v1/nodes/XR-1/interfaces/Gi0-0-0-0",
+			setup: func(m mocks) {
+				m.applier.EXPECT().Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+					"XR-1": {"Gi0-0-0-0": {}},
+				}}).Return(nil, nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Test list links",
+			method: http.MethodGet,
+			path:   "/api/v1/links",
+			setup: func(m mocks) {
+				m.config.EXPECT().Unmarshal("links", gomock.Any()).DoAndReturn(func(key string, value interface{}) error {
+					links := value.(*map[string]link.Link)
+					(*links)["XR-1:Gi0-0-0-0<->XR-2:Gi0-0-0-0"] = link.Link{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}}
+					return nil
+				})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"a":{"node":"XR-1","interface":"Gi0-0-0-0"},"b":{"node":"XR-2","interface":"Gi0-0-0-0"}}]`,
+		},
+		{
+			name:   "Test set symmetric link",
+			method: http.MethodPut,
+			path:   "/api/v1/links/XR-1:Gi0-0-0-0/XR-2:GigabitEthernet0%2F0%2F0%2F0",
+			body:   `{"a_to_b":{"delay":5}}`,
+			setup: func(m mocks) {
+				m.linkManager.EXPECT().Set(
+					link.Link{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}},
+					scenario.Impairments{Delay: 5}, scenario.Impairments{Delay: 5},
+				).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Test set asymmetric link",
+			method: http.MethodPut,
+			path:   "/api/v1/links/XR-1:Gi0-0-0-0/XR-2:Gi0-0-0-0",
+			body:   `{"a_to_b":{"delay":5},"b_to_a":{"rate":1000}}`,
+			setup: func(m mocks) {
+				m.linkManager.EXPECT().Set(gomock.Any(), scenario.Impairments{Delay: 5}, scenario.Impairments{Rate: 1000}).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Test set link which is not in the topology",
+			method:     http.MethodPut,
+			path:       "/api/v1/links/XR-1:Gi0-0-0-1/XR-2:Gi0-0-0-0",
+			body:       `{"a_to_b":{"delay":5}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Test delete link",
+			method: http.MethodDelete,
+			path:   "/api/v1/links/XR-1:Gi0-0-0-0/XR-2:Gi0-0-0-0",
+			setup: func(m mocks) {
+				m.linkManager.EXPECT().Delete(gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Test delete link with invalid endpoint",
+			method:     http.MethodDelete,
+			path:       "/api/v1/links/XR-1/XR-2:Gi0-0-0-0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Test unknown route",
+			method:     http.MethodPost,
+			path:       "/api/v1/nodes",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Test path outside of the api",
+			method:     http.MethodGet,
+			path:       "/metrics",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Test missing token",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes",
+			token:      "secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Test wrong token",
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes",
+			token:      "secret",
+			header:     "Bearer wrong",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Test valid token",
+			method: http.MethodGet,
+			path:   "/api/v1/nodes",
+			token:  "secret",
+			header: "Bearer secret",
+			setup: func(m mocks) {
+				m.config.EXPECT().Unmarshal("nodes", gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			server, m := newTestServer(ctrl, tt.token, tt.viewer)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+			}
+			if recorder.Code >= http.StatusBadRequest {
+				assert.Contains(t, recorder.Body.String(), `"error"`)
+			}
+		})
+	}
+}
+
+func TestDefaultServer_StartStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server, _ := newTestServer(ctrl, "", staticViewer{})
+	done := make(chan struct{})
+	go func() {
+		server.Start()
+		close(done)
+	}()
+	server.Stop()
+	<-done
+}
@@ -0,0 +1,41 @@
+package backoff
+
+import "time"
+
+// Backoff calculates exponentially growing wait times between retries
+type Backoff interface {
+	Next() time.Duration
+	Reset()
+}
+
+type ExponentialBackoff struct {
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	multiplier      float64
+	currentInterval time.Duration
+}
+
+func NewExponentialBackoff(initialInterval, maxInterval time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		initialInterval: initialInterval,
+		maxInterval:     maxInterval,
+		multiplier:      2,
+		currentInterval: initialInterval,
+	}
+}
+
+// Next returns the time to wait before the next retry and increases the interval up to the maximum
+func (backoff *ExponentialBackoff) Next() time.Duration {
+	interval := backoff.currentInterval
+	next := time.Duration(float64(backoff.currentInterval) * backoff.multiplier)
+	if next > backoff.maxInterval {
+		next = backoff.maxInterval
+	}
+	backoff.currentInterval = next
+	return interval
+}
+
+// Reset starts again with the initial interval, e.g. after a successful connection
+func (backoff *ExponentialBackoff) Reset() {
+	backoff.currentInterval = backoff.initialInterval
+}
@@ -0,0 +1,72 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExponentialBackoff(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test creating exponential backoff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotNil(t, NewExponentialBackoff(time.Second, time.Minute))
+		})
+	}
+}
+
+func TestExponentialBackoff_Next(t *testing.T) {
+	tests := []struct {
+		name            string
+		initialInterval time.Duration
+		maxInterval     time.Duration
+		want            []time.Duration
+	}{
+		{
+			name:            "Test doubling intervals",
+			initialInterval: time.Second,
+			maxInterval:     time.Minute,
+			want:            []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:            "Test intervals are capped at maximum",
+			initialInterval: time.Second,
+			maxInterval:     3 * time.Second,
+			want:            []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff := NewExponentialBackoff(tt.initialInterval, tt.maxInterval)
+			for _, want := range tt.want {
+				assert.Equal(t, want, backoff.Next())
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff_Reset(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test reset to initial interval",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff := NewExponentialBackoff(time.Second, time.Minute)
+			backoff.Next()
+			backoff.Next()
+			backoff.Reset()
+			assert.Equal(t, time.Second, backoff.Next())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package command
+
+import "fmt"
+
+const (
+	// BackendKey selects the impairment backend in the config file
+	BackendKey          = "impairment-backend"
+	ContainerlabBackend = "containerlab"
+	NetlinkBackend      = "netlink"
+)
+
+// NewSetCommand creates the set command of the given backend, containerlab is used if no backend is configured
+func NewSetCommand(backend, node, interface_, clabName string) (SetCommand, error) {
+	switch backend {
+	case "", ContainerlabBackend:
+		return NewDefaultSetCommand(node, interface_, clabName), nil
+	case NetlinkBackend:
+		return newNetlinkSetCommand(node, interface_, clabName)
+	default:
+		return nil, fmt.Errorf("Unknown impairment backend %q, use %s or %s", backend, ContainerlabBackend, NetlinkBackend)
+	}
+}
+
+// NewShowCommand creates the show command of the given backend, containerlab is used if no backend is configured
+func NewShowCommand(backend, node, clabName string) (ShowCommand, error) {
+	switch backend {
+	case "", ContainerlabBackend:
+		return NewDefaultShowCommand(node, clabName), nil
+	case NetlinkBackend:
+		return newNetlinkShowCommand(node, clabName)
+	default:
+		return nil, fmt.Errorf("Unknown impairment backend %q, use %s or %s", backend, ContainerlabBackend, NetlinkBackend)
+	}
+}
+
+// getContainerName returns the name containerlab gives the node, labs deployed without prefix use the node name
+func getContainerName(node, clabName string) string {
+	if clabName == "" {
+		return node
+	}
+	return clabName + "-" + node
+}
+
+// getNamespacePath returns the network namespace link containerlab creates for each node
+func getNamespacePath(node, clabName string) string {
+	return "/var/run/netns/" + getContainerName(node, clabName)
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSetCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		want    SetCommand
+		wantErr bool
+	}{
+		{
+			name:    "Test without backend",
+			backend: "",
+			want:    NewDefaultSetCommand("XR-1", "Gi0-0-0-0", "clab-hawkv6"),
+		},
+		{
+			name:    "Test with containerlab backend",
+			backend: ContainerlabBackend,
+			want:    NewDefaultSetCommand("XR-1", "Gi0-0-0-0", "clab-hawkv6"),
+		},
+		{
+			name:    "Test with unknown backend",
+			backend: "unknown",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSetCommand(tt.backend, "XR-1", "Gi0-0-0-0", "clab-hawkv6")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewShowCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		want    ShowCommand
+		wantErr bool
+	}{
+		{
+			name:    "Test without backend",
+			backend: "",
+			want:    NewDefaultShowCommand("XR-1", "clab-hawkv6"),
+		},
+		{
+			name:    "Test with containerlab backend",
+			backend: ContainerlabBackend,
+			want:    NewDefaultShowCommand("XR-1", "clab-hawkv6"),
+		},
+		{
+			name:    "Test with unknown backend",
+			backend: "unknown",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewShowCommand(tt.backend, "XR-1", "clab-hawkv6")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetContainerName(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     string
+		clabName string
+		want     string
+	}{
+		{
+			name:     "Test container name with lab prefix",
+			node:     "XR-1",
+			clabName: "clab-hawkv6",
+			want:     "clab-hawkv6-XR-1",
+		},
+		{
+			name:     "Test container name of lab without prefix",
+			node:     "XR-1",
+			clabName: "",
+			want:     "XR-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getContainerName(tt.node, tt.clabName))
+			assert.Equal(t, "/var/run/netns/"+tt.want, getNamespacePath(tt.node, tt.clabName))
+		})
+	}
+}
@@ -20,6 +20,16 @@ type BaseCommand struct {
 }
 
 func (command *BaseCommand) ExecuteCommand(cmd *exec.Cmd) error {
+	out, err := command.ExecuteCommandOutput(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", out)
+	return nil
+}
+
+// ExecuteCommandOutput returns stdout of the command instead of printing it
+func (command *BaseCommand) ExecuteCommandOutput(cmd *exec.Cmd) ([]byte, error) {
 	command.log.Debugf("Execute Command: %s\n", cmd)
 	var out bytes.Buffer
 	var stderr bytes.Buffer
@@ -28,11 +38,10 @@ func (command *BaseCommand) ExecuteCommand(cmd *exec.Cmd) error {
 	err := cmd.Run()
 	if err != nil {
 		if stderr.String() != "" {
-			return fmt.Errorf("Aborting... Following Error happened: %v", stderr.String())
+			return nil, fmt.Errorf("Aborting... Following Error happened: %v", stderr.String())
 		} else {
-			return fmt.Errorf("Aborting... Following Error happened: %v", err)
+			return nil, fmt.Errorf("Aborting... Following Error happened: %v", err)
 		}
 	}
-	fmt.Printf("%s\n", out.String())
-	return nil
+	return out.Bytes(), nil
 }
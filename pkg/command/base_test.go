@@ -60,3 +60,35 @@ func TestBaseCommand_ExecuteCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseCommand_ExecuteCommandOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     *exec.Cmd
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "Test valid command returns stdout",
+			cmd:  exec.Command("echo", "test"),
+			want: []byte("test\n"),
+		},
+		{
+			name:    "Test failing command returns stderr",
+			cmd:     exec.Command("sh", "-c", "echo failed >&2; exit 1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := &BaseCommand{log: logging.DefaultLogger.WithField("subsystem", "command")}
+			got, err := command.ExecuteCommandOutput(tt.cmd)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "failed")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+	"unsafe"
+)
+
+// netlink attributes and structures of the netem qdisc, see linux/pkt_sched.h
+const (
+	tcaKind    = 1
+	tcaOptions = 2
+
+	tcaNetemRate      = 6
+	tcaNetemRate64    = 8
+	tcaNetemLatency64 = 10
+	tcaNetemJitter64  = 11
+
+	tcHandleRoot      = 0xFFFFFFFF
+	sizeofTcMsg       = 20
+	sizeofNetemQopt   = 24
+	sizeofNetemRate   = 16
+	sizeofRtAttr      = 4
+	defaultNetemLimit = 1000
+)
+
+// nativeEndian is the byte order used by netlink, binary.NativeEndian requires go 1.21
+var nativeEndian binary.ByteOrder = func() binary.ByteOrder {
+	value := uint16(1)
+	if *(*byte)(unsafe.Pointer(&value)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+func alignAttr(length int) int {
+	return (length + 3) &^ 3
+}
+
+func appendAttr(buffer []byte, attrType uint16, payload []byte) []byte {
+	attr := make([]byte, alignAttr(sizeofRtAttr+len(payload)))
+	nativeEndian.PutUint16(attr[0:2], uint16(sizeofRtAttr+len(payload)))
+	nativeEndian.PutUint16(attr[2:4], attrType)
+	copy(attr[sizeofRtAttr:], payload)
+	return append(buffer, attr...)
+}
+
+func uint64Bytes(value uint64) []byte {
+	payload := make([]byte, 8)
+	nativeEndian.PutUint64(payload, value)
+	return payload
+}
+
+// lossToProbability converts a percentage to the 32 bit probability used by netem
+func lossToProbability(loss float64) uint32 {
+	if loss >= 100 {
+		return math.MaxUint32
+	}
+	return uint32(loss / 100 * math.MaxUint32)
+}
+
+func probabilityToLoss(probability uint32) float64 {
+	return float64(probability) / math.MaxUint32 * 100
+}
+
+// encode returns the TCA_OPTIONS payload, netem expects the tc_netem_qopt struct followed by its attributes
+func (options Impairments) encode() []byte {
+	buffer := make([]byte, sizeofNetemQopt)
+	nativeEndian.PutUint32(buffer[4:8], defaultNetemLimit)
+	nativeEndian.PutUint32(buffer[8:12], lossToProbability(options.Loss))
+	// the 64 bit attributes take precedence over the tick based latency and jitter of tc_netem_qopt
+	buffer = appendAttr(buffer, tcaNetemLatency64, uint64Bytes(options.Delay*uint64(time.Millisecond)))
+	buffer = appendAttr(buffer, tcaNetemJitter64, uint64Bytes(options.Jitter*uint64(time.Millisecond)))
+	if options.Rate != 0 {
+		bytesPerSecond := options.Rate * 1000 / 8
+		rate := make([]byte, sizeofNetemRate)
+		nativeEndian.PutUint32(rate[0:4], uint32(math.Min(float64(bytesPerSecond), math.MaxUint32)))
+		buffer = appendAttr(buffer, tcaNetemRate, rate)
+		if bytesPerSecond >= math.MaxUint32 {
+			buffer = appendAttr(buffer, tcaNetemRate64, uint64Bytes(bytesPerSecond))
+		}
+	}
+	return buffer
+}
+
+// parseAttrs splits netlink attributes by type
+func parseAttrs(buffer []byte) (map[uint16][]byte, error) {
+	attrs := make(map[uint16][]byte)
+	for len(buffer) >= sizeofRtAttr {
+		length := int(nativeEndian.Uint16(buffer[0:2]))
+		if length < sizeofRtAttr || length > len(buffer) {
+			return nil, errors.New("Invalid netlink attribute length")
+		}
+		attrs[nativeEndian.Uint16(buffer[2:4])] = buffer[sizeofRtAttr:length]
+		if alignAttr(length) > len(buffer) {
+			break
+		}
+		buffer = buffer[alignAttr(length):]
+	}
+	return attrs, nil
+}
+
+func decodeNetemOptions(buffer []byte) (Impairments, error) {
+	if len(buffer) < sizeofNetemQopt {
+		return Impairments{}, errors.New("Netem options too short")
+	}
+	options := Impairments{Loss: probabilityToLoss(nativeEndian.Uint32(buffer[8:12]))}
+	attrs, err := parseAttrs(buffer[alignAttr(sizeofNetemQopt):])
+	if err != nil {
+		return Impairments{}, err
+	}
+	if latency, ok := attrs[tcaNetemLatency64]; ok && len(latency) == 8 {
+		options.Delay = nativeEndian.Uint64(latency) / uint64(time.Millisecond)
+	}
+	if jitter, ok := attrs[tcaNetemJitter64]; ok && len(jitter) == 8 {
+		options.Jitter = nativeEndian.Uint64(jitter) / uint64(time.Millisecond)
+	}
+	if rate, ok := attrs[tcaNetemRate]; ok && len(rate) >= 4 {
+		options.Rate = uint64(nativeEndian.Uint32(rate[0:4])) * 8 / 1000
+	}
+	if rate64, ok := attrs[tcaNetemRate64]; ok && len(rate64) == 8 {
+		options.Rate = nativeEndian.Uint64(rate64) * 8 / 1000
+	}
+	return options, nil
+}
+
+// encodeTcMsg returns the tcmsg header addressing the root qdisc of the interface
+func encodeTcMsg(interfaceIndex int) []byte {
+	buffer := make([]byte, sizeofTcMsg)
+	nativeEndian.PutUint32(buffer[4:8], uint32(interfaceIndex))
+	nativeEndian.PutUint32(buffer[12:16], tcHandleRoot)
+	return buffer
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetemOptions_encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Impairments
+	}{
+		{
+			name:    "Test delay and jitter",
+			options: Impairments{Delay: 10, Jitter: 2},
+		},
+		{
+			name:    "Test loss",
+			options: Impairments{Loss: 5},
+		},
+		{
+			name:    "Test rate",
+			options: Impairments{Rate: 100000},
+		},
+		{
+			name:    "Test rate above 32 bit bytes per second",
+			options: Impairments{Rate: 400000000},
+		},
+		{
+			name:    "Test all impairments",
+			options: Impairments{Delay: 100, Jitter: 10, Loss: 0.5, Rate: 1000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.options.encode()
+			assert.Equal(t, uint32(defaultNetemLimit), nativeEndian.Uint32(encoded[4:8]))
+			attrs, err := parseAttrs(encoded[sizeofNetemQopt:])
+			assert.NoError(t, err)
+			_, hasRate64 := attrs[tcaNetemRate64]
+			assert.Equal(t, tt.options.Rate*1000/8 >= math.MaxUint32, hasRate64)
+			decoded, err := decodeNetemOptions(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.options.Delay, decoded.Delay)
+			assert.Equal(t, tt.options.Jitter, decoded.Jitter)
+			assert.Equal(t, tt.options.Rate, decoded.Rate)
+			assert.InDelta(t, tt.options.Loss, decoded.Loss, 0.0001)
+		})
+	}
+}
+
+func TestDecodeNetemOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		buffer  []byte
+		wantErr bool
+	}{
+		{
+			name:    "Test too short options",
+			buffer:  make([]byte, sizeofNetemQopt-1),
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid attribute length",
+			buffer:  append(make([]byte, sizeofNetemQopt), 0xff, 0x00, 0x0a, 0x00),
+			wantErr: true,
+		},
+		{
+			name:   "Test options without attributes",
+			buffer: make([]byte, sizeofNetemQopt),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeNetemOptions(tt.buffer)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLossToProbability(t *testing.T) {
+	tests := []struct {
+		name string
+		loss float64
+		want uint32
+	}{
+		{
+			name: "Test no loss",
+			loss: 0,
+			want: 0,
+		},
+		{
+			name: "Test full loss",
+			loss: 100,
+			want: math.MaxUint32,
+		},
+		{
+			name: "Test loss above 100%",
+			loss: 120,
+			want: math.MaxUint32,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, lossToProbability(tt.loss))
+		})
+	}
+}
+
+func TestEncodeTcMsg(t *testing.T) {
+	tcMsg := encodeTcMsg(5)
+	assert.Len(t, tcMsg, sizeofTcMsg)
+	assert.Equal(t, uint32(5), nativeEndian.Uint32(tcMsg[4:8]))
+	assert.Equal(t, uint32(tcHandleRoot), nativeEndian.Uint32(tcMsg[12:16]))
+}
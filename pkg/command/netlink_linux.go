@@ -0,0 +1,261 @@
+//go:build linux
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// runInNamespace executes fn on a dedicated OS thread switched into the network namespace
+func runInNamespace(namespacePath string, fn func() error) error {
+	target, err := os.Open(namespacePath)
+	if err != nil {
+		return fmt.Errorf("Unable to open network namespace: %v", err)
+	}
+	defer target.Close()
+	result := make(chan error, 1)
+	go func() {
+		// the thread is never unlocked, so it is terminated with the goroutine instead of being reused in the wrong namespace
+		runtime.LockOSThread()
+		if err := unix.Setns(int(target.Fd()), unix.CLONE_NEWNET); err != nil {
+			result <- fmt.Errorf("Unable to enter network namespace %s: %v", namespacePath, err)
+			return
+		}
+		result <- fn()
+	}()
+	return <-result
+}
+
+type rtnetlinkSocket struct {
+	fd       int
+	sequence uint32
+}
+
+func openRtnetlink() (*rtnetlinkSocket, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open netlink socket: %v", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("Unable to bind netlink socket: %v", err)
+	}
+	return &rtnetlinkSocket{fd: fd}, nil
+}
+
+func (socket *rtnetlinkSocket) Close() error {
+	return unix.Close(socket.fd)
+}
+
+// request sends a message and returns the payloads of the replies, it waits for the ack or the end of the dump
+func (socket *rtnetlinkSocket) request(messageType uint16, flags uint16, payload []byte) ([][]byte, error) {
+	socket.sequence++
+	message := make([]byte, unix.SizeofNlMsghdr, unix.SizeofNlMsghdr+len(payload))
+	nativeEndian.PutUint32(message[0:4], uint32(unix.SizeofNlMsghdr+len(payload)))
+	nativeEndian.PutUint16(message[4:6], messageType)
+	nativeEndian.PutUint16(message[6:8], flags|unix.NLM_F_REQUEST|unix.NLM_F_ACK)
+	nativeEndian.PutUint32(message[8:12], socket.sequence)
+	message = append(message, payload...)
+	if err := unix.Sendto(socket.fd, message, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("Unable to send netlink message: %v", err)
+	}
+	var replies [][]byte
+	buffer := make([]byte, 65536)
+	for {
+		n, _, err := unix.Recvfrom(socket.fd, buffer, 0)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to receive netlink message: %v", err)
+		}
+		received := buffer[:n]
+		for len(received) >= unix.SizeofNlMsghdr {
+			length := int(nativeEndian.Uint32(received[0:4]))
+			if length < unix.SizeofNlMsghdr || length > len(received) {
+				return nil, errors.New("Invalid netlink message length")
+			}
+			if nativeEndian.Uint32(received[8:12]) == socket.sequence {
+				switch nativeEndian.Uint16(received[4:6]) {
+				case unix.NLMSG_DONE:
+					return replies, nil
+				case unix.NLMSG_ERROR:
+					if length < unix.SizeofNlMsghdr+4 {
+						return nil, errors.New("Invalid netlink error message")
+					}
+					if errno := int32(nativeEndian.Uint32(received[16:20])); errno != 0 {
+						return nil, unix.Errno(-errno)
+					}
+					if flags&unix.NLM_F_DUMP == 0 {
+						return replies, nil
+					}
+				default:
+					reply := make([]byte, length-unix.SizeofNlMsghdr)
+					copy(reply, received[unix.SizeofNlMsghdr:length])
+					replies = append(replies, reply)
+				}
+			}
+			if alignAttr(length) > len(received) {
+				break
+			}
+			received = received[alignAttr(length):]
+		}
+	}
+}
+
+// NetlinkSetCommand configures the netem qdisc directly via netlink in the network namespace of the node
+type NetlinkSetCommand struct {
+	log           *logrus.Entry
+	namespacePath string
+	interfaceName string
+	options       Impairments
+}
+
+func newNetlinkSetCommand(node, interface_, clabName string) (SetCommand, error) {
+	return NewNetlinkSetCommand(node, interface_, clabName), nil
+}
+
+func NewNetlinkSetCommand(node, interface_, clabName string) *NetlinkSetCommand {
+	return &NetlinkSetCommand{
+		log:           logging.DefaultLogger.WithField("subsystem", subsystem),
+		namespacePath: getNamespacePath(node, clabName),
+		interfaceName: interface_,
+	}
+}
+
+func (command *NetlinkSetCommand) AddDelay(delay uint64) {
+	command.options.Delay = delay
+}
+
+func (command *NetlinkSetCommand) AddJitter(jitter uint64) {
+	command.options.Jitter = jitter
+}
+
+func (command *NetlinkSetCommand) AddLoss(loss float64) {
+	command.options.Loss = loss
+}
+
+func (command *NetlinkSetCommand) AddRate(rate uint64) {
+	command.options.Rate = rate
+}
+
+func (command *NetlinkSetCommand) qdiscRequest(messageType uint16, flags uint16, options *Impairments) error {
+	return runInNamespace(command.namespacePath, func() error {
+		netInterface, err := net.InterfaceByName(command.interfaceName)
+		if err != nil {
+			return fmt.Errorf("Unable to find interface %s: %v", command.interfaceName, err)
+		}
+		socket, err := openRtnetlink()
+		if err != nil {
+			return err
+		}
+		defer socket.Close()
+		payload := encodeTcMsg(netInterface.Index)
+		if options != nil {
+			payload = appendAttr(payload, tcaKind, []byte("netem\x00"))
+			payload = appendAttr(payload, tcaOptions, options.encode())
+		}
+		_, err = socket.request(messageType, flags, payload)
+		return err
+	})
+}
+
+// ApplyImpairments replaces the root qdisc with netem, without impairments the qdisc is removed
+func (command *NetlinkSetCommand) ApplyImpairments() error {
+	if command.options.isEmpty() {
+		return command.DeleteImpairments()
+	}
+	command.log.Debugf("Set netem %+v on interface %s in %s\n", command.options, command.interfaceName, command.namespacePath)
+	if err := command.qdiscRequest(unix.RTM_NEWQDISC, unix.NLM_F_CREATE|unix.NLM_F_REPLACE, &command.options); err != nil {
+		return fmt.Errorf("Aborting... Following Error happened: %v", err)
+	}
+	return nil
+}
+
+func (command *NetlinkSetCommand) DeleteImpairments() error {
+	command.log.Debugf("Delete netem on interface %s in %s\n", command.interfaceName, command.namespacePath)
+	err := command.qdiscRequest(unix.RTM_DELQDISC, 0, nil)
+	// the kernel reports ENOENT or EINVAL if there is no qdisc to delete
+	if err != nil && !errors.Is(err, unix.ENOENT) && !errors.Is(err, unix.EINVAL) {
+		return fmt.Errorf("Aborting... Following Error happened: %v", err)
+	}
+	return nil
+}
+
+// NetlinkShowCommand reads the netem qdiscs directly via netlink from the network namespace of the node
+type NetlinkShowCommand struct {
+	log           *logrus.Entry
+	namespacePath string
+}
+
+func newNetlinkShowCommand(node, clabName string) (ShowCommand, error) {
+	return NewNetlinkShowCommand(node, clabName), nil
+}
+
+func NewNetlinkShowCommand(node, clabName string) *NetlinkShowCommand {
+	return &NetlinkShowCommand{
+		log:           logging.DefaultLogger.WithField("subsystem", subsystem),
+		namespacePath: getNamespacePath(node, clabName),
+	}
+}
+
+// parseNetemQdisc returns the interface index and options of a root netem qdisc, ok is false for other qdiscs
+func parseNetemQdisc(reply []byte) (int, *Impairments, bool, error) {
+	if len(reply) < sizeofTcMsg || nativeEndian.Uint32(reply[12:16]) != tcHandleRoot {
+		return 0, nil, false, nil
+	}
+	attrs, err := parseAttrs(reply[sizeofTcMsg:])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if string(attrs[tcaKind]) != "netem\x00" {
+		return 0, nil, false, nil
+	}
+	options, err := decodeNetemOptions(attrs[tcaOptions])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return int(int32(nativeEndian.Uint32(reply[4:8]))), &options, true, nil
+}
+
+func (command *NetlinkShowCommand) GetImpairments() ([]InterfaceImpairments, error) {
+	var impairments []InterfaceImpairments
+	err := runInNamespace(command.namespacePath, func() error {
+		socket, err := openRtnetlink()
+		if err != nil {
+			return err
+		}
+		defer socket.Close()
+		replies, err := socket.request(unix.RTM_GETQDISC, unix.NLM_F_DUMP, make([]byte, sizeofTcMsg))
+		if err != nil {
+			return err
+		}
+		qdiscs := make(map[int]*Impairments)
+		for _, reply := range replies {
+			index, options, ok, err := parseNetemQdisc(reply)
+			if err != nil {
+				return err
+			}
+			if ok {
+				qdiscs[index] = options
+			}
+		}
+		interfaces, err := net.Interfaces()
+		if err != nil {
+			return err
+		}
+		for _, netInterface := range interfaces {
+			impairments = append(impairments, InterfaceImpairments{Interface: netInterface.Name, Impairments: qdiscs[netInterface.Index]})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Aborting... Following Error happened: %v", err)
+	}
+	return impairments, nil
+}
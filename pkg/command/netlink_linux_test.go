@@ -0,0 +1,92 @@
+//go:build linux
+
+package command
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNetlinkSetCommand(t *testing.T) {
+	command := NewNetlinkSetCommand("XR-1", "Gi0-0-0-0", "clab-hawkv6")
+	command.AddDelay(10)
+	command.AddJitter(2)
+	command.AddLoss(5)
+	command.AddRate(100000)
+	assert.Equal(t, &NetlinkSetCommand{
+		log:           logging.DefaultLogger.WithField("subsystem", "command"),
+		namespacePath: "/var/run/netns/clab-hawkv6-XR-1",
+		interfaceName: "Gi0-0-0-0",
+		options:       Impairments{Delay: 10, Jitter: 2, Loss: 5, Rate: 100000},
+	}, command)
+}
+
+func TestNewNetlinkShowCommand(t *testing.T) {
+	assert.Equal(t, &NetlinkShowCommand{
+		log:           logging.DefaultLogger.WithField("subsystem", "command"),
+		namespacePath: "/var/run/netns/clab-hawkv6-XR-1",
+	}, NewNetlinkShowCommand("XR-1", "clab-hawkv6"))
+}
+
+func TestNetlinkCommand_missingNamespace(t *testing.T) {
+	setCommand := NewNetlinkSetCommand("non-existing-node", "Gi0-0-0-0", "non-existing-clab")
+	setCommand.AddDelay(10)
+	assert.Error(t, setCommand.ApplyImpairments())
+	assert.Error(t, setCommand.DeleteImpairments())
+	impairments, err := NewNetlinkShowCommand("non-existing-node", "non-existing-clab").GetImpairments()
+	assert.Error(t, err)
+	assert.Nil(t, impairments)
+}
+
+func TestRunInNamespace(t *testing.T) {
+	called := false
+	err := runInNamespace("/var/run/netns/non-existing", func() error {
+		called = true
+		return nil
+	})
+	assert.Error(t, err)
+	assert.False(t, called)
+	fnErr := errors.New("error")
+	assert.ErrorIs(t, runInNamespace("/proc/self/ns/net", func() error { return fnErr }), fnErr)
+}
+
+func TestParseNetemQdisc(t *testing.T) {
+	netemQdisc := appendAttr(appendAttr(encodeTcMsg(3), tcaKind, []byte("netem\x00")), tcaOptions, Impairments{Delay: 10}.encode())
+	tbfQdisc := appendAttr(encodeTcMsg(3), tcaKind, []byte("tbf\x00"))
+	childQdisc := appendAttr(make([]byte, sizeofTcMsg), tcaKind, []byte("netem\x00"))
+	tests := []struct {
+		name      string
+		reply     []byte
+		wantIndex int
+		wantOk    bool
+	}{
+		{
+			name:      "Test root netem qdisc",
+			reply:     netemQdisc,
+			wantIndex: 3,
+			wantOk:    true,
+		},
+		{
+			name:  "Test other qdisc kind",
+			reply: tbfQdisc,
+		},
+		{
+			name:  "Test non root qdisc",
+			reply: childQdisc,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, options, ok, err := parseNetemQdisc(tt.reply)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantIndex, index)
+			if tt.wantOk {
+				assert.Equal(t, uint64(10), options.Delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package command
+
+import "errors"
+
+var errNetlinkUnsupported = errors.New("The netlink impairment backend is only supported on Linux")
+
+func newNetlinkSetCommand(node, interface_, clabName string) (SetCommand, error) {
+	return nil, errNetlinkUnsupported
+}
+
+func newNetlinkShowCommand(node, clabName string) (ShowCommand, error) {
+	return nil, errNetlinkUnsupported
+}
@@ -24,7 +24,7 @@ type DefaultSetCommand struct {
 func createBaseCommand(node, interface_, clabName string) *exec.Cmd {
 	command := exec.Command("containerlab")
 	command.Args = append(command.Args, "tools", "netem", "set")
-	clabNode := clabName + "-" + node
+	clabNode := getContainerName(node, clabName)
 	command.Args = append(command.Args, "-n", clabNode)
 	command.Args = append(command.Args, "-i", interface_)
 	return command
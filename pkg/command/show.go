@@ -1,13 +1,35 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
 	"os/exec"
+	"sort"
+	"time"
 
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
 )
 
 type ShowCommand interface {
-	ShowImpairments() error
+	GetImpairments() ([]InterfaceImpairments, error)
+}
+
+// Impairments holds the impairments of an interface in the units of the set command
+type Impairments struct {
+	Delay  uint64  `json:"delay" yaml:"delay"`             // ms
+	Jitter uint64  `json:"jitter" yaml:"jitter"`           // ms
+	Loss   float64 `json:"packet_loss" yaml:"packet_loss"` // %
+	Rate   uint64  `json:"rate" yaml:"rate"`               // kbit/s
+}
+
+func (impairments Impairments) isEmpty() bool {
+	return impairments == Impairments{}
+}
+
+// InterfaceImpairments are the impairments applied to an interface, Impairments is nil if the interface has no netem qdisc
+type InterfaceImpairments struct {
+	Interface   string
+	Impairments *Impairments
 }
 
 type DefaultShowCommand struct {
@@ -22,12 +44,80 @@ func NewDefaultShowCommand(node, clabName string) *DefaultShowCommand {
 		},
 	}
 	command.execCommand.Args = append(command.execCommand.Args, "tools", "netem", "show")
-	clabNode := clabName + "-" + node
+	clabNode := getContainerName(node, clabName)
 	command.execCommand.Args = append(command.execCommand.Args, "-n", clabNode)
+	command.execCommand.Args = append(command.execCommand.Args, "--format", "json")
 	command.log.Debugln("Create basic command: ", command.execCommand)
 	return command
 }
 
-func (command *DefaultShowCommand) ShowImpairments() error {
-	return command.ExecuteCommand(command.execCommand)
+// containerlabImpairments is an interface entry of containerlab tools netem show --format json
+type containerlabImpairments struct {
+	Interface  string  `json:"interface"`
+	Delay      string  `json:"delay"`
+	Jitter     string  `json:"jitter"`
+	PacketLoss float64 `json:"packet_loss"`
+	Rate       uint64  `json:"rate"`
+}
+
+func parseContainerlabDuration(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse duration %q: %v", value, err)
+	}
+	return uint64(duration / time.Millisecond), nil
+}
+
+func (entry containerlabImpairments) toInterfaceImpairments() (InterfaceImpairments, error) {
+	// containerlab reports interfaces without netem qdisc as N/A or without values
+	if entry.Delay == "N/A" || (entry.Delay == "" && entry.Jitter == "" && entry.PacketLoss == 0 && entry.Rate == 0) {
+		return InterfaceImpairments{Interface: entry.Interface}, nil
+	}
+	delay, err := parseContainerlabDuration(entry.Delay)
+	if err != nil {
+		return InterfaceImpairments{}, err
+	}
+	jitter, err := parseContainerlabDuration(entry.Jitter)
+	if err != nil {
+		return InterfaceImpairments{}, err
+	}
+	return InterfaceImpairments{
+		Interface:   entry.Interface,
+		Impairments: &Impairments{Delay: delay, Jitter: jitter, Loss: entry.PacketLoss, Rate: entry.Rate},
+	}, nil
+}
+
+// parseContainerlabImpairments parses the JSON output, which holds the interfaces per container
+func parseContainerlabImpairments(output []byte) ([]InterfaceImpairments, error) {
+	var nodes map[string][]containerlabImpairments
+	if err := json.Unmarshal(output, &nodes); err != nil {
+		return nil, fmt.Errorf("Unable to parse containerlab output: %v", err)
+	}
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var impairments []InterfaceImpairments
+	for _, name := range names {
+		for _, entry := range nodes[name] {
+			interfaceImpairments, err := entry.toInterfaceImpairments()
+			if err != nil {
+				return nil, err
+			}
+			impairments = append(impairments, interfaceImpairments)
+		}
+	}
+	return impairments, nil
+}
+
+func (command *DefaultShowCommand) GetImpairments() ([]InterfaceImpairments, error) {
+	output, err := command.ExecuteCommandOutput(command.execCommand)
+	if err != nil {
+		return nil, err
+	}
+	return parseContainerlabImpairments(output)
 }
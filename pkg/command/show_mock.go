@@ -38,16 +38,17 @@ func (m *MockShowCommand) EXPECT() *MockShowCommandMockRecorder {
 	return m.recorder
 }
 
-// ShowImpairments mocks base method.
-func (m *MockShowCommand) ShowImpairments() error {
+// GetImpairments mocks base method.
+func (m *MockShowCommand) GetImpairments() ([]InterfaceImpairments, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ShowImpairments")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetImpairments")
+	ret0, _ := ret[0].([]InterfaceImpairments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ShowImpairments indicates an expected call of ShowImpairments.
-func (mr *MockShowCommandMockRecorder) ShowImpairments() *gomock.Call {
+// GetImpairments indicates an expected call of GetImpairments.
+func (mr *MockShowCommandMockRecorder) GetImpairments() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowImpairments", reflect.TypeOf((*MockShowCommand)(nil).ShowImpairments))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImpairments", reflect.TypeOf((*MockShowCommand)(nil).GetImpairments))
 }
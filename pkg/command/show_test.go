@@ -27,7 +27,7 @@ func TestNewDefaultShowCommand(t *testing.T) {
 			want: &DefaultShowCommand{
 				BaseCommand: BaseCommand{
 					log:         logging.DefaultLogger.WithField("subsystem", "command"),
-					execCommand: exec.Command("containerlab", "tools", "netem", "show", "-n", "clab-hawkv6-XR-1"),
+					execCommand: exec.Command("containerlab", "tools", "netem", "show", "-n", "clab-hawkv6-XR-1", "--format", "json"),
 				},
 			},
 		},
@@ -40,7 +40,7 @@ func TestNewDefaultShowCommand(t *testing.T) {
 	}
 }
 
-func TestDefaultShowCommand_ShowImpairments(t *testing.T) {
+func TestDefaultShowCommand_GetImpairments(t *testing.T) {
 	type fields struct {
 		name     string
 		clabName string
@@ -62,11 +62,64 @@ func TestDefaultShowCommand_ShowImpairments(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			command := NewDefaultShowCommand(tt.fields.name, tt.fields.clabName)
+			impairments, err := command.GetImpairments()
 			if tt.wantErr {
-				assert.Error(t, command.ShowImpairments())
+				assert.Error(t, err)
+				assert.Nil(t, impairments)
 			} else {
-				assert.NoError(t, command.ShowImpairments())
+				assert.NoError(t, err)
 			}
 		})
 	}
 }
+
+func TestParseContainerlabImpairments(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []InterfaceImpairments
+		wantErr bool
+	}{
+		{
+			name: "Test output with and without netem",
+			output: `{"clab-hawkv6-XR-1": [
+				{"interface": "lo", "delay": "N/A", "jitter": "N/A"},
+				{"interface": "eth0"},
+				{"interface": "Gi0-0-0-0", "delay": "0s", "jitter": "0s", "packet_loss": 0, "rate": 0},
+				{"interface": "Gi0-0-0-1", "delay": "4ms", "jitter": "1ms", "packet_loss": 5, "rate": 100000}
+			]}`,
+			want: []InterfaceImpairments{
+				{Interface: "lo"},
+				{Interface: "eth0"},
+				{Interface: "Gi0-0-0-0", Impairments: &Impairments{}},
+				{Interface: "Gi0-0-0-1", Impairments: &Impairments{Delay: 4, Jitter: 1, Loss: 5, Rate: 100000}},
+			},
+		},
+		{
+			name:    "Test invalid delay",
+			output:  `{"clab-hawkv6-XR-1": [{"interface": "Gi0-0-0-0", "delay": "4 parsecs"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid jitter",
+			output:  `{"clab-hawkv6-XR-1": [{"interface": "Gi0-0-0-0", "delay": "4ms", "jitter": "invalid"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test table output",
+			output:  "+-----------+-------+",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseContainerlabImpairments([]byte(tt.output))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -5,5 +5,11 @@ type Config interface {
 	GetValue(string) string
 	DeleteValue(string)
 	SetValue(string, interface{}) error
+	Unmarshal(string, interface{}) error
 	WriteConfig() error
 }
+
+// ChangeNotifier calls the registered callbacks after the watched config file was reloaded
+type ChangeNotifier interface {
+	OnChange(func())
+}
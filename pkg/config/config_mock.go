@@ -92,6 +92,20 @@ func (mr *MockConfigMockRecorder) SetValue(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValue", reflect.TypeOf((*MockConfig)(nil).SetValue), arg0, arg1)
 }
 
+// Unmarshal mocks base method.
+func (m *MockConfig) Unmarshal(arg0 string, arg1 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unmarshal", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unmarshal indicates an expected call of Unmarshal.
+func (mr *MockConfigMockRecorder) Unmarshal(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmarshal", reflect.TypeOf((*MockConfig)(nil).Unmarshal), arg0, arg1)
+}
+
 // WriteConfig mocks base method.
 func (m *MockConfig) WriteConfig() error {
 	m.ctrl.T.Helper()
@@ -105,3 +119,38 @@ func (mr *MockConfigMockRecorder) WriteConfig() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteConfig", reflect.TypeOf((*MockConfig)(nil).WriteConfig))
 }
+
+// MockChangeNotifier is a mock of ChangeNotifier interface.
+type MockChangeNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeNotifierMockRecorder
+}
+
+// MockChangeNotifierMockRecorder is the mock recorder for MockChangeNotifier.
+type MockChangeNotifierMockRecorder struct {
+	mock *MockChangeNotifier
+}
+
+// NewMockChangeNotifier creates a new mock instance.
+func NewMockChangeNotifier(ctrl *gomock.Controller) *MockChangeNotifier {
+	mock := &MockChangeNotifier{ctrl: ctrl}
+	mock.recorder = &MockChangeNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeNotifier) EXPECT() *MockChangeNotifierMockRecorder {
+	return m.recorder
+}
+
+// OnChange mocks base method.
+func (m *MockChangeNotifier) OnChange(arg0 func()) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnChange", arg0)
+}
+
+// OnChange indicates an expected call of OnChange.
+func (mr *MockChangeNotifierMockRecorder) OnChange(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnChange", reflect.TypeOf((*MockChangeNotifier)(nil).OnChange), arg0)
+}
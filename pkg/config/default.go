@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
@@ -26,6 +28,10 @@ type DefaultConfig struct {
 	clabNameKey      string
 	fileProvider     *file.File
 	helper           helpers.Helper
+	callbacks        []func()
+	callbackMutex    sync.Mutex
+	// mutex guards the koanf instance, which is not safe for concurrent use, and its replacement on reload
+	mutex sync.RWMutex
 }
 
 func (config *DefaultConfig) setUserHome() error {
@@ -57,7 +63,8 @@ func (config *DefaultConfig) setConfigFileLocation() {
 
 func (config *DefaultConfig) setClabName(clabName string) error {
 	name := config.koanfInstance.String(config.clabNameKey)
-	if name == "" {
+	// an empty clab name is valid for labs deployed without prefix
+	if !config.koanfInstance.Exists(config.clabNameKey) {
 		config.log.Debugln("No clab name found in config, set to default: clab-hawkv6")
 		config.clabName = config.helper.GetDefaultClabName()
 		if err := config.koanfInstance.Set(config.clabNameKey, config.clabName); err != nil {
@@ -94,16 +101,23 @@ func (config *DefaultConfig) createConfig() error {
 		return err
 	}
 	defer configFile.Close()
+	// the new config is written from the koanf instance, the provider is only needed to watch it
+	config.fileProvider = file.Provider(config.fullfileLocation)
 	return nil
 }
 
 func (config *DefaultConfig) readConfig() error {
 	config.log.Infoln("Read config file: ", config.fullfileLocation)
-	config.fileProvider = file.Provider(config.fullfileLocation)
-	config.koanfInstance = koanf.New(".")
-	if err := config.koanfInstance.Load(config.fileProvider, yaml.Parser()); err != nil {
+	fileProvider := file.Provider(config.fullfileLocation)
+	// the file is loaded into a new instance, so readers never see a partially loaded config
+	koanfInstance := koanf.New(".")
+	if err := koanfInstance.Load(fileProvider, yaml.Parser()); err != nil {
 		return err
 	}
+	config.mutex.Lock()
+	defer config.mutex.Unlock()
+	config.fileProvider = fileProvider
+	config.koanfInstance = koanfInstance
 	return nil
 }
 
@@ -131,20 +145,42 @@ func (config *DefaultConfig) WatchConfigChange() error {
 		config.log.Debugln("Config file changed")
 		if err := config.readConfig(); err != nil {
 			config.log.Errorf("Error reading config file: %v", err)
+			return
 		}
+		config.notifyChange()
 	}); err != nil {
 		return err
 	}
 	return nil
 }
 
+// OnChange registers a callback which is called after each reload of the watched config file
+func (config *DefaultConfig) OnChange(callback func()) {
+	config.callbackMutex.Lock()
+	defer config.callbackMutex.Unlock()
+	config.callbacks = append(config.callbacks, callback)
+}
+
+func (config *DefaultConfig) notifyChange() {
+	config.callbackMutex.Lock()
+	callbacks := append([]func(){}, config.callbacks...)
+	config.callbackMutex.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
 func (config *DefaultConfig) DeleteValue(key string) {
 	config.log.Debugln("Delete value from config: ", key)
+	config.mutex.Lock()
+	defer config.mutex.Unlock()
 	config.koanfInstance.Delete(key)
 }
 
 func (config *DefaultConfig) SetValue(key string, value interface{}) error {
 	config.log.Debugln("Set value in config: ", key, value)
+	config.mutex.Lock()
+	defer config.mutex.Unlock()
 	if err := config.koanfInstance.Set(key, value); err != nil {
 		return err
 	}
@@ -152,7 +188,9 @@ func (config *DefaultConfig) SetValue(key string, value interface{}) error {
 }
 
 func (config *DefaultConfig) GetValue(key string) string {
+	config.mutex.RLock()
 	value := config.koanfInstance.String(key)
+	config.mutex.RUnlock()
 	if value == "" {
 		config.log.Debugf("No value found in config for key: %s", key)
 	} else {
@@ -161,20 +199,53 @@ func (config *DefaultConfig) GetValue(key string) string {
 	return value
 }
 
+// Unmarshal decodes structured values like lists into out, out is left unchanged if the key is not set
+func (config *DefaultConfig) Unmarshal(key string, out interface{}) error {
+	config.mutex.RLock()
+	defer config.mutex.RUnlock()
+	return config.koanfInstance.Unmarshal(key, out)
+}
+
 func (config *DefaultConfig) WriteConfig() error {
 	config.log.Debugln("Write config file: ", config.fullfileLocation)
+	config.mutex.RLock()
 	data, err := config.koanfInstance.Marshal(yaml.Parser())
+	config.mutex.RUnlock()
 	if err != nil {
 		config.log.Errorf("error marshalling config: %v", err)
 		return err
 	}
-	if err := os.WriteFile(config.fullfileLocation, data, 0644); err != nil {
+	if err := writeFileAtomic(config.fullfileLocation, data, 0644); err != nil {
 		config.log.Errorf("error writing config: %v", err)
 		return err
 	}
 	return nil
 }
 
+// writeFileAtomic writes to a temporary file which replaces the file, readers like the config watcher never see a partial config
+func writeFileAtomic(fileName string, data []byte, perm os.FileMode) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(fileName), "."+filepath.Base(fileName)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempFile.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), fileName)
+}
+
 func CreateDefaultConfig(configFileName, clabName, clabNameKey string, helper helpers.Helper) (*DefaultConfig, error) {
 	defaultConfig := &DefaultConfig{
 		log:           logging.DefaultLogger.WithField("subsystem", Subsystem),
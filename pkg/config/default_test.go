@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
@@ -122,7 +125,8 @@ func TestDefaultConfig_setConfigFileLocation(t *testing.T) {
 
 func TestDefaultConfig_setClabName(t *testing.T) {
 	type fields struct {
-		name string
+		name   string
+		stored bool
 	}
 	type args struct {
 		clabName string
@@ -156,6 +160,14 @@ func TestDefaultConfig_setClabName(t *testing.T) {
 			},
 			want: "clab-hawkv6",
 		},
+		{
+			fields: fields{name: "", stored: true},
+			name:   "Test with empty clab name in config",
+			args: args{
+				clabName: "",
+			},
+			want: "",
+		},
 		{
 			fields: fields{name: "clab-hawkv6"},
 			name:   "Test with identical name",
@@ -173,7 +185,7 @@ func TestDefaultConfig_setClabName(t *testing.T) {
 				helper:        helpers.NewDefaultHelper(),
 			}
 			config.clabNameKey = config.helper.GetDefaultClabNameKey()
-			if tt.fields.name != "" {
+			if tt.fields.name != "" || tt.fields.stored {
 				if err := config.koanfInstance.Set(config.clabNameKey, tt.fields.name); err != nil {
 					t.Fatal(err)
 				}
@@ -453,6 +465,42 @@ func TestDefaultConfig_GetValue(t *testing.T) {
 	}
 }
 
+func TestDefaultConfig_Unmarshal(t *testing.T) {
+	type entry struct {
+		Pattern     string `koanf:"pattern"`
+		Replacement string `koanf:"replacement"`
+	}
+	tests := []struct {
+		name  string
+		value interface{}
+		wants []entry
+	}{
+		{
+			name:  "Test unmarshal list",
+			value: []interface{}{map[string]interface{}{"pattern": "^eth(\\d+)$", "replacement": "e$1"}},
+			wants: []entry{{Pattern: "^eth(\\d+)$", Replacement: "e$1"}},
+		},
+		{
+			name:  "Test unmarshal missing key",
+			wants: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &DefaultConfig{
+				log:           logging.DefaultLogger.WithField("subsystem", "config_test"),
+				koanfInstance: koanf.New("."),
+			}
+			if tt.value != nil {
+				assert.NoError(t, config.koanfInstance.Set("test", tt.value))
+			}
+			var got []entry
+			assert.NoError(t, config.Unmarshal("test", &got))
+			assert.Equal(t, tt.wants, got)
+		})
+	}
+}
+
 func TestDefaultConfig_DeleteValue(t *testing.T) {
 	type args struct {
 		key   string
@@ -668,3 +716,99 @@ func TestDefaultConfig_WatchConfigChange(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultConfig_OnChange(t *testing.T) {
+	defaultConfig := &DefaultConfig{}
+	calls := 0
+	defaultConfig.OnChange(func() { calls++ })
+	defaultConfig.OnChange(func() { calls += 10 })
+	defaultConfig.notifyChange()
+	assert.Equal(t, 11, calls)
+}
+
+func TestDefaultConfig_ConcurrentAccess(t *testing.T) {
+	config := &DefaultConfig{
+		log:              logging.DefaultLogger.WithField("subsystem", "config_test"),
+		koanfInstance:    koanf.New("."),
+		fullfileLocation: "config-example.yaml",
+		helper:           helpers.NewDefaultHelper(),
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(4)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, config.SetValue(fmt.Sprintf("nodes.XR-%d.config.Gi0-0-0-0.impairments.delay", i), j))
+				config.DeleteValue(fmt.Sprintf("nodes.XR-%d.config.Gi0-0-0-1", i))
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				config.GetValue(fmt.Sprintf("nodes.XR-%d.config.Gi0-0-0-0.impairments.delay", i))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				nodes := make(map[string]interface{})
+				assert.NoError(t, config.Unmarshal("nodes", &nodes))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, config.readConfig())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  bool
+		directory string
+		wantError bool
+	}{
+		{
+			name:     "Test write new file",
+			existing: false,
+		},
+		{
+			name:     "Test replace existing file",
+			existing: true,
+		},
+		{
+			name:      "Test write to missing directory",
+			directory: "missing",
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := t.TempDir()
+			fileName := filepath.Join(directory, tt.directory, "config.yaml")
+			if tt.existing {
+				assert.NoError(t, os.WriteFile(fileName, []byte("old: value\n"), 0600))
+			}
+			err := writeFileAtomic(fileName, []byte("new: value\n"), 0644)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			data, err := os.ReadFile(fileName)
+			assert.NoError(t, err)
+			assert.Equal(t, "new: value\n", string(data))
+			info, err := os.Stat(fileName)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+			entries, err := os.ReadDir(directory)
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1, "temporary file is removed")
+		})
+	}
+}
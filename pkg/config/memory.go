@@ -0,0 +1,53 @@
+package config
+
+import (
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+)
+
+// MemoryConfig stores the values like the default config but without a config file, it is used as test double
+// where the values have to be read back as they are stored (e.g. Unmarshal of nested keys)
+type MemoryConfig struct {
+	koanfInstance *koanf.Koanf
+}
+
+// NewMemoryConfig loads the given YAML content
+func NewMemoryConfig(content string) (*MemoryConfig, error) {
+	koanfInstance := koanf.New(".")
+	if err := koanfInstance.Load(rawbytes.Provider([]byte(content)), yaml.Parser()); err != nil {
+		return nil, err
+	}
+	return &MemoryConfig{koanfInstance: koanfInstance}, nil
+}
+
+func (config *MemoryConfig) InitConfig() error {
+	return nil
+}
+
+func (config *MemoryConfig) GetValue(key string) string {
+	return config.koanfInstance.String(key)
+}
+
+func (config *MemoryConfig) DeleteValue(key string) {
+	config.koanfInstance.Delete(key)
+}
+
+func (config *MemoryConfig) SetValue(key string, value interface{}) error {
+	return config.koanfInstance.Set(key, value)
+}
+
+func (config *MemoryConfig) Unmarshal(key string, out interface{}) error {
+	return config.koanfInstance.Unmarshal(key, out)
+}
+
+// WriteConfig does nothing, the values are only kept in memory
+func (config *MemoryConfig) WriteConfig() error {
+	return nil
+}
+
+// Content returns the values as YAML, as they would be written to the config file
+func (config *MemoryConfig) Content() (string, error) {
+	data, err := config.koanfInstance.Marshal(yaml.Parser())
+	return string(data), err
+}
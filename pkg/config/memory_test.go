@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryConfig(t *testing.T) {
+	memoryConfig, err := NewMemoryConfig("nodes:\n  XR-1:\n    delay: 10\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", memoryConfig.GetValue("nodes.XR-1.delay"))
+	assert.NoError(t, memoryConfig.SetValue("nodes.XR-2.loss", 5))
+	memoryConfig.DeleteValue("nodes.XR-1")
+	var nodes map[string]map[string]int
+	assert.NoError(t, memoryConfig.Unmarshal("nodes", &nodes))
+	assert.Equal(t, map[string]map[string]int{"XR-2": {"loss": 5}}, nodes)
+	assert.NoError(t, memoryConfig.WriteConfig())
+	content, err := memoryConfig.Content()
+	assert.NoError(t, err)
+	assert.Equal(t, "nodes:\n    XR-2:\n        loss: 5\n", content)
+
+	_, err = NewMemoryConfig("nodes: [")
+	assert.Error(t, err)
+}
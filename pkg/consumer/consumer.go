@@ -4,6 +4,6 @@ var subsystem = "consumer"
 
 type Consumer interface {
 	Init() error
-	Start()
+	Start() error
 	Stop() error
 }
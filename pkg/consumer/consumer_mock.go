@@ -53,9 +53,11 @@ func (mr *MockConsumerMockRecorder) Init() *gomock.Call {
 }
 
 // Start mocks base method.
-func (m *MockConsumer) Start() {
+func (m *MockConsumer) Start() error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Start indicates an expected call of Start.
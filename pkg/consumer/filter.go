@@ -0,0 +1,117 @@
+package consumer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type tagFilter struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+// PassthroughFilter selects the measurements which are published unchanged.
+// Measurement names and tag values are matched with wildcard patterns, * matches any characters (including /) and ? a single one.
+type PassthroughFilter struct {
+	includeMeasurements []*regexp.Regexp
+	excludeMeasurements []*regexp.Regexp
+	includeTags         []tagFilter
+	excludeTags         []tagFilter
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("Empty pattern")
+	}
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	return regexp.Compile("^" + expression + "$")
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiledPatterns := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiledPattern, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid pattern %q: %v", pattern, err)
+		}
+		compiledPatterns = append(compiledPatterns, compiledPattern)
+	}
+	return compiledPatterns, nil
+}
+
+// parseTagFilters parses tag filters in the form key=pattern
+func parseTagFilters(filters []string) ([]tagFilter, error) {
+	tagFilters := make([]tagFilter, 0, len(filters))
+	for _, filter := range filters {
+		key, pattern, found := strings.Cut(filter, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("Invalid tag filter %q, expected key=pattern", filter)
+		}
+		compiledPattern, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid tag filter %q: %v", filter, err)
+		}
+		tagFilters = append(tagFilters, tagFilter{key: key, pattern: compiledPattern})
+	}
+	return tagFilters, nil
+}
+
+func NewPassthroughFilter(includeMeasurements, excludeMeasurements, includeTags, excludeTags []string) (*PassthroughFilter, error) {
+	includeMeasurementPatterns, err := compilePatterns(includeMeasurements)
+	if err != nil {
+		return nil, err
+	}
+	excludeMeasurementPatterns, err := compilePatterns(excludeMeasurements)
+	if err != nil {
+		return nil, err
+	}
+	includeTagFilters, err := parseTagFilters(includeTags)
+	if err != nil {
+		return nil, err
+	}
+	excludeTagFilters, err := parseTagFilters(excludeTags)
+	if err != nil {
+		return nil, err
+	}
+	return &PassthroughFilter{
+		includeMeasurements: includeMeasurementPatterns,
+		excludeMeasurements: excludeMeasurementPatterns,
+		includeTags:         includeTagFilters,
+		excludeTags:         excludeTagFilters,
+	}, nil
+}
+
+func matchesAnyPattern(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyTag(filters []tagFilter, tags map[string]string) bool {
+	for _, filter := range filters {
+		if value, ok := tags[filter.key]; ok && filter.pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the measurement is included (no include filter or at least one matches) and not excluded
+func (filter *PassthroughFilter) Matches(name string, tags map[string]string) bool {
+	if len(filter.includeMeasurements) > 0 && !matchesAnyPattern(filter.includeMeasurements, name) {
+		return false
+	}
+	if matchesAnyPattern(filter.excludeMeasurements, name) {
+		return false
+	}
+	if len(filter.includeTags) > 0 && !matchesAnyTag(filter.includeTags, tags) {
+		return false
+	}
+	return !matchesAnyTag(filter.excludeTags, tags)
+}
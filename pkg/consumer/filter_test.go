@@ -0,0 +1,143 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPassthroughFilter(t *testing.T) {
+	tests := []struct {
+		name                string
+		includeMeasurements []string
+		excludeMeasurements []string
+		includeTags         []string
+		excludeTags         []string
+		wantErr             bool
+	}{
+		{
+			name:    "Test filter without rules",
+			wantErr: false,
+		},
+		{
+			name:                "Test filter with valid rules",
+			includeMeasurements: []string{"utilization", "*-counters"},
+			excludeMeasurements: []string{"isis"},
+			includeTags:         []string{"source=XR-*"},
+			excludeTags:         []string{"name=Loopback*"},
+			wantErr:             false,
+		},
+		{
+			name:                "Test filter with empty include measurement pattern",
+			includeMeasurements: []string{""},
+			wantErr:             true,
+		},
+		{
+			name:                "Test filter with empty exclude measurement pattern",
+			excludeMeasurements: []string{"isis", ""},
+			wantErr:             true,
+		},
+		{
+			name:        "Test filter with include tag without value",
+			includeTags: []string{"source"},
+			wantErr:     true,
+		},
+		{
+			name:        "Test filter with exclude tag without key",
+			excludeTags: []string{"=XR-1"},
+			wantErr:     true,
+		},
+		{
+			name:        "Test filter with empty tag pattern",
+			includeTags: []string{"source="},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewPassthroughFilter(tt.includeMeasurements, tt.excludeMeasurements, tt.includeTags, tt.excludeTags)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, filter)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, filter)
+			}
+		})
+	}
+}
+
+func TestPassthroughFilter_Matches(t *testing.T) {
+	tags := map[string]string{
+		"host":   "telegraf",
+		"name":   "GigabitEthernet0/0/0/0",
+		"source": "XR-1",
+	}
+	tests := []struct {
+		name                string
+		includeMeasurements []string
+		excludeMeasurements []string
+		includeTags         []string
+		excludeTags         []string
+		want                bool
+	}{
+		{
+			name: "Test without rules all measurements match",
+			want: true,
+		},
+		{
+			name:                "Test included measurement",
+			includeMeasurements: []string{"isis", "util*"},
+			want:                true,
+		},
+		{
+			name:                "Test measurement which is not included",
+			includeMeasurements: []string{"isis"},
+			want:                false,
+		},
+		{
+			name:                "Test excluded measurement",
+			excludeMeasurements: []string{"utilization"},
+			want:                false,
+		},
+		{
+			name:        "Test included tag",
+			includeTags: []string{"source=XR-2", "source=XR-1"},
+			want:        true,
+		},
+		{
+			name:        "Test tag which is not included",
+			includeTags: []string{"source=XR-2"},
+			want:        false,
+		},
+		{
+			name:        "Test included tag key which is missing",
+			includeTags: []string{"interface_name=*"},
+			want:        false,
+		},
+		{
+			name:        "Test pattern with special characters",
+			includeTags: []string{"name=GigabitEthernet0/0/0/?", "path=openconfig-interfaces:*"},
+			want:        true,
+		},
+		{
+			name:        "Test excluded tag",
+			excludeTags: []string{"name=GigabitEthernet*"},
+			want:        false,
+		},
+		{
+			name:                "Test exclude wins over include",
+			includeMeasurements: []string{"utilization"},
+			includeTags:         []string{"source=XR-*"},
+			excludeTags:         []string{"host=telegraf"},
+			want:                false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewPassthroughFilter(tt.includeMeasurements, tt.excludeMeasurements, tt.includeTags, tt.excludeTags)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, filter.Matches("utilization", tags))
+		})
+	}
+}
@@ -1,77 +1,216 @@
 package consumer
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/deadletter"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
 	"github.com/sirupsen/logrus"
 )
 
 type KafkaConsumer struct {
-	log                     *logrus.Entry
-	kafkaBroker             string
-	kafkaTopic              string
-	unprocessedMsgChan      chan Message
-	quitChan                chan bool
-	saramaConfig            *sarama.Config
-	saramaConsumer          sarama.Consumer
-	saramaPartitionConsumer sarama.PartitionConsumer
+	log                 *logrus.Entry
+	kafkaBrokers        []string
+	kafkaTopic          string
+	groupID             string
+	rebalanceStrategy   string
+	initialOffset       int64
+	rewoundPartitions   map[string]bool
+	rewoundMutex        sync.Mutex
+	security            *kafka.SecurityConfig
+	passthrough         *PassthroughFilter
+	unprocessedMsgChan  chan Message
+	deadLetter          deadletter.Writer
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	saramaConfig        *sarama.Config
+	saramaClient        sarama.Client
+	saramaConsumerGroup sarama.ConsumerGroup
 }
 
-func NewKafkaConsumer(kafkaBroker, kafkaTopic string, msgChan chan Message) *KafkaConsumer {
+// NewKafkaConsumer creates a consumer group member for the given topic.
+// The initialOffset is either sarama.OffsetOldest, sarama.OffsetNewest or a timestamp in milliseconds (see ParseInitialOffset).
+// Measurements which are not modified by the linker are only forwarded if a passthrough filter is given and matches them.
+func NewKafkaConsumer(kafkaBrokers []string, kafkaTopic, groupID, rebalanceStrategy string, initialOffset int64, security *kafka.SecurityConfig, passthrough *PassthroughFilter, msgChan chan Message, deadLetter deadletter.Writer) *KafkaConsumer {
 	return &KafkaConsumer{
 		log:                logging.DefaultLogger.WithField("subsystem", subsystem),
-		kafkaBroker:        kafkaBroker,
+		kafkaBrokers:       kafkaBrokers,
 		kafkaTopic:         kafkaTopic,
+		groupID:            groupID,
+		rebalanceStrategy:  rebalanceStrategy,
+		initialOffset:      initialOffset,
+		rewoundPartitions:  make(map[string]bool),
+		security:           security,
+		passthrough:        passthrough,
 		unprocessedMsgChan: msgChan,
-		quitChan:           make(chan bool),
+		deadLetter:         deadLetter,
 	}
 }
 
-func (consumer *KafkaConsumer) createConfig() {
+// ParseInitialOffset converts the value of the --from flag into an initial offset.
+// Valid values are oldest, newest, a RFC3339 timestamp or a duration (e.g. 15m) which is subtracted from now.
+func ParseInitialOffset(from string, now time.Time) (int64, error) {
+	switch from {
+	case "", "newest":
+		return sarama.OffsetNewest, nil
+	case "oldest":
+		return sarama.OffsetOldest, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, from); err == nil {
+		return timestamp.UnixMilli(), nil
+	}
+	if duration, err := time.ParseDuration(from); err == nil && duration > 0 {
+		return now.Add(-duration).UnixMilli(), nil
+	}
+	return 0, fmt.Errorf("Invalid start position %q, use oldest, newest, a RFC3339 timestamp or a duration", from)
+}
+
+func (consumer *KafkaConsumer) getBalanceStrategy() (sarama.BalanceStrategy, error) {
+	switch consumer.rebalanceStrategy {
+	case "", sarama.RangeBalanceStrategyName:
+		return sarama.NewBalanceStrategyRange(), nil
+	case sarama.RoundRobinBalanceStrategyName:
+		return sarama.NewBalanceStrategyRoundRobin(), nil
+	case sarama.StickyBalanceStrategyName:
+		return sarama.NewBalanceStrategySticky(), nil
+	default:
+		return nil, fmt.Errorf("Unknown rebalance strategy %q, use one of range, roundrobin or sticky", consumer.rebalanceStrategy)
+	}
+}
+
+func (consumer *KafkaConsumer) createConfig() error {
 	consumer.saramaConfig = sarama.NewConfig()
 	consumer.saramaConfig.Net.DialTimeout = time.Second * 5
+	// The initial offset is only used if the group has no committed offset yet, otherwise consumption resumes.
+	// A timestamp starts from the oldest offset so that no message is skipped if the partition can not be rewound.
+	if consumer.initialOffset == sarama.OffsetNewest {
+		consumer.saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	} else {
+		consumer.saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+	consumer.saramaConfig.Consumer.Offsets.AutoCommit.Enable = true
+	consumer.saramaConfig.Consumer.Offsets.AutoCommit.Interval = time.Second
+	balanceStrategy, err := consumer.getBalanceStrategy()
+	if err != nil {
+		return err
+	}
+	consumer.saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{balanceStrategy}
+	return consumer.security.Apply(consumer.saramaConfig)
 }
 
-func (consumer *KafkaConsumer) createConsumer() error {
-	consumer.createConfig()
-	saramaConsumer, err := sarama.NewConsumer([]string{consumer.kafkaBroker}, consumer.saramaConfig)
+func (consumer *KafkaConsumer) createConsumerGroup() error {
+	if err := consumer.createConfig(); err != nil {
+		return err
+	}
+	client, err := sarama.NewClient(consumer.kafkaBrokers, consumer.saramaConfig)
 	if err != nil {
-		consumer.log.Debugln("Error creating consumer: ", err)
+		consumer.log.Debugln("Error creating client: ", err)
 		return err
 	}
-	consumer.log.Debugln("Successfully created Kafka consumer for broker: ", consumer.kafkaBroker)
-	consumer.saramaConsumer = saramaConsumer
-	return nil
-}
-func (consumer *KafkaConsumer) createParitionConsumer() error {
-	partitionConsumer, err := consumer.saramaConsumer.ConsumePartition(consumer.kafkaTopic, 0, sarama.OffsetNewest)
+	consumerGroup, err := sarama.NewConsumerGroupFromClient(consumer.groupID, client)
 	if err != nil {
-		consumer.log.Debugln("Error partition consumer: ", err)
+		consumer.log.Debugln("Error creating consumer group: ", err)
+		if err := client.Close(); err != nil {
+			consumer.log.Debugln("Error closing client: ", err)
+		}
 		return err
 	}
-	consumer.saramaPartitionConsumer = partitionConsumer
-	consumer.log.Debugln("Successfully created Kafka partition consumer for topic: ", consumer.kafkaTopic)
+	consumer.log.Debugf("Successfully created Kafka consumer group %s for brokers: %s", consumer.groupID, strings.Join(consumer.kafkaBrokers, ","))
+	consumer.saramaClient = client
+	consumer.saramaConsumerGroup = consumerGroup
 	return nil
 }
 
-func (consumer *KafkaConsumer) Init() error {
-	if err := consumer.createConsumer(); err != nil {
-		return err
+// rewindToTimestamp moves the claimed partitions to the first offset at or after the configured timestamp.
+// Every partition is rewound once, later sessions (e.g. after a rebalance) continue from the committed offset.
+// Setup runs before the claims are consumed, so the moved offset is the one the claims start from.
+func (consumer *KafkaConsumer) rewindToTimestamp(session sarama.ConsumerGroupSession) error {
+	consumer.rewoundMutex.Lock()
+	defer consumer.rewoundMutex.Unlock()
+	for topic, partitions := range session.Claims() {
+		for _, partition := range partitions {
+			key := fmt.Sprintf("%s/%d", topic, partition)
+			if consumer.rewoundPartitions[key] {
+				continue
+			}
+			offset, err := consumer.saramaClient.GetOffset(topic, partition, consumer.initialOffset)
+			if err != nil {
+				return fmt.Errorf("Unable to get offset for partition %d of topic %s: %v", partition, topic, err)
+			}
+			if offset == sarama.OffsetNewest {
+				// No message newer than the timestamp, continue with the next produced message
+				if offset, err = consumer.saramaClient.GetOffset(topic, partition, sarama.OffsetNewest); err != nil {
+					return fmt.Errorf("Unable to get newest offset for partition %d of topic %s: %v", partition, topic, err)
+				}
+			}
+			consumer.log.Debugf("Rewind partition %d of topic %s to offset %d", partition, topic, offset)
+			// ResetOffset only moves the offset backwards and MarkOffset only forwards, one of them applies depending on
+			// whether the offset is before or after the committed one (a group without committed offset is always moved forward)
+			session.ResetOffset(topic, partition, offset, "")
+			session.MarkOffset(topic, partition, offset, "")
+			consumer.rewoundPartitions[key] = true
+		}
 	}
-	if err := consumer.createParitionConsumer(); err != nil {
-		return err
+	return nil
+}
+
+// Init creates a new consumer group, it is called again to reconnect after Start returned an error
+func (consumer *KafkaConsumer) Init() error {
+	consumer.ctx, consumer.cancel = context.WithCancel(context.Background())
+	return consumer.createConsumerGroup()
+}
+
+// Setup is run at the beginning of a new session, before ConsumeClaim
+func (consumer *KafkaConsumer) Setup(session sarama.ConsumerGroupSession) error {
+	consumer.log.Debugf("Consumer group session started with claims: %v", session.Claims())
+	if consumer.initialOffset >= 0 {
+		return consumer.rewindToTimestamp(session)
 	}
 	return nil
 }
 
+// Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited
+func (consumer *KafkaConsumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	consumer.log.Debugf("Consumer group session ended with claims: %v", session.Claims())
+	return nil
+}
+
+// ConsumeClaim processes the messages of a single partition assigned to this consumer
+func (consumer *KafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	consumer.log.Debugf("Start consuming partition %d of topic %s", claim.Partition(), claim.Topic())
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if !consumer.processMessage(session.Context(), message) {
+				return nil
+			}
+			// Marked offsets are committed periodically and when the consumer group is closed
+			session.MarkMessage(message, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
 func (consumer *KafkaConsumer) UnmarshalTelemetryMessage(message *sarama.ConsumerMessage) (*TelemetryMessage, error) {
 	consumer.log.Debugln("Received JSON message: ", string(message.Value))
 	var telemetryMessage TelemetryMessage
-	if err := json.Unmarshal([]byte(message.Value), &telemetryMessage); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(message.Value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&telemetryMessage); err != nil {
 		consumer.log.Debugln("Error unmarshalling message: ", err)
 		return nil, err
 	}
@@ -82,36 +221,46 @@ func (consumer *KafkaConsumer) UnmarshalDelayMessage(telemetryMessage TelemetryM
 	delayMessage := DelayMessage{TelemetryMessage: telemetryMessage}
 
 	fields := map[string]*uint32{
-		"delay_measurement_session/last_advertisement_information/advertised_values/average":  &delayMessage.Average,
-		"delay_measurement_session/last_advertisement_information/advertised_values/minimum":  &delayMessage.Minimum,
-		"delay_measurement_session/last_advertisement_information/advertised_values/maximum":  &delayMessage.Maximum,
-		"delay_measurement_session/last_advertisement_information/advertised_values/variance": &delayMessage.Variance,
+		averageDelayField:  &delayMessage.Average,
+		minimumDelayField:  &delayMessage.Minimum,
+		maximumDelayField:  &delayMessage.Maximum,
+		delayVarianceField: &delayMessage.Variance,
 	}
 	for key, field := range fields {
-		value, ok := telemetryMessage.Fields[key].(float64)
-		if !ok {
-			return nil, fmt.Errorf("unable to convert %s to float64", key)
+		value, err := telemetryMessage.getFloat64Field(key)
+		if err != nil {
+			return nil, err
 		}
 		*field = uint32(value)
 	}
 	return &delayMessage, nil
 }
 
+// UnmarshalIsisMessage splits an ISIS message into a loss and a bandwidth message.
+// If both are present, each message keeps all other fields but not the field impaired by the other one, so that the published lines do not overwrite each other's impaired values.
 func (consumer *KafkaConsumer) UnmarshalIsisMessage(telemetryMessage TelemetryMessage) ([]Message, error) {
 	var messages []Message
-	var err error
-	var msg Message
+	hasLoss := telemetryMessage.Fields[lossField] != nil
+	hasBandwidth := telemetryMessage.Fields[bandwidthField] != nil
 
-	if telemetryMessage.Fields["interface_status_and_data/enabled/packet_loss_percentage"] != nil {
-		msg, err = consumer.UnmarshalLossMessage(telemetryMessage)
+	if hasLoss {
+		lossTelemetryMessage := telemetryMessage
+		if hasBandwidth {
+			lossTelemetryMessage = telemetryMessage.withoutField(bandwidthField)
+		}
+		msg, err := consumer.UnmarshalLossMessage(lossTelemetryMessage)
 		if err != nil {
 			return nil, err
 		}
 		messages = append(messages, msg)
 	}
 
-	if telemetryMessage.Fields["interface_status_and_data/enabled/bandwidth"] != nil {
-		msg, err = consumer.UnmarshalBandwidthMessage(telemetryMessage)
+	if hasBandwidth {
+		bandwidthTelemetryMessage := telemetryMessage
+		if hasLoss {
+			bandwidthTelemetryMessage = telemetryMessage.withoutField(lossField)
+		}
+		msg, err := consumer.UnmarshalBandwidthMessage(bandwidthTelemetryMessage)
 		if err != nil {
 			return nil, err
 		}
@@ -119,7 +268,7 @@ func (consumer *KafkaConsumer) UnmarshalIsisMessage(telemetryMessage TelemetryMe
 	}
 
 	if len(messages) == 0 {
-		return nil, fmt.Errorf("Received unknown ISIS message: %v", telemetryMessage)
+		return nil, fmt.Errorf("%w: %v", ErrUnknownIsisMessage, telemetryMessage)
 	}
 
 	return messages, nil
@@ -127,8 +276,8 @@ func (consumer *KafkaConsumer) UnmarshalIsisMessage(telemetryMessage TelemetryMe
 
 func (consumer *KafkaConsumer) UnmarshalLossMessage(telemetryMessage TelemetryMessage) (*LossMessage, error) {
 	lossMessage := LossMessage{TelemetryMessage: telemetryMessage}
-	value, ok := telemetryMessage.Fields["interface_status_and_data/enabled/packet_loss_percentage"].(float64)
-	if !ok {
+	value, err := telemetryMessage.getFloat64Field(lossField)
+	if err != nil {
 		return nil, fmt.Errorf("unable to convert packet_loss_percentage to float")
 	}
 	lossMessage.LossPercentage = value
@@ -137,61 +286,144 @@ func (consumer *KafkaConsumer) UnmarshalLossMessage(telemetryMessage TelemetryMe
 
 func (consumer *KafkaConsumer) UnmarshalBandwidthMessage(telemetryMessage TelemetryMessage) (*BandwidthMessage, error) {
 	bandwidthMessage := BandwidthMessage{TelemetryMessage: telemetryMessage}
-	value, ok := telemetryMessage.Fields["interface_status_and_data/enabled/bandwidth"].(float64)
-	if !ok {
+	value, err := telemetryMessage.getFloat64Field(bandwidthField)
+	if err != nil {
 		return nil, fmt.Errorf("unable to convert bandwidth to float64")
 	}
 	bandwidthMessage.Bandwidth = value
 	return &bandwidthMessage, nil
 }
 
-func (consumer *KafkaConsumer) processMessage(message *sarama.ConsumerMessage) {
+func (consumer *KafkaConsumer) UnmarshalUtilizationMessage(telemetryMessage TelemetryMessage) (*UtilizationMessage, error) {
+	utilizationMessage := UtilizationMessage{TelemetryMessage: telemetryMessage}
+	inOctets, err := telemetryMessage.getUint64Field(inOctetsField)
+	if err != nil {
+		return nil, err
+	}
+	outOctets, err := telemetryMessage.getUint64Field(outOctetsField)
+	if err != nil {
+		return nil, err
+	}
+	utilizationMessage.InOctets = inOctets
+	utilizationMessage.OutOctets = outOctets
+	return &utilizationMessage, nil
+}
+
+// forwardMessage hands the message to the processor, it returns false if the session ended while the pipeline was blocked
+func (consumer *KafkaConsumer) forwardMessage(ctx context.Context, message Message) bool {
+	select {
+	case consumer.unprocessedMsgChan <- message:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// writeDeadLetter stores a message which can not be processed together with the reason
+func (consumer *KafkaConsumer) writeDeadLetter(message *sarama.ConsumerMessage, reason error) {
+	consumer.log.Debugf("Dropping message from partition %d at offset %d: %v", message.Partition, message.Offset, reason)
+	letter := deadletter.NewLetter(deadletter.StageConsumer, reason, message.Value)
+	letter.Topic = message.Topic
+	letter.Partition = message.Partition
+	letter.Offset = message.Offset
+	consumer.deadLetter.Write(letter)
+}
+
+// dropMessage counts and stores a message which is not handed to the processor
+func (consumer *KafkaConsumer) dropMessage(message *sarama.ConsumerMessage, telemetryMessage TelemetryMessage, reason string, err error) {
+	metrics.ConsumerDropped.WithLabelValues(telemetryMessage.Name, telemetryMessage.Tags.Source(), reason).Inc()
+	consumer.writeDeadLetter(message, err)
+}
+
+// processMessage returns false if the message was not handed over and must therefore not be marked as consumed
+func (consumer *KafkaConsumer) processMessage(ctx context.Context, message *sarama.ConsumerMessage) bool {
 	telemetryMessage, err := consumer.UnmarshalTelemetryMessage(message)
 	if err != nil {
-		return
+		metrics.ConsumerReceived.WithLabelValues("", "").Inc()
+		consumer.dropMessage(message, TelemetryMessage{}, metrics.ReasonInvalidJSON, fmt.Errorf("Invalid JSON: %v", err))
+		return true
 	}
+	metrics.ConsumerReceived.WithLabelValues(telemetryMessage.Name, telemetryMessage.Tags.Source()).Inc()
 	if telemetryMessage.Name == "performance-measurement" {
 		delayMessage, err := consumer.UnmarshalDelayMessage(*telemetryMessage)
 		if err != nil {
-			return
+			consumer.dropMessage(message, *telemetryMessage, metrics.ReasonInvalidFields, err)
+			return true
 		}
-		consumer.unprocessedMsgChan <- delayMessage
+		return consumer.forwardMessage(ctx, delayMessage)
 	} else if telemetryMessage.Name == "isis" {
 		isisMessages, err := consumer.UnmarshalIsisMessage(*telemetryMessage)
+		if errors.Is(err, ErrUnknownIsisMessage) && consumer.passthrough != nil {
+			return consumer.processPassthroughMessage(ctx, *telemetryMessage)
+		}
 		if err != nil {
-			return
+			consumer.dropMessage(message, *telemetryMessage, metrics.ReasonInvalidFields, err)
+			return true
 		}
 		for _, isisMessage := range isisMessages {
-			consumer.unprocessedMsgChan <- isisMessage
+			if !consumer.forwardMessage(ctx, isisMessage) {
+				return false
+			}
 		}
+		return true
+	} else if telemetryMessage.Name == "utilization" {
+		utilizationMessage, err := consumer.UnmarshalUtilizationMessage(*telemetryMessage)
+		if err != nil {
+			consumer.dropMessage(message, *telemetryMessage, metrics.ReasonInvalidFields, err)
+			return true
+		}
+		return consumer.forwardMessage(ctx, utilizationMessage)
+	} else if consumer.passthrough != nil {
+		return consumer.processPassthroughMessage(ctx, *telemetryMessage)
 	} else {
-		consumer.log.Debugf("Skipping unknown message: %v", telemetryMessage)
-		return
+		consumer.dropMessage(message, *telemetryMessage, metrics.ReasonUnknownMeasurement, fmt.Errorf("Unknown measurement name %q", telemetryMessage.Name))
+		return true
+	}
+}
+
+// processPassthroughMessage forwards measurements which are not modified by the linker if they match the passthrough filter
+func (consumer *KafkaConsumer) processPassthroughMessage(ctx context.Context, telemetryMessage TelemetryMessage) bool {
+	if !consumer.passthrough.Matches(telemetryMessage.Name, telemetryMessage.Tags) {
+		consumer.log.Debugf("Skipping measurement %s which does not match the passthrough filter", telemetryMessage.Name)
+		metrics.ConsumerDropped.WithLabelValues(telemetryMessage.Name, telemetryMessage.Tags.Source(), metrics.ReasonFiltered).Inc()
+		return true
 	}
+	return consumer.forwardMessage(ctx, &PassthroughMessage{TelemetryMessage: telemetryMessage})
 }
 
-func (consumer *KafkaConsumer) Start() {
-	consumer.log.Infof("Start consuming messages from broker %s and topic %s", consumer.kafkaBroker, consumer.kafkaTopic)
+// Start consumes messages until Stop is called (returns nil) or the connection to Kafka fails (returns the error)
+func (consumer *KafkaConsumer) Start() error {
+	consumer.log.Infof("Start consuming messages from brokers %s and topic %s in group %s", strings.Join(consumer.kafkaBrokers, ","), consumer.kafkaTopic, consumer.groupID)
 	for {
-		select {
-		case message := <-consumer.saramaPartitionConsumer.Messages():
-			consumer.processMessage(message)
-		case <-consumer.quitChan:
-			consumer.log.Infoln("Stop consumer with values: ", consumer.kafkaBroker, consumer.kafkaTopic)
-			return
+		// Consume has to be called in a loop since it returns whenever the group is rebalanced
+		err := consumer.saramaConsumerGroup.Consume(consumer.ctx, []string{consumer.kafkaTopic}, consumer)
+		if consumer.ctx.Err() != nil || errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			consumer.log.Infoln("Stop consumer with values: ", strings.Join(consumer.kafkaBrokers, ","), consumer.kafkaTopic)
+			return nil
+		}
+		if err != nil {
+			consumer.log.Errorln("Error consuming messages: ", err)
+			return err
 		}
 	}
 }
 
+// Stop ends consuming and releases the connection, it is safe to call if Init failed or Start already returned
 func (consumer *KafkaConsumer) Stop() error {
-	consumer.quitChan <- true
-	if err := consumer.saramaPartitionConsumer.Close(); err != nil {
-		consumer.log.Errorln("Error closing partition consumer: ", err)
-		return err
+	if consumer.cancel != nil {
+		consumer.cancel()
 	}
-	if err := consumer.saramaConsumer.Close(); err != nil {
-		consumer.log.Errorln("Error closing consumer: ", err)
-		return err
+	if consumer.saramaConsumerGroup != nil {
+		if err := consumer.saramaConsumerGroup.Close(); err != nil && !errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			consumer.log.Errorln("Error closing consumer group: ", err)
+			return err
+		}
+	}
+	if consumer.saramaClient != nil {
+		if err := consumer.saramaClient.Close(); err != nil && !errors.Is(err, sarama.ErrClosedClient) {
+			consumer.log.Errorln("Error closing client: ", err)
+			return err
+		}
 	}
 	return nil
 }
@@ -1,18 +1,26 @@
 package consumer
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/IBM/sarama"
-	"github.com/IBM/sarama/mocks"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/deadletter"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
 func TestNewKafkaConsumer(t *testing.T) {
 	type args struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	tests := []struct {
 		name string
@@ -22,15 +30,15 @@ func TestNewKafkaConsumer(t *testing.T) {
 		{
 			name: "Test New Kafka Consumer",
 			args: args{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			msgChan := make(chan Message)
-			kafkaConsumer := NewKafkaConsumer(tt.args.kafkaBroker, tt.args.kafkaTopic, msgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.args.kafkaBrokers, tt.args.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, msgChan, deadletter.NewMultiWriter())
 			assert.NotNil(t, kafkaConsumer)
 		})
 	}
@@ -38,7 +46,7 @@ func TestNewKafkaConsumer(t *testing.T) {
 
 func TestKafkaConsumer_createConfig(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -49,7 +57,7 @@ func TestKafkaConsumer_createConfig(t *testing.T) {
 		{
 			name: "Test Create Config",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -57,87 +65,316 @@ func TestKafkaConsumer_createConfig(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
-			kafkaConsumer.createConfig()
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
+			assert.NoError(t, kafkaConsumer.createConfig())
 			assert.NotNil(t, kafkaConsumer.saramaConfig)
 		})
 	}
 }
 
-func TestKafkaConsumer_createConsumer(t *testing.T) {
-	type fields struct {
-		kafkaBroker        string
-		kafkaTopic         string
-		unprocessedMsgChan chan Message
+func TestKafkaConsumer_getBalanceStrategy(t *testing.T) {
+	tests := []struct {
+		name              string
+		rebalanceStrategy string
+		want              string
+		wantErr           bool
+	}{
+		{
+			name:              "Test default strategy",
+			rebalanceStrategy: "",
+			want:              sarama.RangeBalanceStrategyName,
+			wantErr:           false,
+		},
+		{
+			name:              "Test range strategy",
+			rebalanceStrategy: "range",
+			want:              sarama.RangeBalanceStrategyName,
+			wantErr:           false,
+		},
+		{
+			name:              "Test roundrobin strategy",
+			rebalanceStrategy: "roundrobin",
+			want:              sarama.RoundRobinBalanceStrategyName,
+			wantErr:           false,
+		},
+		{
+			name:              "Test sticky strategy",
+			rebalanceStrategy: "sticky",
+			want:              sarama.StickyBalanceStrategyName,
+			wantErr:           false,
+		},
+		{
+			name:              "Test unknown strategy",
+			rebalanceStrategy: "unknown",
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", tt.rebalanceStrategy, sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			strategy, err := kafkaConsumer.getBalanceStrategy()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, kafkaConsumer.createConfig())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, strategy.Name())
+			}
+		})
 	}
+}
+
+func TestParseInitialOffset(t *testing.T) {
+	now := time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC)
 	tests := []struct {
-		name   string
-		fields fields
+		name    string
+		from    string
+		want    int64
+		wantErr bool
 	}{
 		{
-			name: "Test create consumer with invalid broker",
-			fields: fields{
-				kafkaBroker:        "localhost:9092",
-				kafkaTopic:         "test",
-				unprocessedMsgChan: make(chan Message),
-			},
+			name:    "Test empty start position",
+			from:    "",
+			want:    sarama.OffsetNewest,
+			wantErr: false,
+		},
+		{
+			name:    "Test newest start position",
+			from:    "newest",
+			want:    sarama.OffsetNewest,
+			wantErr: false,
+		},
+		{
+			name:    "Test oldest start position",
+			from:    "oldest",
+			want:    sarama.OffsetOldest,
+			wantErr: false,
+		},
+		{
+			name:    "Test RFC3339 timestamp start position",
+			from:    "2024-01-08T14:00:00Z",
+			want:    time.Date(2024, 1, 8, 14, 0, 0, 0, time.UTC).UnixMilli(),
+			wantErr: false,
+		},
+		{
+			name:    "Test duration start position",
+			from:    "15m",
+			want:    time.Date(2024, 1, 8, 14, 45, 0, 0, time.UTC).UnixMilli(),
+			wantErr: false,
+		},
+		{
+			name:    "Test negative duration start position",
+			from:    "-15m",
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid start position",
+			from:    "yesterday",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
-			assert.Error(t, kafkaConsumer.createConsumer())
+			offset, err := ParseInitialOffset(tt.from, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, offset)
+			}
 		})
 	}
 }
-func TestKafkaConsumer_createParitionConsumer(t *testing.T) {
-	type fields struct {
-		kafkaBroker        string
-		kafkaTopic         string
-		unprocessedMsgChan chan Message
+
+func TestKafkaConsumer_createConfig_InitialOffset(t *testing.T) {
+	tests := []struct {
+		name          string
+		initialOffset int64
+		want          int64
+	}{
+		{
+			name:          "Test oldest initial offset",
+			initialOffset: sarama.OffsetOldest,
+			want:          sarama.OffsetOldest,
+		},
+		{
+			name:          "Test newest initial offset",
+			initialOffset: sarama.OffsetNewest,
+			want:          sarama.OffsetNewest,
+		},
+		{
+			name:          "Test timestamp initial offset",
+			initialOffset: 1704728135000,
+			want:          sarama.OffsetOldest,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", tt.initialOffset, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			assert.NoError(t, kafkaConsumer.createConfig())
+			assert.Equal(t, tt.want, kafkaConsumer.saramaConfig.Consumer.Offsets.Initial)
+			assert.True(t, kafkaConsumer.saramaConfig.Consumer.Offsets.AutoCommit.Enable)
+		})
+	}
+}
+
+func TestKafkaConsumer_createConfig_Security(t *testing.T) {
 	tests := []struct {
 		name     string
-		fields   fields
-		wantsErr bool
+		security *kafka.SecurityConfig
+		wantTLS  bool
+		wantErr  bool
 	}{
 		{
-			name: "Test create partition consumer with valid consumer",
-			fields: fields{
-				kafkaBroker:        "localhost:9092",
-				kafkaTopic:         "test",
-				unprocessedMsgChan: make(chan Message),
-			},
-			wantsErr: false,
+			name:     "Test config with TLS",
+			security: &kafka.SecurityConfig{TLSEnabled: true},
+			wantTLS:  true,
+			wantErr:  false,
 		},
 		{
-			name: "Test create partition consumer with invalid consumer",
+			name:     "Test config with invalid SASL settings",
+			security: &kafka.SecurityConfig{SASLMechanism: "PLAIN"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, tt.security, nil, make(chan Message), deadletter.NewMultiWriter())
+			if tt.wantErr {
+				assert.Error(t, kafkaConsumer.createConfig())
+				assert.Error(t, kafkaConsumer.Init())
+			} else {
+				assert.NoError(t, kafkaConsumer.createConfig())
+				assert.Equal(t, tt.wantTLS, kafkaConsumer.saramaConfig.Net.TLS.Enable)
+			}
+		})
+	}
+}
+
+func TestKafkaConsumer_Setup(t *testing.T) {
+	tests := []struct {
+		name          string
+		initialOffset int64
+		claims        map[string][]int32
+		committed     map[int32]int64
+		offsets       map[int64]int64
+		offsetErr     error
+		want          map[string]int64
+		wantErr       bool
+	}{
+		{
+			name:          "Test setup without timestamp",
+			initialOffset: sarama.OffsetNewest,
+			committed:     map[int32]int64{0: 10},
+			want:          map[string]int64{"test": 10},
+			wantErr:       false,
+		},
+		{
+			name:          "Test setup with timestamp for a group without committed offset",
+			initialOffset: 1704728135000,
+			offsets:       map[int64]int64{1704728135000: 42},
+			want:          map[string]int64{"test": 42},
+			wantErr:       false,
+		},
+		{
+			name:          "Test setup with timestamp before the committed offset",
+			initialOffset: 1704728135000,
+			committed:     map[int32]int64{0: 100},
+			offsets:       map[int64]int64{1704728135000: 42},
+			want:          map[string]int64{"test": 42},
+			wantErr:       false,
+		},
+		{
+			name:          "Test setup with timestamp after the committed offset",
+			initialOffset: 1704728135000,
+			committed:     map[int32]int64{0: 10},
+			offsets:       map[int64]int64{1704728135000: 42},
+			want:          map[string]int64{"test": 42},
+			wantErr:       false,
+		},
+		{
+			name:          "Test setup with timestamp newer than all messages",
+			initialOffset: 1704728135000,
+			offsets:       map[int64]int64{1704728135000: sarama.OffsetNewest, sarama.OffsetNewest: 100},
+			want:          map[string]int64{"test": 100},
+			wantErr:       false,
+		},
+		{
+			name:          "Test setup with timestamp for the same partition of two topics",
+			initialOffset: 1704728135000,
+			claims:        map[string][]int32{"test": {0}, "other": {0}},
+			offsets:       map[int64]int64{1704728135000: 42},
+			want:          map[string]int64{"test": 42, "other": 42},
+			wantErr:       false,
+		},
+		{
+			name:          "Test setup with offset error",
+			initialOffset: 1704728135000,
+			offsetErr:     errors.New("offset error"),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", tt.initialOffset, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			assert.NoError(t, kafkaConsumer.createConfig())
+			kafkaConsumer.saramaClient = &fakeClient{offsets: tt.offsets, offsetErr: tt.offsetErr}
+			session := newFakeConsumerGroupSession(context.Background())
+			session.claims = tt.claims
+			for partition, offset := range tt.committed {
+				session.offsets[fmt.Sprintf("test/%d", partition)] = offset
+			}
+			if tt.wantErr {
+				assert.Error(t, kafkaConsumer.Setup(session))
+				return
+			}
+			assert.NoError(t, kafkaConsumer.Setup(session))
+			initial := kafkaConsumer.saramaConfig.Consumer.Offsets.Initial
+			got := make(map[string]int64)
+			for topic, partitions := range session.Claims() {
+				for _, partition := range partitions {
+					got[topic] = session.startOffset(topic, partition, initial)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+			// a second session must not rewind the partitions again
+			secondSession := newFakeConsumerGroupSession(context.Background())
+			secondSession.claims = tt.claims
+			assert.NoError(t, kafkaConsumer.Setup(secondSession))
+			assert.Empty(t, secondSession.moved)
+		})
+	}
+}
+
+func TestKafkaConsumer_createConsumerGroup(t *testing.T) {
+	type fields struct {
+		kafkaBrokers       []string
+		kafkaTopic         string
+		unprocessedMsgChan chan Message
+	}
+	tests := []struct {
+		name   string
+		fields fields
+	}{
+		{
+			name: "Test create consumer with invalid broker",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
-			wantsErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
-			saramaConsumer := mocks.NewConsumer(t, nil)
-			saramaConsumer.ExpectConsumePartition(tt.fields.kafkaTopic, 0, sarama.OffsetNewest)
-			kafkaConsumer.saramaConsumer = saramaConsumer
-			if tt.wantsErr {
-				assert.Error(t, kafkaConsumer.createParitionConsumer())
-			} else {
-				assert.NoError(t, kafkaConsumer.createParitionConsumer())
-			}
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
+			assert.Error(t, kafkaConsumer.createConsumerGroup())
 		})
 	}
 }
-
 func TestKafkaConsumer_Init(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -148,7 +385,7 @@ func TestKafkaConsumer_Init(t *testing.T) {
 		{
 			name: "Test init function with invalid consumer",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -156,7 +393,7 @@ func TestKafkaConsumer_Init(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
 			assert.Error(t, kafkaConsumer.Init())
 		})
 	}
@@ -164,7 +401,7 @@ func TestKafkaConsumer_Init(t *testing.T) {
 
 func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -180,7 +417,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with packet loss message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -208,7 +445,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with delay message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -239,7 +476,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with bandwidth message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -267,7 +504,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with invalid message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -283,7 +520,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with invalid json",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -299,7 +536,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
 			telemetryMsg, err := kafkaConsumer.UnmarshalTelemetryMessage(tt.args.message)
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -313,7 +550,7 @@ func TestKafkaConsumer_UnmarshalTelemetryMessage(t *testing.T) {
 
 func TestKafkaConsumer_UnmarshalDelayMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -329,7 +566,7 @@ func TestKafkaConsumer_UnmarshalDelayMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with delay message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -360,7 +597,7 @@ func TestKafkaConsumer_UnmarshalDelayMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with wrong fields",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -391,7 +628,7 @@ func TestKafkaConsumer_UnmarshalDelayMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with wrong message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -419,7 +656,7 @@ func TestKafkaConsumer_UnmarshalDelayMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
 			telemetryMsg, err := kafkaConsumer.UnmarshalTelemetryMessage(tt.args.message)
 			assert.NoError(t, err)
 			delayMsg, err := kafkaConsumer.UnmarshalDelayMessage(*telemetryMsg)
@@ -439,7 +676,7 @@ func TestKafkaConsumer_UnmarshalDelayMessage(t *testing.T) {
 
 func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -455,7 +692,7 @@ func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with unknown isis message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -486,7 +723,7 @@ func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with Bandwidth message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -514,7 +751,7 @@ func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with packet loss message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -542,7 +779,7 @@ func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Telemetry Message with invalid packet loss message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -570,7 +807,7 @@ func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
 			telemetryMsg, err := kafkaConsumer.UnmarshalTelemetryMessage(tt.args.message)
 			assert.NoError(t, err)
 			delayMsg, err := kafkaConsumer.UnmarshalIsisMessage(*telemetryMsg)
@@ -583,9 +820,45 @@ func TestKafkaConsumer_UnmarshalIsisMessage(t *testing.T) {
 		})
 	}
 }
+func TestKafkaConsumer_UnmarshalIsisMessage_Split(t *testing.T) {
+	tests := []struct {
+		name  string
+		value []byte
+	}{
+		{
+			name:  "Test ISIS message with loss and bandwidth is split without the other impaired field",
+			value: []byte(`{"fields":{"interface_status_and_data/enabled/packet_loss_percentage":0,"interface_status_and_data/enabled/bandwidth":1000000,"interface_status_and_data/enabled/adjacency_count":1},"name":"isis","tags":{"instance_name":"1","interface_name":"GigabitEthernet0/0/0/0","source":"XR-1"},"timestamp":1704728369}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			telemetryMsg, err := kafkaConsumer.UnmarshalTelemetryMessage(&sarama.ConsumerMessage{Value: tt.value})
+			assert.NoError(t, err)
+			messages, err := kafkaConsumer.UnmarshalIsisMessage(*telemetryMsg)
+			assert.NoError(t, err)
+			assert.Len(t, messages, 2)
+			lossMessage, ok := messages[0].(*LossMessage)
+			assert.True(t, ok)
+			assert.Equal(t, map[string]interface{}{
+				lossField: float64(0),
+				"interface_status_and_data/enabled/adjacency_count": json.Number("1"),
+			}, lossMessage.GetFields())
+			bandwidthMessage, ok := messages[1].(*BandwidthMessage)
+			assert.True(t, ok)
+			assert.Equal(t, map[string]interface{}{
+				bandwidthField: float64(1000000),
+				"interface_status_and_data/enabled/adjacency_count": json.Number("1"),
+			}, bandwidthMessage.GetFields())
+			assert.Equal(t, "1", bandwidthMessage.GetTags()["instance_name"])
+			assert.Len(t, telemetryMsg.Fields, 3, "original fields must not be modified")
+		})
+	}
+}
+
 func TestKafkaConsumer_UnmarshalLossMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -601,7 +874,7 @@ func TestKafkaConsumer_UnmarshalLossMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Loss Message with valid message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -629,7 +902,7 @@ func TestKafkaConsumer_UnmarshalLossMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal Loss Message with invalid message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -657,7 +930,7 @@ func TestKafkaConsumer_UnmarshalLossMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
 			telemetryMsg, err := kafkaConsumer.UnmarshalTelemetryMessage(tt.args.message)
 			assert.NoError(t, err)
 			isisMsg, err := kafkaConsumer.UnmarshalLossMessage(*telemetryMsg)
@@ -673,7 +946,7 @@ func TestKafkaConsumer_UnmarshalLossMessage(t *testing.T) {
 
 func TestKafkaConsumer_UnmarshalBandwidthMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -689,7 +962,7 @@ func TestKafkaConsumer_UnmarshalBandwidthMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal valid Bandwidth message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -717,7 +990,7 @@ func TestKafkaConsumer_UnmarshalBandwidthMessage(t *testing.T) {
 		{
 			name: "Test Unmarshal invalid Bandwidth message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -745,7 +1018,7 @@ func TestKafkaConsumer_UnmarshalBandwidthMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadletter.NewMultiWriter())
 			telemetryMsg, err := kafkaConsumer.UnmarshalTelemetryMessage(tt.args.message)
 			assert.NoError(t, err)
 			bwMsg, err := kafkaConsumer.UnmarshalBandwidthMessage(*telemetryMsg)
@@ -760,9 +1033,62 @@ func TestKafkaConsumer_UnmarshalBandwidthMessage(t *testing.T) {
 	}
 }
 
+func TestKafkaConsumer_UnmarshalUtilizationMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		fields        map[string]interface{}
+		wantInOctets  uint64
+		wantOutOctets uint64
+		wantErr       bool
+	}{
+		{
+			name:          "Test Unmarshal Utilization Message with json.Number counters",
+			fields:        map[string]interface{}{inOctetsField: json.Number("18446744073709551615"), outOctetsField: json.Number("2500000"), "in_unicast_pkts": json.Number("1000")},
+			wantInOctets:  18446744073709551615,
+			wantOutOctets: 2500000,
+		},
+		{
+			name:          "Test Unmarshal Utilization Message with float64 counters",
+			fields:        map[string]interface{}{inOctetsField: float64(1250000), outOctetsField: float64(2500000)},
+			wantInOctets:  1250000,
+			wantOutOctets: 2500000,
+		},
+		{
+			name:    "Test Unmarshal Utilization Message without out_octets",
+			fields:  map[string]interface{}{inOctetsField: json.Number("1250000")},
+			wantErr: true,
+		},
+		{
+			name:    "Test Unmarshal Utilization Message with negative counter",
+			fields:  map[string]interface{}{inOctetsField: json.Number("-1"), outOctetsField: json.Number("2500000")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			telemetryMessage := TelemetryMessage{
+				Fields:    tt.fields,
+				Name:      "utilization",
+				Tags:      MessageTags{"name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+				Timestamp: 1704728135,
+			}
+			msg, err := kafkaConsumer.UnmarshalUtilizationMessage(telemetryMessage)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantInOctets, msg.InOctets)
+			assert.Equal(t, tt.wantOutOctets, msg.OutOctets)
+			assert.Equal(t, tt.fields, msg.Fields)
+		})
+	}
+}
+
 func TestKafkaConsumer_processMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker        string
+		kafkaBrokers       []string
 		kafkaTopic         string
 		unprocessedMsgChan chan Message
 	}
@@ -778,7 +1104,7 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 		{
 			name: "Test processMessage with packet loss message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -806,7 +1132,7 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 		{
 			name: "Test processMessage with delay message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -837,7 +1163,7 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 		{
 			name: "Test processMessage with bandwidth message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -862,10 +1188,38 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Test processMessage with utilization message",
+			fields: fields{
+				kafkaBrokers:       []string{"localhost:9092"},
+				kafkaTopic:         "test",
+				unprocessedMsgChan: make(chan Message),
+			},
+			args: args{
+				message: &sarama.ConsumerMessage{
+					Value: []byte(`{"fields":{"in_octets":1250000,"out_octets":2500000},"name":"utilization","tags":{"name":"GigabitEthernet0/0/0/0","source":"XR-1"},"timestamp":1704728369}`),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test processMessage with invalid utilization message",
+			fields: fields{
+				kafkaBrokers:       []string{"localhost:9092"},
+				kafkaTopic:         "test",
+				unprocessedMsgChan: make(chan Message),
+			},
+			args: args{
+				message: &sarama.ConsumerMessage{
+					Value: []byte(`{"fields":{"in_octets":"invalid","out_octets":2500000},"name":"utilization","tags":{"name":"GigabitEthernet0/0/0/0","source":"XR-1"},"timestamp":1704728369}`),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Test processMessage with invalid isis bandwidth message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -893,7 +1247,7 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 		{
 			name: "Test processMessage with invalid unknown message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -921,7 +1275,7 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 		{
 			name: "Test processMessage with invalid message",
 			fields: fields{
-				kafkaBroker:        "localhost:9092",
+				kafkaBrokers:       []string{"localhost:9092"},
 				kafkaTopic:         "test",
 				unprocessedMsgChan: make(chan Message),
 			},
@@ -937,8 +1291,16 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
-			go kafkaConsumer.processMessage(tt.args.message)
+			deadLetter := deadletter.NewMockWriter(gomock.NewController(t))
+			if tt.wantErr {
+				deadLetter.EXPECT().Write(gomock.Any()).Do(func(letter *deadletter.Letter) {
+					assert.Equal(t, deadletter.StageConsumer, letter.Stage)
+					assert.Equal(t, string(tt.args.message.Value), letter.Payload)
+					assert.NotEmpty(t, letter.Reason)
+				})
+			}
+			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, tt.fields.unprocessedMsgChan, deadLetter)
+			go kafkaConsumer.processMessage(context.Background(), tt.args.message)
 			time.Sleep(1 * time.Second)
 			if tt.wantErr {
 				select {
@@ -959,35 +1321,427 @@ func TestKafkaConsumer_processMessage(t *testing.T) {
 	}
 }
 
-func TestKafkaConsumer_Stop(t *testing.T) {
-	type fields struct {
-		kafkaBroker        string
-		kafkaTopic         string
-		unprocessedMsgChan chan Message
+func TestKafkaConsumer_processMessage_Passthrough(t *testing.T) {
+	errorsMessage := []byte(`{"fields":{"in_errors":47912820356,"out_errors":1864216230},"name":"errors","tags":{"host":"telegraf","name":"GigabitEthernet0/0/0/0","path":"openconfig-interfaces:interfaces/interface/state/counters","source":"XR-1","subscription":"hawk-metrics"},"timestamp":1704728433}`)
+	tests := []struct {
+		name                string
+		value               []byte
+		excludeMeasurements []string
+		want                *PassthroughMessage
+		wantDeadLetter      bool
+	}{
+		{
+			name:  "Test passthrough of unknown measurement",
+			value: errorsMessage,
+			want: &PassthroughMessage{
+				TelemetryMessage: TelemetryMessage{
+					Fields: map[string]interface{}{
+						"in_errors":  json.Number("47912820356"),
+						"out_errors": json.Number("1864216230"),
+					},
+					Name: "errors",
+					Tags: map[string]string{
+						"host":         "telegraf",
+						"name":         "GigabitEthernet0/0/0/0",
+						"path":         "openconfig-interfaces:interfaces/interface/state/counters",
+						"source":       "XR-1",
+						"subscription": "hawk-metrics",
+					},
+					Timestamp: 1704728433,
+				},
+			},
+		},
+		{
+			name:  "Test passthrough of ISIS message without known fields",
+			value: []byte(`{"fields":{"interface_status_and_data/enabled/adjacency_count":1},"name":"isis","tags":{"interface_name":"GigabitEthernet0/0/0/0","source":"XR-1"},"timestamp":1704728369}`),
+			want: &PassthroughMessage{
+				TelemetryMessage: TelemetryMessage{
+					Fields:    map[string]interface{}{"interface_status_and_data/enabled/adjacency_count": json.Number("1")},
+					Name:      "isis",
+					Tags:      map[string]string{"interface_name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+					Timestamp: 1704728369,
+				},
+			},
+		},
+		{
+			name:                "Test excluded measurement is skipped",
+			value:               errorsMessage,
+			excludeMeasurements: []string{"errors"},
+		},
+		{
+			name:           "Test passthrough of measurement with invalid tags",
+			value:          []byte(`{"fields":{"in_errors":1},"name":"errors","tags":{"instance":1},"timestamp":1704728433}`),
+			wantDeadLetter: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewPassthroughFilter(nil, tt.excludeMeasurements, nil, nil)
+			assert.NoError(t, err)
+			deadLetter := deadletter.NewMockWriter(gomock.NewController(t))
+			if tt.wantDeadLetter {
+				deadLetter.EXPECT().Write(gomock.Any())
+			}
+			msgChan := make(chan Message, 1)
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, filter, msgChan, deadLetter)
+			assert.True(t, kafkaConsumer.processMessage(context.Background(), &sarama.ConsumerMessage{Value: tt.value}))
+			if tt.want != nil {
+				assert.Equal(t, tt.want, <-msgChan)
+			} else {
+				assert.Empty(t, msgChan)
+			}
+		})
 	}
+}
+
+func TestKafkaConsumer_ConsumeClaim(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
+		name     string
+		messages []*sarama.ConsumerMessage
 	}{
 		{
-			name: "Test Stop function",
-			fields: fields{
-				kafkaBroker:        "localhost:9092",
-				kafkaTopic:         "test",
-				unprocessedMsgChan: make(chan Message),
+			name: "Test consume claim with delay message",
+			messages: []*sarama.ConsumerMessage{
+				{
+					Topic:     "test",
+					Partition: 1,
+					Offset:    42,
+					Value: []byte(`{
+						"fields": {
+						  "delay_measurement_session/last_advertisement_information/advertised_values/average": 10000,
+						  "delay_measurement_session/last_advertisement_information/advertised_values/maximum": 10000,
+						  "delay_measurement_session/last_advertisement_information/advertised_values/minimum": 10000,
+						  "delay_measurement_session/last_advertisement_information/advertised_values/variance": 0
+						},
+						"name": "performance-measurement",
+						"tags": {
+						  "interface_name": "GigabitEthernet0/0/0/1",
+						  "source": "XR-1"
+						},
+						"timestamp": 1704728135
+					  }`),
+				},
+			},
+		},
+		{
+			name: "Test consume claim with invalid message",
+			messages: []*sarama.ConsumerMessage{
+				{
+					Topic:     "test",
+					Partition: 0,
+					Offset:    1,
+					Value:     []byte(`{"name": "unknown",}`),
+				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaConsumer := NewKafkaConsumer(tt.fields.kafkaBroker, tt.fields.kafkaTopic, tt.fields.unprocessedMsgChan)
-			consumer := mocks.NewConsumer(t, nil)
-			consumer.ExpectConsumePartition(tt.fields.kafkaTopic, 0, sarama.OffsetNewest)
-			kafkaConsumer.saramaConsumer = consumer
-			assert.NoError(t, kafkaConsumer.createParitionConsumer())
-			go kafkaConsumer.Start()
-			time.Sleep(1 * time.Second)
-			assert.NoError(t, kafkaConsumer.Stop())
+			msgChan := make(chan Message, len(tt.messages))
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, msgChan, deadletter.NewMultiWriter())
+			claim := newFakeConsumerGroupClaim("test", 0, tt.messages)
+			session := newFakeConsumerGroupSession(context.Background())
+			assert.NoError(t, kafkaConsumer.Setup(session))
+			assert.NoError(t, kafkaConsumer.ConsumeClaim(session, claim))
+			assert.NoError(t, kafkaConsumer.Cleanup(session))
+			assert.Equal(t, tt.messages, session.marked)
+		})
+	}
+}
+
+func TestKafkaConsumer_ConsumeClaim_SessionDone(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test consume claim returns when session ends",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			claim := &fakeConsumerGroupClaim{topic: "test", messages: make(chan *sarama.ConsumerMessage)}
+			ctx, cancel := context.WithCancel(context.Background())
+			session := newFakeConsumerGroupSession(ctx)
+			cancel()
+			assert.NoError(t, kafkaConsumer.ConsumeClaim(session, claim))
+		})
+	}
+}
+
+func TestKafkaConsumer_ConsumeClaim_BlockedPipeline(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test consume claim does not mark message if session ends while pipeline is blocked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			message := &sarama.ConsumerMessage{
+				Topic: "test",
+				Value: []byte(`{"fields":{"delay_measurement_session/last_advertisement_information/advertised_values/average":8553,"delay_measurement_session/last_advertisement_information/advertised_values/maximum":8836,"delay_measurement_session/last_advertisement_information/advertised_values/minimum":8242,"delay_measurement_session/last_advertisement_information/advertised_values/variance":307},"name":"performance-measurement","tags":{"host":"telegraf","interface_name":"GigabitEthernet0/0/0/0","node":"0/RP0/CPU0","path":"Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail","source":"XR-1","subscription":"hawk-metrics"},"timestamp":1704728135}`),
+			}
+			claim := newFakeConsumerGroupClaim("test", 0, []*sarama.ConsumerMessage{message})
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			session := newFakeConsumerGroupSession(ctx)
+			assert.NoError(t, kafkaConsumer.ConsumeClaim(session, claim))
+			assert.Empty(t, session.marked)
+		})
+	}
+}
+
+func TestKafkaConsumer_Start(t *testing.T) {
+	tests := []struct {
+		name       string
+		consumeErr error
+		wantErr    bool
+	}{
+		{
+			name:       "Test Start returns error if consuming fails",
+			consumeErr: sarama.ErrOutOfBrokers,
+			wantErr:    true,
+		},
+		{
+			name:       "Test Start returns without error if group is closed",
+			consumeErr: sarama.ErrClosedConsumerGroup,
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			kafkaConsumer.ctx, kafkaConsumer.cancel = context.WithCancel(context.Background())
+			kafkaConsumer.saramaConsumerGroup = &fakeConsumerGroup{consumeErr: tt.consumeErr}
+			if tt.wantErr {
+				assert.ErrorIs(t, kafkaConsumer.Start(), tt.consumeErr)
+			} else {
+				assert.NoError(t, kafkaConsumer.Start())
+			}
+		})
+	}
+}
+
+func TestKafkaConsumer_Stop(t *testing.T) {
+	tests := []struct {
+		name        string
+		initialized bool
+		closeErr    error
+		wantErr     bool
+	}{
+		{
+			name:        "Test Stop function",
+			initialized: true,
+			wantErr:     false,
+		},
+		{
+			name:        "Test Stop function with already closed group",
+			initialized: true,
+			closeErr:    sarama.ErrClosedConsumerGroup,
+			wantErr:     false,
+		},
+		{
+			name:        "Test Stop function with close error",
+			initialized: true,
+			closeErr:    errors.New("close error"),
+			wantErr:     true,
+		},
+		{
+			name:        "Test Stop function without successful init",
+			initialized: false,
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadletter.NewMultiWriter())
+			if tt.initialized {
+				kafkaConsumer.ctx, kafkaConsumer.cancel = context.WithCancel(context.Background())
+				kafkaConsumer.saramaConsumerGroup = &fakeConsumerGroup{closeErr: tt.closeErr}
+				kafkaConsumer.saramaClient = &fakeClient{}
+				stopped := make(chan error)
+				go func() {
+					stopped <- kafkaConsumer.Start()
+				}()
+				time.Sleep(100 * time.Millisecond)
+				if tt.wantErr {
+					assert.Error(t, kafkaConsumer.Stop())
+				} else {
+					assert.NoError(t, kafkaConsumer.Stop())
+				}
+				assert.NoError(t, <-stopped)
+			} else {
+				assert.NoError(t, kafkaConsumer.Stop())
+			}
+		})
+	}
+}
+
+type fakeConsumerGroup struct {
+	sarama.ConsumerGroup
+	consumeErr error
+	closeErr   error
+}
+
+func (group *fakeConsumerGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	if group.consumeErr != nil {
+		return group.consumeErr
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (group *fakeConsumerGroup) Close() error {
+	return group.closeErr
+}
+
+type fakeClient struct {
+	sarama.Client
+	offsets   map[int64]int64
+	offsetErr error
+}
+
+func (client *fakeClient) GetOffset(topic string, partitionID int32, time int64) (int64, error) {
+	return client.offsets[time], client.offsetErr
+}
+
+func (client *fakeClient) Close() error {
+	return nil
+}
+
+// fakeConsumerGroupSession tracks the offsets like the partition offset manager of sarama:
+// a partition without committed offset starts at -1, ResetOffset only moves backwards and MarkOffset only forwards
+type fakeConsumerGroupSession struct {
+	sarama.ConsumerGroupSession
+	ctx     context.Context
+	claims  map[string][]int32
+	marked  []*sarama.ConsumerMessage
+	offsets map[string]int64
+	moved   map[string]bool
+}
+
+func newFakeConsumerGroupSession(ctx context.Context) *fakeConsumerGroupSession {
+	return &fakeConsumerGroupSession{ctx: ctx, offsets: make(map[string]int64), moved: make(map[string]bool)}
+}
+
+func (session *fakeConsumerGroupSession) offset(topic string, partition int32) int64 {
+	if offset, ok := session.offsets[fmt.Sprintf("%s/%d", topic, partition)]; ok {
+		return offset
+	}
+	return -1
+}
+
+// startOffset returns the offset the claim of the partition starts from, like the consumer group session of sarama
+func (session *fakeConsumerGroupSession) startOffset(topic string, partition int32, initial int64) int64 {
+	if offset := session.offset(topic, partition); offset >= 0 {
+		return offset
+	}
+	return initial
+}
+
+func (session *fakeConsumerGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	if offset <= session.offset(topic, partition) {
+		session.offsets[fmt.Sprintf("%s/%d", topic, partition)] = offset
+		session.moved[fmt.Sprintf("%s/%d", topic, partition)] = true
+	}
+}
+
+func (session *fakeConsumerGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	if offset > session.offset(topic, partition) {
+		session.offsets[fmt.Sprintf("%s/%d", topic, partition)] = offset
+		session.moved[fmt.Sprintf("%s/%d", topic, partition)] = true
+	}
+}
+
+func (session *fakeConsumerGroupSession) Claims() map[string][]int32 {
+	if session.claims != nil {
+		return session.claims
+	}
+	return map[string][]int32{"test": {0}}
+}
+
+func (session *fakeConsumerGroupSession) Context() context.Context {
+	return session.ctx
+}
+
+func (session *fakeConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	session.marked = append(session.marked, msg)
+}
+
+type fakeConsumerGroupClaim struct {
+	sarama.ConsumerGroupClaim
+	topic     string
+	partition int32
+	messages  chan *sarama.ConsumerMessage
+}
+
+func newFakeConsumerGroupClaim(topic string, partition int32, messages []*sarama.ConsumerMessage) *fakeConsumerGroupClaim {
+	claim := &fakeConsumerGroupClaim{
+		topic:     topic,
+		partition: partition,
+		messages:  make(chan *sarama.ConsumerMessage, len(messages)),
+	}
+	for _, message := range messages {
+		claim.messages <- message
+	}
+	close(claim.messages)
+	return claim
+}
+
+func (claim *fakeConsumerGroupClaim) Topic() string {
+	return claim.topic
+}
+
+func (claim *fakeConsumerGroupClaim) Partition() int32 {
+	return claim.partition
+}
+
+func (claim *fakeConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return claim.messages
+}
+
+func TestKafkaConsumer_processMessage_Metrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       []byte
+		measurement string
+		reason      string
+	}{
+		{
+			name:   "Test invalid JSON is counted",
+			value:  []byte(`{"name":`),
+			reason: metrics.ReasonInvalidJSON,
+		},
+		{
+			name:        "Test invalid fields are counted",
+			value:       []byte(`{"fields":{"in_octets":"a"},"name":"utilization","tags":{"source":"XR-1"},"timestamp":1704728433}`),
+			measurement: "utilization",
+			reason:      metrics.ReasonInvalidFields,
+		},
+		{
+			name:        "Test unknown measurement is counted",
+			value:       []byte(`{"fields":{"in_errors":1},"name":"errors","tags":{"source":"XR-1"},"timestamp":1704728433}`),
+			measurement: "errors",
+			reason:      metrics.ReasonUnknownMeasurement,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := ""
+			if tt.measurement != "" {
+				node = "XR-1"
+			}
+			received := testutil.ToFloat64(metrics.ConsumerReceived.WithLabelValues(tt.measurement, node))
+			dropped := testutil.ToFloat64(metrics.ConsumerDropped.WithLabelValues(tt.measurement, node, tt.reason))
+			deadLetter := deadletter.NewMockWriter(gomock.NewController(t))
+			deadLetter.EXPECT().Write(gomock.Any())
+			kafkaConsumer := NewKafkaConsumer([]string{"localhost:9092"}, "test", "clab-telemetry-linker", "range", sarama.OffsetNewest, nil, nil, make(chan Message), deadLetter)
+			assert.True(t, kafkaConsumer.processMessage(context.Background(), &sarama.ConsumerMessage{Value: tt.value}))
+			assert.Equal(t, received+1, testutil.ToFloat64(metrics.ConsumerReceived.WithLabelValues(tt.measurement, node)))
+			assert.Equal(t, dropped+1, testutil.ToFloat64(metrics.ConsumerDropped.WithLabelValues(tt.measurement, node, tt.reason)))
 		})
 	}
 }
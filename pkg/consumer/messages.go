@@ -1,9 +1,37 @@
 package consumer
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrUnknownIsisMessage is returned for ISIS messages without loss or bandwidth fields
+var ErrUnknownIsisMessage = errors.New("Received unknown ISIS message")
+
+const (
+	averageDelayField  = "delay_measurement_session/last_advertisement_information/advertised_values/average"
+	maximumDelayField  = "delay_measurement_session/last_advertisement_information/advertised_values/maximum"
+	minimumDelayField  = "delay_measurement_session/last_advertisement_information/advertised_values/minimum"
+	delayVarianceField = "delay_measurement_session/last_advertisement_information/advertised_values/variance"
+	lossField          = "interface_status_and_data/enabled/packet_loss_percentage"
+	bandwidthField     = "interface_status_and_data/enabled/bandwidth"
+	inOctetsField      = "in_octets"
+	outOctetsField     = "out_octets"
+)
+
+// Message keeps all tags and fields of the received measurement.
+// GetFields returns the original fields with the values impaired by the processor replaced.
 type Message interface {
 	isMessage()
+	GetName() string
+	GetTags() MessageTags
+	GetFields() map[string]interface{}
+	GetTimestamp() int64
 }
 
+// TelemetryMessage is a measurement as received from Telegraf, numbers are kept as json.Number to preserve integers
 type TelemetryMessage struct {
 	Fields    map[string]interface{} `json:"fields,omitempty"`
 	Name      string                 `json:"name,omitempty"`
@@ -11,13 +39,31 @@ type TelemetryMessage struct {
 	Timestamp int64                  `json:"timestamp,omitempty"`
 }
 
-type MessageTags struct {
-	Host          string `json:"host,omitempty"`
-	InterfaceName string `json:"interface_name,omitempty"`
-	Node          string `json:"node"`
-	Path          string `json:"path,omitempty"`
-	Source        string `json:"source,omitempty"`
-	Subscription  string `json:"subscription,omitempty"`
+// MessageTags holds all tags of a measurement
+type MessageTags map[string]string
+
+func (tags MessageTags) Host() string {
+	return tags["host"]
+}
+
+func (tags MessageTags) InterfaceName() string {
+	return tags["interface_name"]
+}
+
+func (tags MessageTags) Node() string {
+	return tags["node"]
+}
+
+func (tags MessageTags) Path() string {
+	return tags["path"]
+}
+
+func (tags MessageTags) Source() string {
+	return tags["source"]
+}
+
+func (tags MessageTags) Subscription() string {
+	return tags["subscription"]
 }
 
 type DelayMessage struct {
@@ -38,4 +84,111 @@ type BandwidthMessage struct {
 	Bandwidth float64 `json:"interface_status_and_data/enabled/bandwidth,omitempty"`
 }
 
+// UtilizationMessage holds the openconfig interface counters, the interface is identified by the name tag
+type UtilizationMessage struct {
+	TelemetryMessage
+	InOctets  uint64 `json:"in_octets,omitempty"`
+	OutOctets uint64 `json:"out_octets,omitempty"`
+}
+
+func (msg UtilizationMessage) GetInterfaceName() string {
+	return msg.Tags["name"]
+}
+
+// PassthroughMessage is a measurement which is not modified by the linker
+type PassthroughMessage struct {
+	TelemetryMessage
+}
+
 func (TelemetryMessage) isMessage() {}
+
+func (msg TelemetryMessage) GetName() string {
+	return msg.Name
+}
+
+func (msg TelemetryMessage) GetTags() MessageTags {
+	return msg.Tags
+}
+
+func (msg TelemetryMessage) GetFields() map[string]interface{} {
+	return msg.Fields
+}
+
+func (msg TelemetryMessage) GetTimestamp() int64 {
+	return msg.Timestamp
+}
+
+// overrideFields returns a copy of the original fields with the given values replaced
+func (msg TelemetryMessage) overrideFields(overrides map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(msg.Fields)+len(overrides))
+	for key, value := range msg.Fields {
+		fields[key] = value
+	}
+	for key, value := range overrides {
+		fields[key] = value
+	}
+	return fields
+}
+
+// withoutField returns a copy of the message without the given field
+func (msg TelemetryMessage) withoutField(key string) TelemetryMessage {
+	fields := make(map[string]interface{}, len(msg.Fields))
+	for fieldKey, value := range msg.Fields {
+		if fieldKey != key {
+			fields[fieldKey] = value
+		}
+	}
+	msg.Fields = fields
+	return msg
+}
+
+func (msg DelayMessage) GetFields() map[string]interface{} {
+	return msg.overrideFields(map[string]interface{}{
+		averageDelayField:  float64(msg.Average),
+		maximumDelayField:  float64(msg.Maximum),
+		minimumDelayField:  float64(msg.Minimum),
+		delayVarianceField: float64(msg.Variance),
+	})
+}
+
+func (msg LossMessage) GetFields() map[string]interface{} {
+	return msg.overrideFields(map[string]interface{}{lossField: msg.LossPercentage})
+}
+
+func (msg BandwidthMessage) GetFields() map[string]interface{} {
+	return msg.overrideFields(map[string]interface{}{bandwidthField: msg.Bandwidth})
+}
+
+func (msg UtilizationMessage) GetFields() map[string]interface{} {
+	return msg.overrideFields(map[string]interface{}{
+		inOctetsField:  int64(msg.InOctets),
+		outOctetsField: int64(msg.OutOctets),
+	})
+}
+
+// getUint64Field converts a counter field, numbers are decoded as json.Number but float64 is accepted as well
+func (msg TelemetryMessage) getUint64Field(key string) (uint64, error) {
+	switch value := msg.Fields[key].(type) {
+	case json.Number:
+		return strconv.ParseUint(value.String(), 10, 64)
+	case float64:
+		if value < 0 {
+			return 0, fmt.Errorf("unable to convert negative %s to uint64", key)
+		}
+		return uint64(value), nil
+	default:
+		return 0, fmt.Errorf("unable to convert %s to uint64", key)
+	}
+}
+
+// getFloat64Field converts a numeric field, numbers are decoded as json.Number but float64 is accepted as well
+func (msg TelemetryMessage) getFloat64Field(key string) (float64, error) {
+	switch value := msg.Fields[key].(type) {
+	case json.Number:
+		return value.Float64()
+	case float64:
+		return value, nil
+	default:
+		return 0, fmt.Errorf("unable to convert %s to float64", key)
+	}
+}
@@ -1,6 +1,7 @@
 package consumer
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,3 +21,113 @@ func TestTelemetryMessage_isMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags MessageTags
+	}{
+		{
+			name: "Test MessageTags accessors",
+			tags: MessageTags{
+				"host":           "telegraf",
+				"instance_name":  "1",
+				"interface_name": "GigabitEthernet0/0/0/0",
+				"node":           "0/RP0/CPU0",
+				"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+				"source":         "XR-1",
+				"subscription":   "hawk-metrics",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, "telegraf", tt.tags.Host())
+			assert.Equal(t, "GigabitEthernet0/0/0/0", tt.tags.InterfaceName())
+			assert.Equal(t, "0/RP0/CPU0", tt.tags.Node())
+			assert.Equal(t, "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface", tt.tags.Path())
+			assert.Equal(t, "XR-1", tt.tags.Source())
+			assert.Equal(t, "hawk-metrics", tt.tags.Subscription())
+		})
+	}
+}
+
+func TestMessage_GetFields(t *testing.T) {
+	telemetryMessage := TelemetryMessage{
+		Fields: map[string]interface{}{
+			averageDelayField:    json.Number("8553"),
+			maximumDelayField:    json.Number("8836"),
+			minimumDelayField:    json.Number("8242"),
+			delayVarianceField:   json.Number("307"),
+			lossField:            json.Number("0"),
+			bandwidthField:       json.Number("1000000"),
+			"additional_counter": json.Number("42"),
+		},
+		Name:      "test",
+		Tags:      MessageTags{"source": "XR-1"},
+		Timestamp: 1704728135,
+	}
+	tests := []struct {
+		name      string
+		msg       Message
+		overrides map[string]interface{}
+	}{
+		{
+			name: "Test TelemetryMessage keeps all fields",
+			msg:  &telemetryMessage,
+		},
+		{
+			name: "Test PassthroughMessage keeps all fields",
+			msg:  &PassthroughMessage{TelemetryMessage: telemetryMessage},
+		},
+		{
+			name: "Test DelayMessage overrides delay fields",
+			msg:  &DelayMessage{TelemetryMessage: telemetryMessage, Average: 10000, Maximum: 11000, Minimum: 9000, Variance: 2000},
+			overrides: map[string]interface{}{
+				averageDelayField:  float64(10000),
+				maximumDelayField:  float64(11000),
+				minimumDelayField:  float64(9000),
+				delayVarianceField: float64(2000),
+			},
+		},
+		{
+			name:      "Test LossMessage overrides loss field",
+			msg:       &LossMessage{TelemetryMessage: telemetryMessage, LossPercentage: 2.5},
+			overrides: map[string]interface{}{lossField: 2.5},
+		},
+		{
+			name:      "Test BandwidthMessage overrides bandwidth field",
+			msg:       &BandwidthMessage{TelemetryMessage: telemetryMessage, Bandwidth: 500000},
+			overrides: map[string]interface{}{bandwidthField: float64(500000)},
+		},
+		{
+			name: "Test UtilizationMessage overrides octet counters as integers",
+			msg:  &UtilizationMessage{TelemetryMessage: telemetryMessage, InOctets: 1250000, OutOctets: 2500000},
+			overrides: map[string]interface{}{
+				inOctetsField:  int64(1250000),
+				outOctetsField: int64(2500000),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make(map[string]interface{})
+			for key, value := range telemetryMessage.Fields {
+				want[key] = value
+			}
+			for key, value := range tt.overrides {
+				want[key] = value
+			}
+			assert.Equal(t, want, tt.msg.GetFields())
+			assert.Equal(t, "test", tt.msg.GetName())
+			assert.Equal(t, telemetryMessage.Tags, tt.msg.GetTags())
+			assert.Equal(t, int64(1704728135), tt.msg.GetTimestamp())
+			assert.Equal(t, json.Number("8553"), telemetryMessage.Fields[averageDelayField], "original fields must not be modified")
+		})
+	}
+}
+
+func TestUtilizationMessage_GetInterfaceName(t *testing.T) {
+	msg := UtilizationMessage{TelemetryMessage: TelemetryMessage{Tags: MessageTags{"name": "GigabitEthernet0/0/0/0"}}}
+	assert.Equal(t, "GigabitEthernet0/0/0/0", msg.GetInterfaceName())
+}
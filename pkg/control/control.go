@@ -0,0 +1,8 @@
+package control
+
+var subsystem = "control"
+
+type Server interface {
+	Start()
+	Stop()
+}
@@ -0,0 +1,295 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: control.proto
+
+package controlpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ImpairmentService_ListImpairments_FullMethodName   = "/clabtelemetrylinker.control.v1.ImpairmentService/ListImpairments"
+	ImpairmentService_GetImpairments_FullMethodName    = "/clabtelemetrylinker.control.v1.ImpairmentService/GetImpairments"
+	ImpairmentService_SetImpairments_FullMethodName    = "/clabtelemetrylinker.control.v1.ImpairmentService/SetImpairments"
+	ImpairmentService_DeleteImpairments_FullMethodName = "/clabtelemetrylinker.control.v1.ImpairmentService/DeleteImpairments"
+	ImpairmentService_WatchImpairments_FullMethodName  = "/clabtelemetrylinker.control.v1.ImpairmentService/WatchImpairments"
+)
+
+// ImpairmentServiceClient is the client API for ImpairmentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ImpairmentServiceClient interface {
+	// ListImpairments returns the impairments of all interfaces of a node
+	ListImpairments(ctx context.Context, in *ListImpairmentsRequest, opts ...grpc.CallOption) (*ListImpairmentsResponse, error)
+	// GetImpairments returns the impairments of an interface, NOT_FOUND if the interface has none
+	GetImpairments(ctx context.Context, in *GetImpairmentsRequest, opts ...grpc.CallOption) (*GetImpairmentsResponse, error)
+	// SetImpairments replaces the impairments of an interface, omitted values are reset to 0
+	SetImpairments(ctx context.Context, in *SetImpairmentsRequest, opts ...grpc.CallOption) (*SetImpairmentsResponse, error)
+	// DeleteImpairments removes the impairments of an interface
+	DeleteImpairments(ctx context.Context, in *DeleteImpairmentsRequest, opts ...grpc.CallOption) (*DeleteImpairmentsResponse, error)
+	// WatchImpairments streams every change of the configured impairments until the client cancels
+	WatchImpairments(ctx context.Context, in *WatchImpairmentsRequest, opts ...grpc.CallOption) (ImpairmentService_WatchImpairmentsClient, error)
+}
+
+type impairmentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewImpairmentServiceClient(cc grpc.ClientConnInterface) ImpairmentServiceClient {
+	return &impairmentServiceClient{cc}
+}
+
+func (c *impairmentServiceClient) ListImpairments(ctx context.Context, in *ListImpairmentsRequest, opts ...grpc.CallOption) (*ListImpairmentsResponse, error) {
+	out := new(ListImpairmentsResponse)
+	err := c.cc.Invoke(ctx, ImpairmentService_ListImpairments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *impairmentServiceClient) GetImpairments(ctx context.Context, in *GetImpairmentsRequest, opts ...grpc.CallOption) (*GetImpairmentsResponse, error) {
+	out := new(GetImpairmentsResponse)
+	err := c.cc.Invoke(ctx, ImpairmentService_GetImpairments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *impairmentServiceClient) SetImpairments(ctx context.Context, in *SetImpairmentsRequest, opts ...grpc.CallOption) (*SetImpairmentsResponse, error) {
+	out := new(SetImpairmentsResponse)
+	err := c.cc.Invoke(ctx, ImpairmentService_SetImpairments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *impairmentServiceClient) DeleteImpairments(ctx context.Context, in *DeleteImpairmentsRequest, opts ...grpc.CallOption) (*DeleteImpairmentsResponse, error) {
+	out := new(DeleteImpairmentsResponse)
+	err := c.cc.Invoke(ctx, ImpairmentService_DeleteImpairments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *impairmentServiceClient) WatchImpairments(ctx context.Context, in *WatchImpairmentsRequest, opts ...grpc.CallOption) (ImpairmentService_WatchImpairmentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ImpairmentService_ServiceDesc.Streams[0], ImpairmentService_WatchImpairments_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &impairmentServiceWatchImpairmentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ImpairmentService_WatchImpairmentsClient interface {
+	Recv() (*ImpairmentEvent, error)
+	grpc.ClientStream
+}
+
+type impairmentServiceWatchImpairmentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *impairmentServiceWatchImpairmentsClient) Recv() (*ImpairmentEvent, error) {
+	m := new(ImpairmentEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ImpairmentServiceServer is the server API for ImpairmentService service.
+// All implementations must embed UnimplementedImpairmentServiceServer
+// for forward compatibility
+type ImpairmentServiceServer interface {
+	// ListImpairments returns the impairments of all interfaces of a node
+	ListImpairments(context.Context, *ListImpairmentsRequest) (*ListImpairmentsResponse, error)
+	// GetImpairments returns the impairments of an interface, NOT_FOUND if the interface has none
+	GetImpairments(context.Context, *GetImpairmentsRequest) (*GetImpairmentsResponse, error)
+	// SetImpairments replaces the impairments of an interface, omitted values are reset to 0
+	SetImpairments(context.Context, *SetImpairmentsRequest) (*SetImpairmentsResponse, error)
+	// DeleteImpairments removes the impairments of an interface
+	DeleteImpairments(context.Context, *DeleteImpairmentsRequest) (*DeleteImpairmentsResponse, error)
+	// WatchImpairments streams every change of the configured impairments until the client cancels
+	WatchImpairments(*WatchImpairmentsRequest, ImpairmentService_WatchImpairmentsServer) error
+	mustEmbedUnimplementedImpairmentServiceServer()
+}
+
+// UnimplementedImpairmentServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedImpairmentServiceServer struct {
+}
+
+func (UnimplementedImpairmentServiceServer) ListImpairments(context.Context, *ListImpairmentsRequest) (*ListImpairmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImpairments not implemented")
+}
+func (UnimplementedImpairmentServiceServer) GetImpairments(context.Context, *GetImpairmentsRequest) (*GetImpairmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImpairments not implemented")
+}
+func (UnimplementedImpairmentServiceServer) SetImpairments(context.Context, *SetImpairmentsRequest) (*SetImpairmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetImpairments not implemented")
+}
+func (UnimplementedImpairmentServiceServer) DeleteImpairments(context.Context, *DeleteImpairmentsRequest) (*DeleteImpairmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteImpairments not implemented")
+}
+func (UnimplementedImpairmentServiceServer) WatchImpairments(*WatchImpairmentsRequest, ImpairmentService_WatchImpairmentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchImpairments not implemented")
+}
+func (UnimplementedImpairmentServiceServer) mustEmbedUnimplementedImpairmentServiceServer() {}
+
+// UnsafeImpairmentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ImpairmentServiceServer will
+// result in compilation errors.
+type UnsafeImpairmentServiceServer interface {
+	mustEmbedUnimplementedImpairmentServiceServer()
+}
+
+func RegisterImpairmentServiceServer(s grpc.ServiceRegistrar, srv ImpairmentServiceServer) {
+	s.RegisterService(&ImpairmentService_ServiceDesc, srv)
+}
+
+func _ImpairmentService_ListImpairments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImpairmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImpairmentServiceServer).ListImpairments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImpairmentService_ListImpairments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImpairmentServiceServer).ListImpairments(ctx, req.(*ListImpairmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImpairmentService_GetImpairments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImpairmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImpairmentServiceServer).GetImpairments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImpairmentService_GetImpairments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImpairmentServiceServer).GetImpairments(ctx, req.(*GetImpairmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImpairmentService_SetImpairments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetImpairmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImpairmentServiceServer).SetImpairments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImpairmentService_SetImpairments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImpairmentServiceServer).SetImpairments(ctx, req.(*SetImpairmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImpairmentService_DeleteImpairments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteImpairmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImpairmentServiceServer).DeleteImpairments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImpairmentService_DeleteImpairments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImpairmentServiceServer).DeleteImpairments(ctx, req.(*DeleteImpairmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImpairmentService_WatchImpairments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchImpairmentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImpairmentServiceServer).WatchImpairments(m, &impairmentServiceWatchImpairmentsServer{stream})
+}
+
+type ImpairmentService_WatchImpairmentsServer interface {
+	Send(*ImpairmentEvent) error
+	grpc.ServerStream
+}
+
+type impairmentServiceWatchImpairmentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *impairmentServiceWatchImpairmentsServer) Send(m *ImpairmentEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ImpairmentService_ServiceDesc is the grpc.ServiceDesc for ImpairmentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ImpairmentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "clabtelemetrylinker.control.v1.ImpairmentService",
+	HandlerType: (*ImpairmentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListImpairments",
+			Handler:    _ImpairmentService_ListImpairments_Handler,
+		},
+		{
+			MethodName: "GetImpairments",
+			Handler:    _ImpairmentService_GetImpairments_Handler,
+		},
+		{
+			MethodName: "SetImpairments",
+			Handler:    _ImpairmentService_SetImpairments_Handler,
+		},
+		{
+			MethodName: "DeleteImpairments",
+			Handler:    _ImpairmentService_DeleteImpairments_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchImpairments",
+			Handler:       _ImpairmentService_WatchImpairments_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "control.proto",
+}
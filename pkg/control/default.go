@@ -0,0 +1,304 @@
+package control
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/control/controlpb"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/topology"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const nodesKey = "nodes"
+
+type DefaultServer struct {
+	controlpb.UnimplementedImpairmentServiceServer
+	log          *logrus.Entry
+	config       config.Config
+	mapper       naming.Mapper
+	applier      scenario.Applier
+	newViewer    func(node string) (impairments.Viewer, error)
+	newValidator func() (topology.Validator, error)
+	feed         *changeFeed
+	address      string
+	token        string
+	grpcServer   *grpc.Server
+}
+
+// NewDefaultServer serves the gRPC service on address, the changes streamed by WatchImpairments are fed by the notifier
+func NewDefaultServer(config config.Config, notifier config.ChangeNotifier, helper helpers.Helper, mapper naming.Mapper, address, token string) *DefaultServer {
+	server := &DefaultServer{
+		log:     logging.DefaultLogger.WithField("subsystem", subsystem),
+		config:  config,
+		mapper:  mapper,
+		applier: scenario.NewDefaultApplier(config, helper, mapper, false),
+		newViewer: func(node string) (impairments.Viewer, error) {
+			showCommand, err := command.NewShowCommand(config.GetValue(command.BackendKey), node, config.GetValue(helper.GetDefaultClabNameKey()))
+			if err != nil {
+				return nil, err
+			}
+			return impairments.NewDefaultViewer(config, node, helper, showCommand), nil
+		},
+		newValidator: func() (topology.Validator, error) {
+			importedTopology, err := topology.Load(config, helper)
+			if err != nil {
+				return nil, err
+			}
+			return topology.NewDefaultValidator(importedTopology), nil
+		},
+		feed:    newChangeFeed(config),
+		address: address,
+		token:   token,
+	}
+	notifier.OnChange(server.feed.update)
+	server.grpcServer = grpc.NewServer(
+		grpc.UnaryInterceptor(server.authorizeUnary),
+		grpc.StreamInterceptor(server.authorizeStream),
+	)
+	controlpb.RegisterImpairmentServiceServer(server.grpcServer, server)
+	return server
+}
+
+func (server *DefaultServer) authorize(ctx context.Context) error {
+	if server.token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, found := strings.CutPrefix(value, "Bearer ")
+		if found && subtle.ConstantTimeCompare([]byte(token), []byte(server.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "Missing or invalid bearer token")
+}
+
+func (server *DefaultServer) authorizeUnary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := server.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+func (server *DefaultServer) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := server.authorize(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+func toProto(values scenario.Impairments) *controlpb.Impairments {
+	return &controlpb.Impairments{
+		DelayMs:               values.Delay,
+		JitterMs:              values.Jitter,
+		LossPercent:           values.Loss,
+		RateKbits:             values.Rate,
+		BackgroundLoadPercent: values.BackgroundLoad,
+	}
+}
+
+func fromProto(values *controlpb.Impairments) scenario.Impairments {
+	return scenario.Impairments{
+		Delay:          values.GetDelayMs(),
+		Jitter:         values.GetJitterMs(),
+		Loss:           values.GetLossPercent(),
+		Rate:           values.GetRateKbits(),
+		BackgroundLoad: values.GetBackgroundLoadPercent(),
+	}
+}
+
+func recordToProto(node string, record impairments.Record) *controlpb.InterfaceImpairments {
+	result := &controlpb.InterfaceImpairments{Node: node, Interface: record.Interface, InSync: record.InSync}
+	if record.Configured != nil {
+		result.Configured = toProto(scenario.Impairments{
+			Delay:          record.Configured.Delay,
+			Jitter:         record.Configured.Jitter,
+			Loss:           record.Configured.Loss,
+			Rate:           record.Configured.Rate,
+			BackgroundLoad: record.BackgroundLoad,
+		})
+	}
+	if record.Live != nil {
+		result.Live = toProto(scenario.Impairments{Delay: record.Live.Delay, Jitter: record.Live.Jitter, Loss: record.Live.Loss, Rate: record.Live.Rate})
+	}
+	return result
+}
+
+func eventToProto(event Event) *controlpb.ImpairmentEvent {
+	eventType := controlpb.ImpairmentEvent_TYPE_SET
+	if event.Current == (scenario.Impairments{}) {
+		eventType = controlpb.ImpairmentEvent_TYPE_DELETED
+	}
+	return &controlpb.ImpairmentEvent{
+		Timestamp: timestamppb.New(event.Timestamp),
+		Type:      eventType,
+		Node:      event.Node,
+		Interface: event.Interface,
+		Previous:  toProto(event.Previous),
+		Current:   toProto(event.Current),
+	}
+}
+
+func (server *DefaultServer) getInterfaceName(name string) string {
+	clabName, err := server.mapper.ToClabName(name)
+	if err != nil {
+		return name
+	}
+	return clabName
+}
+
+// validate checks the node and the interface against the imported topology, an empty interface only checks the node
+func (server *DefaultServer) validate(node, interface_ string) error {
+	if node == "" {
+		return status.Error(codes.InvalidArgument, "Node is required")
+	}
+	validator, err := server.newValidator()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if interface_ == "" {
+		err = validator.ValidateNode(node)
+	} else {
+		err = validator.ValidateInterface(node, interface_)
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func (server *DefaultServer) getRecords(node string) ([]impairments.Record, error) {
+	if err := server.validate(node, ""); err != nil {
+		return nil, err
+	}
+	viewer, err := server.newViewer(node)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	records, err := viewer.GetImpairments()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return records, nil
+}
+
+func (server *DefaultServer) ListImpairments(ctx context.Context, request *controlpb.ListImpairmentsRequest) (*controlpb.ListImpairmentsResponse, error) {
+	records, err := server.getRecords(request.GetNode())
+	if err != nil {
+		return nil, err
+	}
+	response := &controlpb.ListImpairmentsResponse{}
+	for _, record := range records {
+		response.Interfaces = append(response.Interfaces, recordToProto(request.GetNode(), record))
+	}
+	return response, nil
+}
+
+func (server *DefaultServer) GetImpairments(ctx context.Context, request *controlpb.GetImpairmentsRequest) (*controlpb.GetImpairmentsResponse, error) {
+	records, err := server.getRecords(request.GetNode())
+	if err != nil {
+		return nil, err
+	}
+	interface_ := server.getInterfaceName(request.GetInterface())
+	for _, record := range records {
+		if record.Interface == interface_ {
+			return &controlpb.GetImpairmentsResponse{Interface: recordToProto(request.GetNode(), record)}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "No impairments of interface %s on node %s", interface_, request.GetNode())
+}
+
+// apply sets the impairments in one transaction of the applier, a failed interface is restored to the previous impairments.
+// The transactions are serialized with those of the HTTP API and the scheduler by the applier
+func (server *DefaultServer) apply(node, name string, values scenario.Impairments) error {
+	if err := values.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "Interface is required")
+	}
+	interface_ := server.getInterfaceName(name)
+	if err := server.validate(node, interface_); err != nil {
+		return err
+	}
+	if _, err := server.applier.Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{node: {interface_: values}}}); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func (server *DefaultServer) SetImpairments(ctx context.Context, request *controlpb.SetImpairmentsRequest) (*controlpb.SetImpairmentsResponse, error) {
+	if err := server.apply(request.GetNode(), request.GetInterface(), fromProto(request.GetImpairments())); err != nil {
+		return nil, err
+	}
+	server.log.Infof("Set impairments of %s on node %s\n", request.GetInterface(), request.GetNode())
+	return &controlpb.SetImpairmentsResponse{}, nil
+}
+
+func (server *DefaultServer) DeleteImpairments(ctx context.Context, request *controlpb.DeleteImpairmentsRequest) (*controlpb.DeleteImpairmentsResponse, error) {
+	if err := server.apply(request.GetNode(), request.GetInterface(), scenario.Impairments{}); err != nil {
+		return nil, err
+	}
+	server.log.Infof("Deleted impairments of %s on node %s\n", request.GetInterface(), request.GetNode())
+	return &controlpb.DeleteImpairmentsResponse{}, nil
+}
+
+// WatchImpairments streams the changes until the client cancels, the server stops or the client is too slow
+func (server *DefaultServer) WatchImpairments(request *controlpb.WatchImpairmentsRequest, stream controlpb.ImpairmentService_WatchImpairmentsServer) error {
+	subscriber, err := server.feed.subscribe(request.GetNode())
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer server.feed.unsubscribe(subscriber)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscriber.events:
+			if !ok {
+				if subscriber.err != nil {
+					return status.Error(codes.ResourceExhausted, subscriber.err.Error())
+				}
+				return nil
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Start serves the gRPC service until Stop is called
+func (server *DefaultServer) Start() {
+	server.feed.update()
+	listener, err := net.Listen("tcp", server.address)
+	if err != nil {
+		server.log.Errorf("Error listening on %s: %v\n", server.address, err)
+		return
+	}
+	server.log.Infof("Serving gRPC control API on %s\n", listener.Addr())
+	if err := server.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		server.log.Errorf("Error serving gRPC control API: %v\n", err)
+	}
+}
+
+func (server *DefaultServer) Stop() {
+	server.log.Infoln("Stopping gRPC control API")
+	server.feed.close()
+	server.grpcServer.GracefulStop()
+}
@@ -0,0 +1,298 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/control/controlpb"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var defaultMapper, _ = naming.NewDefaultMapper(nil)
+
+type staticViewer struct {
+	records []impairments.Record
+	err     error
+}
+
+func (viewer staticViewer) GetImpairments() ([]impairments.Record, error) {
+	return viewer.records, viewer.err
+}
+
+// newTestClient serves the server on an in-memory listener
+func newTestClient(t *testing.T, server *DefaultServer) controlpb.ImpairmentServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = server.grpcServer.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	connection, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { connection.Close() })
+	return controlpb.NewImpairmentServiceClient(connection)
+}
+
+func newTestServer(ctrl *gomock.Controller, token string, viewer impairments.Viewer) (*DefaultServer, *config.MockConfig, *scenario.MockApplier) {
+	mockConfig := config.NewMockConfig(ctrl)
+	notifier := config.NewMockChangeNotifier(ctrl)
+	notifier.EXPECT().OnChange(gomock.Any())
+	applier := scenario.NewMockApplier(ctrl)
+	server := NewDefaultServer(mockConfig, notifier, helpers.NewMockHelper(ctrl), defaultMapper, "127.0.0.1:0", token)
+	server.applier = applier
+	server.newViewer = func(node string) (impairments.Viewer, error) {
+		return viewer, nil
+	}
+	server.newValidator = func() (topology.Validator, error) {
+		return topology.NewDefaultValidator(&topology.Topology{
+			Nodes: []string{"XR-1", "XR-2"},
+			Links: []link.Link{{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}}},
+		}), nil
+	}
+	return server, mockConfig, applier
+}
+
+func TestNewDefaultServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	notifier := config.NewMockChangeNotifier(ctrl)
+	notifier.EXPECT().OnChange(gomock.Any())
+	assert.NotNil(t, NewDefaultServer(config.NewMockConfig(ctrl), notifier, helpers.NewMockHelper(ctrl), defaultMapper, ":9090", ""))
+}
+
+func TestDefaultServer_GetImpairments(t *testing.T) {
+	records := []impairments.Record{
+		{Interface: "Gi0-0-0-0", Configured: &command.Impairments{Delay: 10}, BackgroundLoad: 5, Live: &command.Impairments{Delay: 10}, InSync: true},
+	}
+	tests := []struct {
+		name     string
+		request  *controlpb.GetImpairmentsRequest
+		viewer   staticViewer
+		want     *controlpb.InterfaceImpairments
+		wantCode codes.Code
+	}{
+		{
+			name:    "Test get interface with telemetry name",
+			request: &controlpb.GetImpairmentsRequest{Node: "XR-1", Interface: "GigabitEthernet0/0/0/0"},
+			viewer:  staticViewer{records: records},
+			want: &controlpb.InterfaceImpairments{
+				Node:       "XR-1",
+				Interface:  "Gi0-0-0-0",
+				Configured: &controlpb.Impairments{DelayMs: 10, BackgroundLoadPercent: 5},
+				Live:       &controlpb.Impairments{DelayMs: 10},
+				InSync:     true,
+			},
+		},
+		{
+			name:     "Test get interface without impairments",
+			request:  &controlpb.GetImpairmentsRequest{Node: "XR-1", Interface: "Gi0-0-0-1"},
+			viewer:   staticViewer{records: records},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "Test get interface of unknown node",
+			request:  &controlpb.GetImpairmentsRequest{Node: "XR-3", Interface: "Gi0-0-0-0"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Test get interface fails",
+			request:  &controlpb.GetImpairmentsRequest{Node: "XR-1", Interface: "Gi0-0-0-0"},
+			viewer:   staticViewer{err: fmt.Errorf("container not running")},
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			server, _, _ := newTestServer(ctrl, "", tt.viewer)
+			response, err := newTestClient(t, server).GetImpairments(context.Background(), tt.request)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.String(), response.GetInterface().String())
+		})
+	}
+}
+
+func TestDefaultServer_ListImpairments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server, _, _ := newTestServer(ctrl, "", staticViewer{records: []impairments.Record{{Interface: "Gi0-0-0-0"}, {Interface: "Gi0-0-0-1"}}})
+	client := newTestClient(t, server)
+	response, err := client.ListImpairments(context.Background(), &controlpb.ListImpairmentsRequest{Node: "XR-1"})
+	assert.NoError(t, err)
+	assert.Len(t, response.GetInterfaces(), 2)
+	_, err = client.ListImpairments(context.Background(), &controlpb.ListImpairmentsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDefaultServer_SetImpairments(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *controlpb.SetImpairmentsRequest
+		want     *scenario.Scenario
+		applyErr error
+		wantCode codes.Code
+	}{
+		{
+			name:    "Test set impairments",
+			request: &controlpb.SetImpairmentsRequest{Node: "XR-1", Interface: "GigabitEthernet0/0/0/0", Impairments: &controlpb.Impairments{DelayMs: 10, LossPercent: 1.5}},
+			want: &scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+				"XR-1": {"Gi0-0-0-0": {Delay: 10, Loss: 1.5}},
+			}},
+		},
+		{
+			name:     "Test set invalid loss",
+			request:  &controlpb.SetImpairmentsRequest{Node: "XR-1", Interface: "Gi0-0-0-0", Impairments: &controlpb.Impairments{LossPercent: 101}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Test set without interface",
+			request:  &controlpb.SetImpairmentsRequest{Node: "XR-1", Impairments: &controlpb.Impairments{DelayMs: 10}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Test set fails",
+			request:  &controlpb.SetImpairmentsRequest{Node: "XR-1", Interface: "Gi0-0-0-0", Impairments: &controlpb.Impairments{DelayMs: 10}},
+			want:     &scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{"XR-1": {"Gi0-0-0-0": {Delay: 10}}}},
+			applyErr: fmt.Errorf("tc failed, all changes are rolled back"),
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			server, _, applier := newTestServer(ctrl, "", staticViewer{})
+			if tt.want != nil {
+				applier.EXPECT().Apply(tt.want).Return(nil, tt.applyErr)
+			}
+			_, err := newTestClient(t, server).SetImpairments(context.Background(), tt.request)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestDefaultServer_DeleteImpairments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server, _, applier := newTestServer(ctrl, "", staticViewer{})
+	applier.EXPECT().Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{"XR-1": {"Gi0-0-0-0": {}}}}).Return(nil, nil)
+	_, err := newTestClient(t, server).DeleteImpairments(context.Background(), &controlpb.DeleteImpairmentsRequest{Node: "XR-1", Interface: "Gi0-0-0-0"})
+	assert.NoError(t, err)
+}
+
+func TestDefaultServer_WatchImpairments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server, mockConfig, _ := newTestServer(ctrl, "", staticViewer{})
+	client := newTestClient(t, server)
+	gomock.InOrder(
+		expectSnapshot(mockConfig, snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 10}}}),
+		expectSnapshot(mockConfig, snapshot{"XR-2": {"Gi0-0-0-0": {Loss: 1}}}),
+	)
+	server.feed.update()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchImpairments(ctx, &controlpb.WatchImpairmentsRequest{Node: "XR-1"})
+	assert.NoError(t, err)
+	// wait until the stream is subscribed, changes before would be missed
+	for {
+		server.feed.mutex.Lock()
+		subscribed := len(server.feed.subscribers) == 1
+		server.feed.mutex.Unlock()
+		if subscribed {
+			break
+		}
+	}
+	server.feed.update()
+	event, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, controlpb.ImpairmentEvent_TYPE_DELETED, event.GetType())
+	assert.Equal(t, "XR-1", event.GetNode())
+	assert.Equal(t, "Gi0-0-0-0", event.GetInterface())
+	assert.Equal(t, uint64(10), event.GetPrevious().GetDelayMs())
+
+	server.Stop()
+	_, err = stream.Recv()
+	assert.Error(t, err)
+}
+
+func TestDefaultServer_Authorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantCode codes.Code
+	}{
+		{
+			name:     "Test missing token",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "Test wrong token",
+			header:   "Bearer wrong",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "Test valid token",
+			header: "Bearer secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			server, _, _ := newTestServer(ctrl, "secret", staticViewer{})
+			client := newTestClient(t, server)
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.header)
+			}
+			_, err := client.ListImpairments(ctx, &controlpb.ListImpairmentsRequest{Node: "XR-1"})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			stream, err := client.WatchImpairments(ctx, &controlpb.WatchImpairmentsRequest{})
+			assert.NoError(t, err)
+			if tt.wantCode != codes.OK {
+				_, err = stream.Recv()
+				assert.Equal(t, tt.wantCode, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestDefaultServer_StartStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server, mockConfig, _ := newTestServer(ctrl, "", staticViewer{})
+	expectSnapshot(mockConfig, snapshot{})
+	done := make(chan struct{})
+	go func() {
+		server.Start()
+		close(done)
+	}()
+	for {
+		server.feed.mutex.Lock()
+		started := server.feed.snapshot != nil
+		server.feed.mutex.Unlock()
+		if started {
+			break
+		}
+	}
+	server.Stop()
+	<-done
+}
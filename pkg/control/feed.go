@@ -0,0 +1,194 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/sirupsen/logrus"
+)
+
+const subscriberBuffer = 64
+
+// errSubscriberTooSlow is returned to watchers which did not keep up with the changes
+var errSubscriberTooSlow = errors.New("Watcher did not keep up with the changes, events were dropped")
+
+// Event is a change of the configured impairments of an interface, Current is empty if the impairments were deleted
+type Event struct {
+	Timestamp time.Time
+	Node      string
+	Interface string
+	Previous  scenario.Impairments
+	Current   scenario.Impairments
+}
+
+// configuredNode is the config layout of a node, see helpers.GetDefaultNodeConfigKey
+type configuredNode struct {
+	Config map[string]struct {
+		Impairments scenario.Impairments `koanf:"impairments"`
+	} `koanf:"config"`
+}
+
+// snapshot contains the configured impairments by node and interface
+type snapshot map[string]map[string]scenario.Impairments
+
+func readSnapshot(config config.Config) (snapshot, error) {
+	nodes := make(map[string]configuredNode)
+	if err := config.Unmarshal(nodesKey, &nodes); err != nil {
+		return nil, fmt.Errorf("Unable to read impairments from config: %v", err)
+	}
+	current := make(snapshot)
+	for node, configured := range nodes {
+		for interface_, values := range configured.Config {
+			if values.Impairments == (scenario.Impairments{}) {
+				continue
+			}
+			if current[node] == nil {
+				current[node] = make(map[string]scenario.Impairments)
+			}
+			current[node][interface_] = values.Impairments
+		}
+	}
+	return current, nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diff returns the events ordered by node and interface
+func diff(previous, current snapshot, timestamp time.Time) []Event {
+	nodes := make(map[string]struct{})
+	for node := range previous {
+		nodes[node] = struct{}{}
+	}
+	for node := range current {
+		nodes[node] = struct{}{}
+	}
+	var events []Event
+	for _, node := range sortedKeys(nodes) {
+		interfaces := make(map[string]struct{})
+		for interface_ := range previous[node] {
+			interfaces[interface_] = struct{}{}
+		}
+		for interface_ := range current[node] {
+			interfaces[interface_] = struct{}{}
+		}
+		for _, interface_ := range sortedKeys(interfaces) {
+			if previous[node][interface_] == current[node][interface_] {
+				continue
+			}
+			events = append(events, Event{
+				Timestamp: timestamp,
+				Node:      node,
+				Interface: interface_,
+				Previous:  previous[node][interface_],
+				Current:   current[node][interface_],
+			})
+		}
+	}
+	return events
+}
+
+type subscriber struct {
+	node   string
+	events chan Event
+	err    error
+}
+
+// changeFeed compares the config after each reload with the previous one and sends the changes to the subscribers
+type changeFeed struct {
+	log         *logrus.Entry
+	config      config.Config
+	mutex       sync.Mutex
+	snapshot    snapshot
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+func newChangeFeed(config config.Config) *changeFeed {
+	return &changeFeed{
+		log:         logging.DefaultLogger.WithField("subsystem", subsystem),
+		config:      config,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// update is registered as config.ChangeNotifier callback, the first call only stores the snapshot.
+// The snapshot is read through the config, which guards its values against concurrent changes of the APIs
+func (feed *changeFeed) update() {
+	current, err := readSnapshot(feed.config)
+	if err != nil {
+		feed.log.Errorf("Error reading changed impairments: %v\n", err)
+		return
+	}
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+	previous := feed.snapshot
+	feed.snapshot = current
+	if previous == nil {
+		return
+	}
+	for _, event := range diff(previous, current, time.Now()) {
+		feed.log.Debugf("Impairments of %s on node %s changed\n", event.Interface, event.Node)
+		for subscriber := range feed.subscribers {
+			if subscriber.node != "" && subscriber.node != event.Node {
+				continue
+			}
+			select {
+			case subscriber.events <- event:
+			default:
+				feed.log.Warnf("Dropping watcher of node %q: %v\n", subscriber.node, errSubscriberTooSlow)
+				feed.remove(subscriber, errSubscriberTooSlow)
+			}
+		}
+	}
+}
+
+// remove has to be called with the mutex held
+func (feed *changeFeed) remove(subscriber *subscriber, err error) {
+	if _, ok := feed.subscribers[subscriber]; !ok {
+		return
+	}
+	delete(feed.subscribers, subscriber)
+	subscriber.err = err
+	close(subscriber.events)
+}
+
+// subscribe returns the events of a node, or of all nodes if node is empty, until unsubscribe is called
+func (feed *changeFeed) subscribe(node string) (*subscriber, error) {
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+	if feed.closed {
+		return nil, errors.New("Server is stopping")
+	}
+	subscriber := &subscriber{node: node, events: make(chan Event, subscriberBuffer)}
+	feed.subscribers[subscriber] = struct{}{}
+	return subscriber, nil
+}
+
+func (feed *changeFeed) unsubscribe(subscriber *subscriber) {
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+	feed.remove(subscriber, nil)
+}
+
+// close ends all subscriptions, watch streams would otherwise block the shutdown
+func (feed *changeFeed) close() {
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+	feed.closed = true
+	for subscriber := range feed.subscribers {
+		feed.remove(subscriber, nil)
+	}
+}
@@ -0,0 +1,120 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// expectSnapshot lets the mock config return the impairments as config layout
+func expectSnapshot(mockConfig *config.MockConfig, current snapshot) *gomock.Call {
+	return mockConfig.EXPECT().Unmarshal(nodesKey, gomock.Any()).DoAndReturn(func(key string, value interface{}) error {
+		nodes := value.(*map[string]configuredNode)
+		for node, interfaces := range current {
+			configured := configuredNode{Config: make(map[string]struct {
+				Impairments scenario.Impairments `koanf:"impairments"`
+			})}
+			for interface_, impairments := range interfaces {
+				entry := configured.Config[interface_]
+				entry.Impairments = impairments
+				configured.Config[interface_] = entry
+			}
+			(*nodes)[node] = configured
+		}
+		return nil
+	})
+}
+
+func TestDiff(t *testing.T) {
+	timestamp := time.Date(2024, 1, 21, 11, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		previous snapshot
+		current  snapshot
+		want     []Event
+	}{
+		{
+			name:     "Test no changes",
+			previous: snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 10}}},
+			current:  snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 10}}},
+		},
+		{
+			name:     "Test added, changed and deleted impairments",
+			previous: snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 10}, "Gi0-0-0-1": {Loss: 1}}},
+			current:  snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 20}}, "XR-2": {"Gi0-0-0-0": {Rate: 1000}}},
+			want: []Event{
+				{Timestamp: timestamp, Node: "XR-1", Interface: "Gi0-0-0-0", Previous: scenario.Impairments{Delay: 10}, Current: scenario.Impairments{Delay: 20}},
+				{Timestamp: timestamp, Node: "XR-1", Interface: "Gi0-0-0-1", Previous: scenario.Impairments{Loss: 1}},
+				{Timestamp: timestamp, Node: "XR-2", Interface: "Gi0-0-0-0", Current: scenario.Impairments{Rate: 1000}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diff(tt.previous, tt.current, timestamp))
+		})
+	}
+}
+
+func TestChangeFeed_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockConfig := config.NewMockConfig(ctrl)
+	feed := newChangeFeed(mockConfig)
+	all, err := feed.subscribe("")
+	assert.NoError(t, err)
+	xr2, err := feed.subscribe("XR-2")
+	assert.NoError(t, err)
+
+	gomock.InOrder(
+		expectSnapshot(mockConfig, snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 10}}}),
+		expectSnapshot(mockConfig, snapshot{"XR-1": {"Gi0-0-0-0": {Delay: 20}}}),
+	)
+	feed.update()
+	assert.Len(t, all.events, 0, "the first update must only store the snapshot")
+	feed.update()
+	assert.Len(t, all.events, 1)
+	assert.Len(t, xr2.events, 0)
+	event := <-all.events
+	assert.Equal(t, "Gi0-0-0-0", event.Interface)
+	assert.Equal(t, scenario.Impairments{Delay: 20}, event.Current)
+
+	feed.unsubscribe(xr2)
+	_, ok := <-xr2.events
+	assert.False(t, ok)
+	assert.NoError(t, xr2.err)
+}
+
+func TestChangeFeed_SlowSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockConfig := config.NewMockConfig(ctrl)
+	feed := newChangeFeed(mockConfig)
+	subscriber, err := feed.subscribe("XR-1")
+	assert.NoError(t, err)
+	expectSnapshot(mockConfig, snapshot{})
+	feed.update()
+	for i := 0; i <= subscriberBuffer; i++ {
+		expectSnapshot(mockConfig, snapshot{"XR-1": {"Gi0-0-0-0": {Delay: uint64(i + 1)}}})
+		feed.update()
+	}
+	for range subscriber.events {
+	}
+	assert.ErrorIs(t, subscriber.err, errSubscriberTooSlow)
+	assert.Empty(t, feed.subscribers)
+}
+
+func TestChangeFeed_Close(t *testing.T) {
+	feed := newChangeFeed(config.NewMockConfig(gomock.NewController(t)))
+	subscriber, err := feed.subscribe("")
+	assert.NoError(t, err)
+	feed.close()
+	_, ok := <-subscriber.events
+	assert.False(t, ok)
+	assert.NoError(t, subscriber.err)
+	_, err = feed.subscribe("")
+	assert.Error(t, err)
+	feed.unsubscribe(subscriber)
+}
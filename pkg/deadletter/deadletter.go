@@ -0,0 +1,10 @@
+package deadletter
+
+var subsystem = "deadletter"
+
+// Writer stores messages which could not be processed together with the reason
+type Writer interface {
+	Init() error
+	Write(letter *Letter)
+	Close() error
+}
@@ -0,0 +1,58 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// FileWriter appends dead letters as JSON lines to a local file
+type FileWriter struct {
+	log     *logrus.Entry
+	path    string
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func NewFileWriter(path string) *FileWriter {
+	return &FileWriter{
+		log:  logging.DefaultLogger.WithField("subsystem", subsystem),
+		path: path,
+	}
+}
+
+func (writer *FileWriter) Init() error {
+	file, err := os.OpenFile(writer.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	writer.file = file
+	writer.encoder = json.NewEncoder(file)
+	writer.log.Infoln("Writing dead letters to file", writer.path)
+	return nil
+}
+
+func (writer *FileWriter) Write(letter *Letter) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.encoder == nil {
+		return
+	}
+	if err := writer.encoder.Encode(letter); err != nil {
+		writer.log.Errorln("Error writing dead letter: ", err)
+	}
+}
+
+func (writer *FileWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.file == nil {
+		return nil
+	}
+	writer.encoder = nil
+	return writer.file.Close()
+}
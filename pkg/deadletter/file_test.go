@@ -0,0 +1,84 @@
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileWriter_Init(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{
+			name:    "Test Init with valid path",
+			path:    filepath.Join(t.TempDir(), "dead-letters.jsonl"),
+			wantErr: false,
+		},
+		{
+			name:    "Test Init with invalid path",
+			path:    filepath.Join(t.TempDir(), "nonexistent", "dead-letters.jsonl"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := NewFileWriter(tt.path)
+			if tt.wantErr {
+				assert.Error(t, writer.Init())
+			} else {
+				assert.NoError(t, writer.Init())
+			}
+			assert.NoError(t, writer.Close())
+		})
+	}
+}
+
+func TestFileWriter_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		letters []*Letter
+	}{
+		{
+			name: "Test Write appends letters as JSON lines",
+			letters: []*Letter{
+				NewLetter(StageConsumer, fmt.Errorf("Invalid JSON"), []byte(`{"name": "unknown",}`)),
+				NewLetter(StageProcessor, fmt.Errorf("interface name Loopback0 does not match expected pattern"), []byte(`{"name":"isis"}`)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+			writer := NewFileWriter(path)
+			writer.Write(tt.letters[0])
+			assert.NoError(t, writer.Init())
+			for _, letter := range tt.letters {
+				writer.Write(letter)
+			}
+			assert.NoError(t, writer.Close())
+			writer.Write(tt.letters[0])
+
+			file, err := os.Open(path)
+			assert.NoError(t, err)
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			var lines int
+			for scanner.Scan() {
+				var letter Letter
+				assert.NoError(t, json.Unmarshal(scanner.Bytes(), &letter))
+				assert.Equal(t, tt.letters[lines].Reason, letter.Reason)
+				assert.Equal(t, tt.letters[lines].Payload, letter.Payload)
+				lines++
+			}
+			assert.Equal(t, len(tt.letters), lines)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const reconnectInterval = 30 * time.Second
+
+// KafkaWriter publishes dead letters as JSON to a Kafka topic.
+// The producer is created in the background and at most once per reconnectInterval, letters written while it is not
+// connected are dropped, so an unavailable broker never blocks the pipeline.
+type KafkaWriter struct {
+	log          *logrus.Entry
+	kafkaBrokers []string
+	kafkaTopic   string
+	security     *kafka.SecurityConfig
+	mutex        sync.Mutex
+	saramaConfig *sarama.Config
+	producer     sarama.AsyncProducer
+	connecting   bool
+	closed       bool
+	lastAttempt  time.Time
+	newProducer  func([]string, *sarama.Config) (sarama.AsyncProducer, error)
+}
+
+func NewKafkaWriter(kafkaBrokers []string, kafkaTopic string, security *kafka.SecurityConfig) *KafkaWriter {
+	return &KafkaWriter{
+		log:          logging.DefaultLogger.WithField("subsystem", subsystem),
+		kafkaBrokers: kafkaBrokers,
+		kafkaTopic:   kafkaTopic,
+		security:     security,
+		newProducer:  sarama.NewAsyncProducer,
+	}
+}
+
+// Init starts connecting in the background, it does not fail if the brokers are unavailable
+func (writer *KafkaWriter) Init() error {
+	saramaConfig := sarama.NewConfig()
+	if err := writer.security.Apply(saramaConfig); err != nil {
+		return err
+	}
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.saramaConfig = saramaConfig
+	writer.closed = false
+	writer.connect()
+	return nil
+}
+
+// connect starts creating the producer unless it is connected, connecting or was attempted less than reconnectInterval ago.
+// It has to be called with the mutex held
+func (writer *KafkaWriter) connect() {
+	if writer.producer != nil || writer.connecting || time.Since(writer.lastAttempt) < reconnectInterval {
+		return
+	}
+	writer.lastAttempt = time.Now()
+	writer.connecting = true
+	go writer.createProducer(writer.saramaConfig)
+}
+
+// createProducer connects without holding the mutex, so Write does not wait for the brokers
+func (writer *KafkaWriter) createProducer(saramaConfig *sarama.Config) {
+	producer, err := writer.newProducer(writer.kafkaBrokers, saramaConfig)
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.connecting = false
+	if err != nil {
+		writer.log.Warnf("Unable to connect to dead-letter brokers %s, retrying in %s: %v", strings.Join(writer.kafkaBrokers, ","), reconnectInterval, err)
+		return
+	}
+	if writer.closed {
+		if err := producer.Close(); err != nil {
+			writer.log.Debugln("Error closing dead-letter producer: ", err)
+		}
+		return
+	}
+	writer.log.Infoln("Writing dead letters to brokers", strings.Join(writer.kafkaBrokers, ","), "and topic", writer.kafkaTopic)
+	writer.producer = producer
+	go writer.logErrors(producer)
+}
+
+func (writer *KafkaWriter) logErrors(producer sarama.AsyncProducer) {
+	for err := range producer.Errors() {
+		writer.log.Errorln("Failed to produce dead letter", err)
+	}
+}
+
+func (writer *KafkaWriter) Write(letter *Letter) {
+	encodedLetter, err := json.Marshal(letter)
+	if err != nil {
+		writer.log.Errorln("Error encoding dead letter: ", err)
+		return
+	}
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.saramaConfig == nil || writer.closed {
+		writer.log.Debugf("Dropping dead letter, not initialized: %s", encodedLetter)
+		return
+	}
+	if writer.producer == nil {
+		writer.connect()
+		writer.log.Debugf("Dropping dead letter, not connected: %s", encodedLetter)
+		return
+	}
+	select {
+	case writer.producer.Input() <- &sarama.ProducerMessage{Topic: writer.kafkaTopic, Value: sarama.ByteEncoder(encodedLetter)}:
+	default:
+		writer.log.Warnf("Dropping dead letter, producer queue is full: %s", encodedLetter)
+	}
+}
+
+// Close closes the producer, a producer which is still connecting is closed once it is created
+func (writer *KafkaWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.closed = true
+	if writer.producer == nil {
+		return nil
+	}
+	producer := writer.producer
+	writer.producer = nil
+	return producer.Close()
+}
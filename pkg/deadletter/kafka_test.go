@@ -0,0 +1,142 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaWriter_Init(t *testing.T) {
+	tests := []struct {
+		name       string
+		security   *kafka.SecurityConfig
+		connectErr error
+		wantErr    bool
+	}{
+		{
+			name:    "Test Init with reachable broker",
+			wantErr: false,
+		},
+		{
+			name:       "Test Init with unreachable broker",
+			connectErr: sarama.ErrOutOfBrokers,
+			wantErr:    false,
+		},
+		{
+			name:     "Test Init with invalid security config",
+			security: &kafka.SecurityConfig{SASLMechanism: "PLAIN"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := NewKafkaWriter([]string{"localhost:9092"}, "dead-letters", tt.security)
+			writer.newProducer = func(brokers []string, config *sarama.Config) (sarama.AsyncProducer, error) {
+				if tt.connectErr != nil {
+					return nil, tt.connectErr
+				}
+				return mocks.NewAsyncProducer(t, config), nil
+			}
+			if tt.wantErr {
+				assert.Error(t, writer.Init())
+				return
+			}
+			assert.NoError(t, writer.Init())
+			// the producer is created in the background
+			assert.Eventually(t, func() bool {
+				writer.mutex.Lock()
+				defer writer.mutex.Unlock()
+				return !writer.connecting
+			}, time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.connectErr == nil, writer.producer != nil)
+			assert.NoError(t, writer.Close())
+		})
+	}
+}
+
+func TestKafkaWriter_Write(t *testing.T) {
+	tests := []struct {
+		name          string
+		connected     bool
+		connectErr    error
+		lastAttempt   time.Time
+		wantProduce   bool
+		wantConnect   bool
+		wantConnected bool
+	}{
+		{
+			name:        "Test Write publishes letter",
+			connected:   true,
+			wantProduce: true,
+		},
+		{
+			name:          "Test Write drops letter and connects in the background",
+			wantConnect:   true,
+			wantConnected: true,
+		},
+		{
+			name:        "Test Write drops letter if broker is unreachable",
+			connectErr:  sarama.ErrOutOfBrokers,
+			wantConnect: true,
+		},
+		{
+			name:        "Test Write does not reconnect before the reconnect interval",
+			lastAttempt: time.Now(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			letter := NewLetter(StageConsumer, fmt.Errorf("Invalid JSON"), []byte(`{"name": "unknown",}`))
+			producer := mocks.NewAsyncProducer(t, nil)
+			if tt.wantProduce {
+				producer.ExpectInputWithCheckerFunctionAndSucceed(func(value []byte) error {
+					var received Letter
+					if err := json.Unmarshal(value, &received); err != nil {
+						return err
+					}
+					if received.Payload != letter.Payload {
+						return fmt.Errorf("unexpected payload %s", received.Payload)
+					}
+					return nil
+				})
+			}
+			writer := NewKafkaWriter([]string{"localhost:9092"}, "dead-letters", nil)
+			writer.saramaConfig = sarama.NewConfig()
+			writer.lastAttempt = tt.lastAttempt
+			connected := make(chan struct{})
+			writer.newProducer = func(brokers []string, config *sarama.Config) (sarama.AsyncProducer, error) {
+				defer close(connected)
+				if tt.connectErr != nil {
+					return nil, tt.connectErr
+				}
+				return producer, nil
+			}
+			if tt.connected {
+				writer.producer = producer
+			}
+			writer.Write(letter)
+			if tt.wantConnect {
+				<-connected
+				assert.Eventually(t, func() bool {
+					writer.mutex.Lock()
+					defer writer.mutex.Unlock()
+					return !writer.connecting
+				}, time.Second, 10*time.Millisecond)
+			} else {
+				assert.False(t, writer.connecting)
+			}
+			assert.Equal(t, tt.connected || tt.wantConnected, writer.producer != nil)
+			if writer.producer != nil {
+				assert.NoError(t, writer.Close())
+			} else {
+				assert.NoError(t, producer.Close())
+			}
+		})
+	}
+}
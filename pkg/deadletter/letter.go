@@ -0,0 +1,28 @@
+package deadletter
+
+import "time"
+
+const (
+	StageConsumer  = "consumer"
+	StageProcessor = "processor"
+)
+
+// Letter is a dropped message together with the reason why it was dropped
+type Letter struct {
+	Timestamp time.Time `json:"timestamp"`
+	Stage     string    `json:"stage"`
+	Reason    string    `json:"reason"`
+	Topic     string    `json:"topic,omitempty"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	Payload   string    `json:"payload"`
+}
+
+func NewLetter(stage string, reason error, payload []byte) *Letter {
+	return &Letter{
+		Timestamp: time.Now(),
+		Stage:     stage,
+		Reason:    reason.Error(),
+		Payload:   string(payload),
+	}
+}
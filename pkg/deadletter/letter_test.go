@@ -0,0 +1,39 @@
+package deadletter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLetter(t *testing.T) {
+	tests := []struct {
+		name    string
+		stage   string
+		reason  error
+		payload []byte
+	}{
+		{
+			name:    "Test creating consumer letter",
+			stage:   StageConsumer,
+			reason:  fmt.Errorf("Invalid JSON"),
+			payload: []byte(`{"name": "unknown",}`),
+		},
+		{
+			name:    "Test creating processor letter",
+			stage:   StageProcessor,
+			reason:  fmt.Errorf("interface name Loopback0 does not match expected pattern"),
+			payload: []byte(`{"name":"isis"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			letter := NewLetter(tt.stage, tt.reason, tt.payload)
+			assert.Equal(t, tt.stage, letter.Stage)
+			assert.Equal(t, tt.reason.Error(), letter.Reason)
+			assert.Equal(t, string(tt.payload), letter.Payload)
+			assert.False(t, letter.Timestamp.IsZero())
+		})
+	}
+}
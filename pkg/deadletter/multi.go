@@ -0,0 +1,35 @@
+package deadletter
+
+// MultiWriter writes every dead letter to all of its writers, without writers dead letters are discarded
+type MultiWriter struct {
+	writers []Writer
+}
+
+func NewMultiWriter(writers ...Writer) *MultiWriter {
+	return &MultiWriter{writers: writers}
+}
+
+func (multiWriter *MultiWriter) Init() error {
+	for _, writer := range multiWriter.writers {
+		if err := writer.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (multiWriter *MultiWriter) Write(letter *Letter) {
+	for _, writer := range multiWriter.writers {
+		writer.Write(letter)
+	}
+}
+
+func (multiWriter *MultiWriter) Close() error {
+	var firstErr error
+	for _, writer := range multiWriter.writers {
+		if err := writer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
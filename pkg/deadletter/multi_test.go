@@ -0,0 +1,67 @@
+package deadletter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMultiWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		writers  int
+		initErr  error
+		closeErr error
+	}{
+		{
+			name:    "Test MultiWriter without writers",
+			writers: 0,
+		},
+		{
+			name:    "Test MultiWriter with two writers",
+			writers: 2,
+		},
+		{
+			name:    "Test MultiWriter with Init error",
+			writers: 2,
+			initErr: fmt.Errorf("unable to open file"),
+		},
+		{
+			name:     "Test MultiWriter with Close error",
+			writers:  2,
+			closeErr: fmt.Errorf("unable to close file"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			letter := NewLetter(StageConsumer, fmt.Errorf("Invalid JSON"), []byte(`{`))
+			var writers []Writer
+			for i := 0; i < tt.writers; i++ {
+				writer := NewMockWriter(ctrl)
+				if tt.initErr != nil {
+					writer.EXPECT().Init().Return(tt.initErr).MaxTimes(1)
+				} else {
+					writer.EXPECT().Init().Return(nil)
+					writer.EXPECT().Write(letter)
+				}
+				writer.EXPECT().Close().Return(tt.closeErr)
+				writers = append(writers, writer)
+			}
+			multiWriter := NewMultiWriter(writers...)
+			if tt.initErr != nil {
+				assert.ErrorIs(t, multiWriter.Init(), tt.initErr)
+			} else {
+				assert.NoError(t, multiWriter.Init())
+				multiWriter.Write(letter)
+			}
+			if tt.closeErr != nil {
+				assert.ErrorIs(t, multiWriter.Close(), tt.closeErr)
+			} else {
+				assert.NoError(t, multiWriter.Close())
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ type Helper interface {
 	GetUserHome() (string, error)
 	GetDefaultClabNameKey() string
 	GetDefaultClabName() string
+	GetDefaultNodeConfigKey(node string) string
 	GetDefaultImpairmentsPrefix(node, interface_ string) string
 }
 
@@ -41,6 +42,10 @@ func (helper *DefaultHelper) GetDefaultClabName() string {
 	return "clab-hawkv6"
 }
 
+func (helper *DefaultHelper) GetDefaultNodeConfigKey(node string) string {
+	return "nodes." + node + ".config"
+}
+
 func (helper *DefaultHelper) GetDefaultImpairmentsPrefix(node, interface_ string) string {
-	return "nodes." + node + ".config." + interface_ + ".impairments."
+	return helper.GetDefaultNodeConfigKey(node) + "." + interface_ + ".impairments."
 }
@@ -80,6 +80,20 @@ func (mr *MockHelperMockRecorder) GetDefaultImpairmentsPrefix(node, interface_ a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultImpairmentsPrefix", reflect.TypeOf((*MockHelper)(nil).GetDefaultImpairmentsPrefix), node, interface_)
 }
 
+// GetDefaultNodeConfigKey mocks base method.
+func (m *MockHelper) GetDefaultNodeConfigKey(node string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultNodeConfigKey", node)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetDefaultNodeConfigKey indicates an expected call of GetDefaultNodeConfigKey.
+func (mr *MockHelperMockRecorder) GetDefaultNodeConfigKey(node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultNodeConfigKey", reflect.TypeOf((*MockHelper)(nil).GetDefaultNodeConfigKey), node)
+}
+
 // GetUserHome mocks base method.
 func (m *MockHelper) GetUserHome() (string, error) {
 	m.ctrl.T.Helper()
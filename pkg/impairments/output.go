@@ -0,0 +1,65 @@
+package impairments
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
+)
+
+// WriteRecords renders the records in the given output format
+func WriteRecords(writer io.Writer, records []Record, format string) error {
+	if records == nil {
+		records = []Record{}
+	}
+	return output.Write(writer, format, records, func() [][]string {
+		table := [][]string{{"Interface", "Delay", "Jitter", "Packet Loss", "Rate (kbit)", "Background Load", "State"}}
+		for _, record := range records {
+			table = append(table, createColumns(record))
+		}
+		return table
+	})
+}
+
+// FormatDuration formats milliseconds like the containerlab output, e.g. 4ms or 0s
+func FormatDuration(milliseconds uint64) string {
+	return (time.Duration(milliseconds) * time.Millisecond).String()
+}
+
+// formatColumn shows the live value and the configured value if it differs
+func formatColumn(record Record, format func(command.Impairments) string) string {
+	live := "N/A"
+	if record.Live != nil {
+		live = format(*record.Live)
+	}
+	configured := format(command.Impairments{})
+	if record.Configured != nil {
+		configured = format(*record.Configured)
+	}
+	if (record.Live != nil || record.Configured != nil) && live != configured {
+		return fmt.Sprintf("%s (config: %s)", live, configured)
+	}
+	return live
+}
+
+func createColumns(record Record) []string {
+	state := "in sync"
+	if !record.InSync {
+		state = "out of sync"
+	}
+	backgroundLoad := "-"
+	if record.BackgroundLoad != 0 {
+		backgroundLoad = fmt.Sprintf("%.2f%%", record.BackgroundLoad)
+	}
+	return []string{
+		record.Interface,
+		formatColumn(record, func(impairments command.Impairments) string { return FormatDuration(impairments.Delay) }),
+		formatColumn(record, func(impairments command.Impairments) string { return FormatDuration(impairments.Jitter) }),
+		formatColumn(record, func(impairments command.Impairments) string { return fmt.Sprintf("%.2f%%", impairments.Loss) }),
+		formatColumn(record, func(impairments command.Impairments) string { return fmt.Sprintf("%d", impairments.Rate) }),
+		backgroundLoad,
+		state,
+	}
+}
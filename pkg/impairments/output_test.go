@@ -0,0 +1,101 @@
+package impairments
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRecords(t *testing.T) {
+	records := []Record{
+		{Interface: "lo", InSync: true},
+		{
+			Interface:      "Gi0-0-0-0",
+			Configured:     &command.Impairments{Delay: 4, Loss: 5},
+			BackgroundLoad: 20,
+			Live:           &command.Impairments{Delay: 4, Loss: 4.9999999},
+			InSync:         true,
+		},
+		{Interface: "Gi0-0-0-1", Configured: &command.Impairments{Rate: 100000}, Live: &command.Impairments{Delay: 2}, InSync: false},
+	}
+	tests := []struct {
+		name    string
+		records []Record
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Test table output",
+			records: records,
+			format:  output.Table,
+			want: `+-----------+------------------+--------+-------------+--------------------+-----------------+-------------+
+| Interface | Delay            | Jitter | Packet Loss | Rate (kbit)        | Background Load | State       |
++-----------+------------------+--------+-------------+--------------------+-----------------+-------------+
+| lo        | N/A              | N/A    | N/A         | N/A                | -               | in sync     |
+| Gi0-0-0-0 | 4ms              | 0s     | 5.00%       | 0                  | 20.00%          | in sync     |
+| Gi0-0-0-1 | 2ms (config: 0s) | 0s     | 0.00%       | 0 (config: 100000) | -               | out of sync |
++-----------+------------------+--------+-------------+--------------------+-----------------+-------------+
+`,
+		},
+		{
+			name:    "Test JSON output",
+			records: records[2:],
+			format:  output.JSON,
+			want: `[
+  {
+    "interface": "Gi0-0-0-1",
+    "configured": {
+      "delay": 0,
+      "jitter": 0,
+      "packet_loss": 0,
+      "rate": 100000
+    },
+    "live": {
+      "delay": 2,
+      "jitter": 0,
+      "packet_loss": 0,
+      "rate": 0
+    },
+    "in_sync": false
+  }
+]
+`,
+		},
+		{
+			name:    "Test YAML output",
+			records: records[:1],
+			format:  output.YAML,
+			want: `- interface: lo
+  in_sync: true
+`,
+		},
+		{
+			name:    "Test JSON output without records",
+			records: nil,
+			format:  output.JSON,
+			want:    "[]\n",
+		},
+		{
+			name:    "Test unknown output",
+			records: records,
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			err := WriteRecords(&buffer, tt.records, tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package impairments
 
 import (
+	"fmt"
+
 	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
@@ -8,12 +10,13 @@ import (
 )
 
 type Setter interface {
-	SetDelay(uint64)
-	SetJitter(uint64)
-	SetLoss(float64)
-	SetRate(uint64)
-	ApplyImpairments()
-	DeleteImpairments()
+	SetDelay(uint64) error
+	SetJitter(uint64) error
+	SetLoss(float64) error
+	SetRate(uint64) error
+	SetBackgroundLoad(float64) error
+	ApplyImpairments() error
+	DeleteImpairments() error
 	WriteConfig() error
 }
 
@@ -90,6 +93,23 @@ func (manager *DefaultSetter) SetRate(rate uint64) error {
 	return nil
 }
 
+// SetBackgroundLoad only stores the synthetic load in % of the rate, it is added to the utilization counters by the processor
+func (manager *DefaultSetter) SetBackgroundLoad(backgroundLoad float64) error {
+	if backgroundLoad == 0 {
+		manager.log.Debugln("Remove background load from config if set")
+		manager.config.DeleteValue(manager.impairmentsPrefix + "background-load")
+	} else {
+		if backgroundLoad < 0 || backgroundLoad > 100 {
+			return fmt.Errorf("Background load %f is not between 0 and 100%%", backgroundLoad)
+		}
+		manager.log.Debugf("Set background load in config to %f\n", backgroundLoad)
+		if err := manager.config.SetValue(manager.impairmentsPrefix+"background-load", backgroundLoad); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (manager *DefaultSetter) ApplyImpairments() error {
 	return manager.command.ApplyImpairments()
 }
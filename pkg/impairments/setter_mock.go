@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: setter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=setter.go -destination=setter_mock.go -package=impairments
+//
+
+// Package impairments is a generated GoMock package.
+package impairments
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSetter is a mock of Setter interface.
+type MockSetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSetterMockRecorder
+}
+
+// MockSetterMockRecorder is the mock recorder for MockSetter.
+type MockSetterMockRecorder struct {
+	mock *MockSetter
+}
+
+// NewMockSetter creates a new mock instance.
+func NewMockSetter(ctrl *gomock.Controller) *MockSetter {
+	mock := &MockSetter{ctrl: ctrl}
+	mock.recorder = &MockSetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSetter) EXPECT() *MockSetterMockRecorder {
+	return m.recorder
+}
+
+// ApplyImpairments mocks base method.
+func (m *MockSetter) ApplyImpairments() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyImpairments")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyImpairments indicates an expected call of ApplyImpairments.
+func (mr *MockSetterMockRecorder) ApplyImpairments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyImpairments", reflect.TypeOf((*MockSetter)(nil).ApplyImpairments))
+}
+
+// DeleteImpairments mocks base method.
+func (m *MockSetter) DeleteImpairments() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImpairments")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImpairments indicates an expected call of DeleteImpairments.
+func (mr *MockSetterMockRecorder) DeleteImpairments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImpairments", reflect.TypeOf((*MockSetter)(nil).DeleteImpairments))
+}
+
+// SetBackgroundLoad mocks base method.
+func (m *MockSetter) SetBackgroundLoad(arg0 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBackgroundLoad", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBackgroundLoad indicates an expected call of SetBackgroundLoad.
+func (mr *MockSetterMockRecorder) SetBackgroundLoad(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackgroundLoad", reflect.TypeOf((*MockSetter)(nil).SetBackgroundLoad), arg0)
+}
+
+// SetDelay mocks base method.
+func (m *MockSetter) SetDelay(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDelay", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDelay indicates an expected call of SetDelay.
+func (mr *MockSetterMockRecorder) SetDelay(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDelay", reflect.TypeOf((*MockSetter)(nil).SetDelay), arg0)
+}
+
+// SetJitter mocks base method.
+func (m *MockSetter) SetJitter(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetJitter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJitter indicates an expected call of SetJitter.
+func (mr *MockSetterMockRecorder) SetJitter(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJitter", reflect.TypeOf((*MockSetter)(nil).SetJitter), arg0)
+}
+
+// SetLoss mocks base method.
+func (m *MockSetter) SetLoss(arg0 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoss", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLoss indicates an expected call of SetLoss.
+func (mr *MockSetterMockRecorder) SetLoss(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoss", reflect.TypeOf((*MockSetter)(nil).SetLoss), arg0)
+}
+
+// SetRate mocks base method.
+func (m *MockSetter) SetRate(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRate indicates an expected call of SetRate.
+func (mr *MockSetterMockRecorder) SetRate(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRate", reflect.TypeOf((*MockSetter)(nil).SetRate), arg0)
+}
+
+// WriteConfig mocks base method.
+func (m *MockSetter) WriteConfig() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteConfig")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteConfig indicates an expected call of WriteConfig.
+func (mr *MockSetterMockRecorder) WriteConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteConfig", reflect.TypeOf((*MockSetter)(nil).WriteConfig))
+}
@@ -292,6 +292,74 @@ func TestDefaultSetter_SetRate(t *testing.T) {
 	}
 }
 
+func TestDefaultSetter_SetBackgroundLoad(t *testing.T) {
+	type args struct {
+		backgroundLoad float64
+	}
+	tests := []struct {
+		name        string
+		args        args
+		configError bool
+		wantErr     bool
+	}{
+		{
+			name: "Test with positive background load",
+			args: args{
+				backgroundLoad: 20,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test with return error from config",
+			args: args{
+				backgroundLoad: 20,
+			},
+			configError: true,
+			wantErr:     true,
+		},
+		{
+			name: "Test with background load above 100%",
+			args: args{
+				backgroundLoad: 120,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test with background load 0 (delete background load)",
+			args: args{
+				backgroundLoad: 0,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig := config.NewMockConfig(gomock.NewController(t))
+			mockCommand := command.NewMockSetCommand(gomock.NewController(t))
+			manager := &DefaultSetter{
+				ImpairmentsManager: ImpairmentsManager{
+					log:    logging.DefaultLogger.WithField("subsystem", Subsystem),
+					config: mockConfig,
+				},
+				command:           mockCommand,
+				impairmentsPrefix: "nodes.XR-1.config.Gi0-0-0-0.impairments.",
+			}
+			if tt.configError {
+				mockConfig.EXPECT().SetValue(manager.impairmentsPrefix+"background-load", tt.args.backgroundLoad).Return(errors.New("error"))
+			} else if tt.args.backgroundLoad == 0 {
+				mockConfig.EXPECT().DeleteValue(manager.impairmentsPrefix + "background-load").Return()
+			} else if !tt.wantErr {
+				mockConfig.EXPECT().SetValue(manager.impairmentsPrefix+"background-load", tt.args.backgroundLoad).Return(nil)
+			}
+			if tt.wantErr {
+				assert.Error(t, manager.SetBackgroundLoad(tt.args.backgroundLoad))
+			} else {
+				assert.NoError(t, manager.SetBackgroundLoad(tt.args.backgroundLoad))
+			}
+		})
+	}
+}
+
 func TestDefaultSetter_ApplyImpairments(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1,29 +1,123 @@
 package impairments
 
 import (
+	"fmt"
+	"math"
+	"sort"
+
 	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
 )
 
 type Viewer interface {
-	ShowImpairments() error
+	GetImpairments() ([]Record, error)
+}
+
+// Record compares the impairments stored in the config with the impairments applied to an interface
+type Record struct {
+	Interface      string               `json:"interface" yaml:"interface"`
+	Configured     *command.Impairments `json:"configured,omitempty" yaml:"configured,omitempty"`
+	BackgroundLoad float64              `json:"background_load,omitempty" yaml:"background_load,omitempty"`
+	Live           *command.Impairments `json:"live,omitempty" yaml:"live,omitempty"`
+	InSync         bool                 `json:"in_sync" yaml:"in_sync"`
+}
+
+// configuredInterface is the config layout of an interface, see helpers.GetDefaultImpairmentsPrefix
+type configuredInterface struct {
+	Impairments struct {
+		Delay          uint64  `koanf:"delay"`
+		Jitter         uint64  `koanf:"jitter"`
+		Loss           float64 `koanf:"loss"`
+		Rate           uint64  `koanf:"rate"`
+		BackgroundLoad float64 `koanf:"background-load"`
+	} `koanf:"impairments"`
 }
 
 type DefaultViewer struct {
 	ImpairmentsManager
-	command command.ShowCommand
+	nodeConfigKey string
+	command       command.ShowCommand
 }
 
-func NewDefaultViewer(node string, command command.ShowCommand) *DefaultViewer {
+func NewDefaultViewer(config config.Config, node string, helper helpers.Helper, command command.ShowCommand) *DefaultViewer {
 	defautlViewer := &DefaultViewer{
 		ImpairmentsManager: ImpairmentsManager{
-			log: logging.DefaultLogger.WithField("subsystem", Subsystem),
+			log:    logging.DefaultLogger.WithField("subsystem", Subsystem),
+			config: config,
 		},
-		command: command,
+		nodeConfigKey: helper.GetDefaultNodeConfigKey(node),
+		command:       command,
 	}
 	return defautlViewer
 }
 
-func (manager *DefaultViewer) ShowImpairments() error {
-	return manager.command.ShowImpairments()
+func (manager *DefaultViewer) getConfiguredImpairments() (map[string]configuredInterface, error) {
+	interfaces := make(map[string]configuredInterface)
+	if err := manager.config.Unmarshal(manager.nodeConfigKey, &interfaces); err != nil {
+		return nil, fmt.Errorf("Unable to read impairments from config: %v", err)
+	}
+	return interfaces, nil
+}
+
+// isInSync treats missing impairments like impairments without values, the loss is rounded by netem
+func isInSync(configured, live *command.Impairments) bool {
+	var want, got command.Impairments
+	if configured != nil {
+		want = *configured
+	}
+	if live != nil {
+		got = *live
+	}
+	return want.Delay == got.Delay && want.Jitter == got.Jitter && want.Rate == got.Rate && math.Abs(want.Loss-got.Loss) < 0.01
+}
+
+func (manager *DefaultViewer) createRecord(name string, configured configuredInterface, live *command.Impairments) Record {
+	record := Record{
+		Interface:      name,
+		BackgroundLoad: configured.Impairments.BackgroundLoad,
+		Live:           live,
+	}
+	impairments := command.Impairments{
+		Delay:  configured.Impairments.Delay,
+		Jitter: configured.Impairments.Jitter,
+		Loss:   configured.Impairments.Loss,
+		Rate:   configured.Impairments.Rate,
+	}
+	if impairments != (command.Impairments{}) {
+		record.Configured = &impairments
+	}
+	record.InSync = isInSync(record.Configured, record.Live)
+	return record
+}
+
+// GetImpairments returns the interfaces reported by the backend followed by the interfaces only found in the config
+func (manager *DefaultViewer) GetImpairments() ([]Record, error) {
+	configured, err := manager.getConfiguredImpairments()
+	if err != nil {
+		return nil, err
+	}
+	live, err := manager.command.GetImpairments()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(live)+len(configured))
+	seen := make(map[string]bool, len(live))
+	for _, liveInterface := range live {
+		seen[liveInterface.Interface] = true
+		records = append(records, manager.createRecord(liveInterface.Interface, configured[liveInterface.Interface], liveInterface.Impairments))
+	}
+	var configOnly []string
+	for name := range configured {
+		if !seen[name] {
+			configOnly = append(configOnly, name)
+		}
+	}
+	sort.Strings(configOnly)
+	for _, name := range configOnly {
+		manager.log.Debugf("Interface %s is configured but not reported by the backend\n", name)
+		records = append(records, manager.createRecord(name, configured[name], nil))
+	}
+	return records, nil
 }
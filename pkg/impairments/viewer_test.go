@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -18,7 +20,6 @@ func TestNewDefaultViewer(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want *DefaultViewer
 	}{
 		{
 			name: "Test function TestNewDefaultViewer",
@@ -26,53 +27,110 @@ func TestNewDefaultViewer(t *testing.T) {
 				node:     "XR-1",
 				clabName: "clab-hawkv6",
 			},
-			want: &DefaultViewer{
-				ImpairmentsManager: ImpairmentsManager{
-					log: logging.DefaultLogger.WithField("subsystem", Subsystem),
-				},
-				command: command.NewDefaultShowCommand("XR-1", "clab-hawkv6"),
-			},
 		},
 	}
 	for _, tt := range tests {
-		command := command.NewDefaultShowCommand(tt.args.node, tt.args.clabName)
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.want, NewDefaultViewer(tt.args.node, command))
+			ctrl := gomock.NewController(t)
+			mockConfig := config.NewMockConfig(ctrl)
+			showCommand := command.NewDefaultShowCommand(tt.args.node, tt.args.clabName)
+			want := &DefaultViewer{
+				ImpairmentsManager: ImpairmentsManager{
+					log:    logging.DefaultLogger.WithField("subsystem", Subsystem),
+					config: mockConfig,
+				},
+				nodeConfigKey: "nodes.XR-1.config",
+				command:       showCommand,
+			}
+			assert.Equal(t, want, NewDefaultViewer(mockConfig, tt.args.node, helpers.NewDefaultHelper(), showCommand))
 		})
 	}
 }
 
-func TestDefaultViewer_ShowImpairments(t *testing.T) {
+func TestDefaultViewer_GetImpairments(t *testing.T) {
+	configContent := `
+nodes:
+  XR-1:
+    config:
+      Gi0-0-0-0:
+        impairments:
+          delay: 4
+          loss: 5
+      Gi0-0-0-1:
+        impairments:
+          rate: 100000
+          background-load: 20
+      Gi0-0-0-2:
+        impairments:
+          delay: 10
+      Gi0-0-0-3:
+        impairments: {}
+`
 	tests := []struct {
 		name    string
+		live    []command.InterfaceImpairments
+		liveErr error
+		want    []Record
 		wantErr bool
 	}{
 		{
-			name:    "Test ShowImpairments with no errors",
-			wantErr: false,
+			name: "Test merge of live and configured impairments",
+			live: []command.InterfaceImpairments{
+				{Interface: "lo"},
+				{Interface: "Gi0-0-0-0", Impairments: &command.Impairments{Delay: 4, Loss: 4.9999999}},
+				{Interface: "Gi0-0-0-1", Impairments: &command.Impairments{Delay: 2, Rate: 100000}},
+				{Interface: "Gi0-0-0-3", Impairments: &command.Impairments{}},
+			},
+			want: []Record{
+				{Interface: "lo", InSync: true},
+				{
+					Interface:  "Gi0-0-0-0",
+					Configured: &command.Impairments{Delay: 4, Loss: 5},
+					Live:       &command.Impairments{Delay: 4, Loss: 4.9999999},
+					InSync:     true,
+				},
+				{
+					Interface:      "Gi0-0-0-1",
+					Configured:     &command.Impairments{Rate: 100000},
+					BackgroundLoad: 20,
+					Live:           &command.Impairments{Delay: 2, Rate: 100000},
+					InSync:         false,
+				},
+				{Interface: "Gi0-0-0-3", Live: &command.Impairments{}, InSync: true},
+				{Interface: "Gi0-0-0-2", Configured: &command.Impairments{Delay: 10}, InSync: false},
+			},
 		},
 		{
-			name:    "Test ShowImpairments with no errors",
-			wantErr: false,
+			name:    "Test with backend error",
+			liveErr: fmt.Errorf("error showing impairments"),
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			manager := &DefaultViewer{
-				ImpairmentsManager: ImpairmentsManager{
-					log: logging.DefaultLogger.WithField("subsystem", Subsystem),
-				},
-			}
 			ctrl := gomock.NewController(t)
-			command := command.NewMockShowCommand(ctrl)
-			manager.command = command
+			showCommand := command.NewMockShowCommand(ctrl)
+			showCommand.EXPECT().GetImpairments().Return(tt.live, tt.liveErr)
+			memoryConfig, err := config.NewMemoryConfig(configContent)
+			assert.NoError(t, err)
+			manager := NewDefaultViewer(memoryConfig, "XR-1", helpers.NewDefaultHelper(), showCommand)
+			got, err := manager.GetImpairments()
 			if tt.wantErr {
-				command.EXPECT().ShowImpairments().Return(fmt.Errorf("error showing impairments"))
-				assert.Error(t, manager.ShowImpairments())
+				assert.Error(t, err)
 			} else {
-				command.EXPECT().ShowImpairments().Return(nil)
-				assert.NoError(t, manager.ShowImpairments())
+				assert.NoError(t, err)
 			}
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestDefaultViewer_GetImpairments_ConfigError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockConfig := config.NewMockConfig(ctrl)
+	mockConfig.EXPECT().Unmarshal("nodes.XR-1.config", gomock.Any()).Return(fmt.Errorf("error"))
+	manager := NewDefaultViewer(mockConfig, "XR-1", helpers.NewDefaultHelper(), command.NewMockShowCommand(ctrl))
+	records, err := manager.GetImpairments()
+	assert.Error(t, err)
+	assert.Nil(t, records)
+}
@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	defaultProducerRetries      = 3
+	defaultProducerRetryBackoff = 100 * time.Millisecond
+	defaultProducerFlushTimeout = 10 * time.Second
+)
+
+// ProducerConfig holds the delivery settings of the Kafka publisher
+type ProducerConfig struct {
+	Acks         string
+	Idempotent   bool
+	Retries      int
+	RetryBackoff time.Duration
+	Compression  string
+	// FlushTimeout limits how long Stop waits until the buffered messages are delivered
+	FlushTimeout time.Duration
+}
+
+func NewProducerConfig() *ProducerConfig {
+	return &ProducerConfig{
+		Acks:         "all",
+		Retries:      defaultProducerRetries,
+		RetryBackoff: defaultProducerRetryBackoff,
+		Compression:  "none",
+		FlushTimeout: defaultProducerFlushTimeout,
+	}
+}
+
+func (producer *ProducerConfig) parseAcks() (sarama.RequiredAcks, error) {
+	switch strings.ToLower(producer.Acks) {
+	case "none", "0":
+		return sarama.NoResponse, nil
+	case "leader", "1":
+		return sarama.WaitForLocal, nil
+	case "all", "-1":
+		return sarama.WaitForAll, nil
+	default:
+		return 0, fmt.Errorf("Unknown acks %q, use one of none, leader or all", producer.Acks)
+	}
+}
+
+func (producer *ProducerConfig) parseCompression() (sarama.CompressionCodec, error) {
+	switch strings.ToLower(producer.Compression) {
+	case "none", "":
+		return sarama.CompressionNone, nil
+	case "gzip":
+		return sarama.CompressionGZIP, nil
+	case "snappy":
+		return sarama.CompressionSnappy, nil
+	case "lz4":
+		return sarama.CompressionLZ4, nil
+	case "zstd":
+		return sarama.CompressionZSTD, nil
+	default:
+		return sarama.CompressionNone, fmt.Errorf("Unknown compression %q, use one of none, gzip, snappy, lz4 or zstd", producer.Compression)
+	}
+}
+
+// Apply configures acks, idempotence, retries and compression on the given sarama config, successes and errors
+// are always returned, so they have to be read from the producer
+func (producer *ProducerConfig) Apply(saramaConfig *sarama.Config) error {
+	acks, err := producer.parseAcks()
+	if err != nil {
+		return err
+	}
+	compression, err := producer.parseCompression()
+	if err != nil {
+		return err
+	}
+	if producer.Retries < 0 {
+		return fmt.Errorf("Retries have to be positive")
+	}
+	if producer.Idempotent {
+		if acks != sarama.WaitForAll {
+			return fmt.Errorf("Idempotent producing requires acks all")
+		}
+		if producer.Retries == 0 {
+			return fmt.Errorf("Idempotent producing requires at least one retry")
+		}
+		// the order of retried batches is only kept with a single in-flight request per broker
+		saramaConfig.Net.MaxOpenRequests = 1
+	}
+	saramaConfig.Producer.RequiredAcks = acks
+	saramaConfig.Producer.Idempotent = producer.Idempotent
+	saramaConfig.Producer.Retry.Max = producer.Retries
+	saramaConfig.Producer.Retry.Backoff = producer.RetryBackoff
+	saramaConfig.Producer.Compression = compression
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	return nil
+}
@@ -0,0 +1,94 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProducerConfig_Apply(t *testing.T) {
+	tests := []struct {
+		name            string
+		modify          func(*ProducerConfig)
+		wantAcks        sarama.RequiredAcks
+		wantCompression sarama.CompressionCodec
+		wantIdempotent  bool
+		wantErr         bool
+	}{
+		{
+			name:            "Test default producer config",
+			modify:          func(*ProducerConfig) {},
+			wantAcks:        sarama.WaitForAll,
+			wantCompression: sarama.CompressionNone,
+		},
+		{
+			name: "Test leader acks with zstd compression",
+			modify: func(producer *ProducerConfig) {
+				producer.Acks = "leader"
+				producer.Compression = "ZSTD"
+			},
+			wantAcks:        sarama.WaitForLocal,
+			wantCompression: sarama.CompressionZSTD,
+		},
+		{
+			name: "Test idempotent producer",
+			modify: func(producer *ProducerConfig) {
+				producer.Idempotent = true
+				producer.Compression = "lz4"
+			},
+			wantAcks:        sarama.WaitForAll,
+			wantCompression: sarama.CompressionLZ4,
+			wantIdempotent:  true,
+		},
+		{
+			name: "Test idempotent producer requires acks all",
+			modify: func(producer *ProducerConfig) {
+				producer.Idempotent = true
+				producer.Acks = "leader"
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test idempotent producer requires retries",
+			modify: func(producer *ProducerConfig) {
+				producer.Idempotent = true
+				producer.Retries = 0
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Test unknown acks",
+			modify:  func(producer *ProducerConfig) { producer.Acks = "some" },
+			wantErr: true,
+		},
+		{
+			name:    "Test unknown compression",
+			modify:  func(producer *ProducerConfig) { producer.Compression = "brotli" },
+			wantErr: true,
+		},
+		{
+			name:    "Test negative retries",
+			modify:  func(producer *ProducerConfig) { producer.Retries = -1 },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := NewProducerConfig()
+			tt.modify(producer)
+			saramaConfig := sarama.NewConfig()
+			err := producer.Apply(saramaConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAcks, saramaConfig.Producer.RequiredAcks)
+			assert.Equal(t, tt.wantCompression, saramaConfig.Producer.Compression)
+			assert.Equal(t, tt.wantIdempotent, saramaConfig.Producer.Idempotent)
+			assert.True(t, saramaConfig.Producer.Return.Successes)
+			assert.NoError(t, saramaConfig.Validate())
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+
+	"github.com/xdg-go/scram"
+)
+
+var (
+	sha256Generator scram.HashGeneratorFcn = sha256.New
+	sha512Generator scram.HashGeneratorFcn = sha512.New
+)
+
+// scramClient implements the sarama.SCRAMClient interface
+type scramClient struct {
+	*scram.Client
+	*scram.ClientConversation
+	hashGenerator scram.HashGeneratorFcn
+}
+
+func (client *scramClient) Begin(userName, password, authzID string) error {
+	scramClient, err := client.hashGenerator.NewClient(userName, password, authzID)
+	if err != nil {
+		return err
+	}
+	client.Client = scramClient
+	client.ClientConversation = scramClient.NewConversation()
+	return nil
+}
+
+func (client *scramClient) Step(challenge string) (string, error) {
+	return client.ClientConversation.Step(challenge)
+}
+
+func (client *scramClient) Done() bool {
+	return client.ClientConversation.Done()
+}
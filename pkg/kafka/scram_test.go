@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xdg-go/scram"
+)
+
+func TestScramClient_Conversation(t *testing.T) {
+	tests := []struct {
+		name          string
+		hashGenerator scram.HashGeneratorFcn
+	}{
+		{
+			name:          "Test SCRAM-SHA-256 conversation start",
+			hashGenerator: sha256Generator,
+		},
+		{
+			name:          "Test SCRAM-SHA-512 conversation start",
+			hashGenerator: sha512Generator,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &scramClient{hashGenerator: tt.hashGenerator}
+			assert.NoError(t, client.Begin("hawkv6", "secret", ""))
+			firstMessage, err := client.Step("")
+			assert.NoError(t, err)
+			assert.Contains(t, firstMessage, "n=hawkv6")
+			assert.False(t, client.Done())
+			_, err = client.Step("invalid server message")
+			assert.Error(t, err)
+		})
+	}
+}
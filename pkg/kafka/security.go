@@ -0,0 +1,159 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+)
+
+const (
+	UsernameEnv = "CLAB_TELEMETRY_LINKER_KAFKA_USERNAME"
+	PasswordEnv = "CLAB_TELEMETRY_LINKER_KAFKA_PASSWORD"
+)
+
+const (
+	tlsEnabledKey            = "kafka.tls.enabled"
+	tlsCAFileKey             = "kafka.tls.ca-file"
+	tlsCertFileKey           = "kafka.tls.cert-file"
+	tlsKeyFileKey            = "kafka.tls.key-file"
+	tlsInsecureSkipVerifyKey = "kafka.tls.insecure-skip-verify"
+	saslMechanismKey         = "kafka.sasl.mechanism"
+	saslUsernameKey          = "kafka.sasl.username"
+	saslPasswordFileKey      = "kafka.sasl.password-file"
+)
+
+// SecurityConfig holds the TLS and SASL settings shared by the Kafka consumer and publisher
+type SecurityConfig struct {
+	TLSEnabled         bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+	SASLMechanism      string
+	Username           string
+	Password           string
+	PasswordFile       string
+}
+
+func NewSecurityConfig() *SecurityConfig {
+	return &SecurityConfig{}
+}
+
+// LoadDefaults fills all settings which were not provided as flag from the config file and the environment
+func (security *SecurityConfig) LoadDefaults(config config.Config) error {
+	if !security.TLSEnabled {
+		security.TLSEnabled = config.GetValue(tlsEnabledKey) == "true"
+	}
+	if !security.InsecureSkipVerify {
+		security.InsecureSkipVerify = config.GetValue(tlsInsecureSkipVerifyKey) == "true"
+	}
+	fields := map[string]*string{
+		tlsCAFileKey:        &security.CAFile,
+		tlsCertFileKey:      &security.CertFile,
+		tlsKeyFileKey:       &security.KeyFile,
+		saslMechanismKey:    &security.SASLMechanism,
+		saslUsernameKey:     &security.Username,
+		saslPasswordFileKey: &security.PasswordFile,
+	}
+	for key, field := range fields {
+		if *field == "" {
+			*field = config.GetValue(key)
+		}
+	}
+	if security.Username == "" {
+		security.Username = os.Getenv(UsernameEnv)
+	}
+	if security.Password == "" && security.PasswordFile != "" {
+		password, err := os.ReadFile(security.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("Unable to read SASL password file: %v", err)
+		}
+		security.Password = strings.TrimSpace(string(password))
+	}
+	if security.Password == "" {
+		security.Password = os.Getenv(PasswordEnv)
+	}
+	return nil
+}
+
+func (security *SecurityConfig) isTLSEnabled() bool {
+	return security.TLSEnabled || security.CAFile != "" || security.CertFile != ""
+}
+
+func (security *SecurityConfig) createTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: security.InsecureSkipVerify,
+	}
+	if security.CAFile != "" {
+		caCert, err := os.ReadFile(security.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read CA file: %v", err)
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("No valid certificate found in CA file %s", security.CAFile)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if security.CertFile != "" || security.KeyFile != "" {
+		if security.CertFile == "" || security.KeyFile == "" {
+			return nil, fmt.Errorf("Client certificate and key have to be provided together")
+		}
+		certificate, err := tls.LoadX509KeyPair(security.CertFile, security.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+func (security *SecurityConfig) applySASL(saramaConfig *sarama.Config) error {
+	if security.Username == "" || security.Password == "" {
+		return fmt.Errorf("SASL mechanism %s requires a username and a password", security.SASLMechanism)
+	}
+	saramaConfig.Net.SASL.Enable = true
+	saramaConfig.Net.SASL.Handshake = true
+	saramaConfig.Net.SASL.User = security.Username
+	saramaConfig.Net.SASL.Password = security.Password
+	switch strings.ToUpper(security.SASLMechanism) {
+	case sarama.SASLTypePlaintext:
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case sarama.SASLTypeSCRAMSHA256:
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha256Generator} }
+	case sarama.SASLTypeSCRAMSHA512:
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha512Generator} }
+	default:
+		return fmt.Errorf("Unknown SASL mechanism %q, use one of PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512", security.SASLMechanism)
+	}
+	return nil
+}
+
+// Apply configures TLS and SASL on the given sarama config
+func (security *SecurityConfig) Apply(saramaConfig *sarama.Config) error {
+	if security == nil {
+		return nil
+	}
+	if security.isTLSEnabled() {
+		tlsConfig, err := security.createTLSConfig()
+		if err != nil {
+			return err
+		}
+		saramaConfig.Net.TLS.Enable = true
+		saramaConfig.Net.TLS.Config = tlsConfig
+	}
+	if security.SASLMechanism != "" {
+		if err := security.applySASL(saramaConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package kafka
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "clab-telemetry-linker"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestNewSecurityConfig(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test creating security config",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotNil(t, NewSecurityConfig())
+		})
+	}
+}
+
+func TestSecurityConfig_LoadDefaults(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("secret-from-file\n"), 0600))
+	tests := []struct {
+		name         string
+		security     *SecurityConfig
+		configValues map[string]string
+		envUsername  string
+		envPassword  string
+		want         *SecurityConfig
+		wantErr      bool
+	}{
+		{
+			name:     "Test values from config file",
+			security: &SecurityConfig{},
+			configValues: map[string]string{
+				tlsEnabledKey:       "true",
+				tlsCAFileKey:        "/etc/kafka/ca.pem",
+				saslMechanismKey:    "SCRAM-SHA-512",
+				saslUsernameKey:     "hawkv6",
+				saslPasswordFileKey: passwordFile,
+			},
+			want: &SecurityConfig{
+				TLSEnabled:    true,
+				CAFile:        "/etc/kafka/ca.pem",
+				SASLMechanism: "SCRAM-SHA-512",
+				Username:      "hawkv6",
+				Password:      "secret-from-file",
+				PasswordFile:  passwordFile,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Test flags override config file",
+			security: &SecurityConfig{CAFile: "/flag/ca.pem", Username: "flag-user"},
+			configValues: map[string]string{
+				tlsCAFileKey:    "/etc/kafka/ca.pem",
+				saslUsernameKey: "hawkv6",
+			},
+			envPassword: "secret-from-env",
+			want: &SecurityConfig{
+				CAFile:   "/flag/ca.pem",
+				Username: "flag-user",
+				Password: "secret-from-env",
+			},
+			wantErr: false,
+		},
+		{
+			name:        "Test values from environment",
+			security:    &SecurityConfig{},
+			envUsername: "env-user",
+			envPassword: "secret-from-env",
+			want: &SecurityConfig{
+				Username: "env-user",
+				Password: "secret-from-env",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Test missing password file",
+			security: &SecurityConfig{PasswordFile: "/nonexistent/password"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(UsernameEnv, tt.envUsername)
+			t.Setenv(PasswordEnv, tt.envPassword)
+			mockConfig := config.NewMockConfig(gomock.NewController(t))
+			mockConfig.EXPECT().GetValue(gomock.Any()).DoAndReturn(func(key string) string {
+				return tt.configValues[key]
+			}).AnyTimes()
+			err := tt.security.LoadDefaults(mockConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, tt.security)
+			}
+		})
+	}
+}
+
+func TestSecurityConfig_Apply(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	assert.NoError(t, os.WriteFile(invalidFile, []byte("invalid"), 0600))
+	tests := []struct {
+		name          string
+		security      *SecurityConfig
+		wantTLS       bool
+		wantSASL      bool
+		wantMechanism sarama.SASLMechanism
+		wantErr       bool
+	}{
+		{
+			name:     "Test nil security config",
+			security: nil,
+			wantErr:  false,
+		},
+		{
+			name:     "Test plaintext",
+			security: &SecurityConfig{},
+			wantErr:  false,
+		},
+		{
+			name:     "Test TLS without CA",
+			security: &SecurityConfig{TLSEnabled: true},
+			wantTLS:  true,
+			wantErr:  false,
+		},
+		{
+			name:     "Test TLS with CA and client certificate",
+			security: &SecurityConfig{CAFile: certFile, CertFile: certFile, KeyFile: keyFile},
+			wantTLS:  true,
+			wantErr:  false,
+		},
+		{
+			name:     "Test TLS with missing CA file",
+			security: &SecurityConfig{CAFile: "/nonexistent/ca.pem"},
+			wantErr:  true,
+		},
+		{
+			name:     "Test TLS with invalid CA file",
+			security: &SecurityConfig{CAFile: invalidFile},
+			wantErr:  true,
+		},
+		{
+			name:     "Test TLS with certificate but without key",
+			security: &SecurityConfig{CertFile: certFile},
+			wantErr:  true,
+		},
+		{
+			name:     "Test TLS with invalid client certificate",
+			security: &SecurityConfig{CertFile: invalidFile, KeyFile: keyFile},
+			wantErr:  true,
+		},
+		{
+			name:          "Test SASL plain",
+			security:      &SecurityConfig{SASLMechanism: "PLAIN", Username: "hawkv6", Password: "secret"},
+			wantSASL:      true,
+			wantMechanism: sarama.SASLTypePlaintext,
+			wantErr:       false,
+		},
+		{
+			name:          "Test SASL SCRAM-SHA-256 over TLS",
+			security:      &SecurityConfig{TLSEnabled: true, SASLMechanism: "scram-sha-256", Username: "hawkv6", Password: "secret"},
+			wantTLS:       true,
+			wantSASL:      true,
+			wantMechanism: sarama.SASLTypeSCRAMSHA256,
+			wantErr:       false,
+		},
+		{
+			name:          "Test SASL SCRAM-SHA-512",
+			security:      &SecurityConfig{SASLMechanism: "SCRAM-SHA-512", Username: "hawkv6", Password: "secret"},
+			wantSASL:      true,
+			wantMechanism: sarama.SASLTypeSCRAMSHA512,
+			wantErr:       false,
+		},
+		{
+			name:     "Test SASL without password",
+			security: &SecurityConfig{SASLMechanism: "PLAIN", Username: "hawkv6"},
+			wantErr:  true,
+		},
+		{
+			name:     "Test unknown SASL mechanism",
+			security: &SecurityConfig{SASLMechanism: "GSSAPI", Username: "hawkv6", Password: "secret"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saramaConfig := sarama.NewConfig()
+			err := tt.security.Apply(saramaConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTLS, saramaConfig.Net.TLS.Enable)
+			assert.Equal(t, tt.wantSASL, saramaConfig.Net.SASL.Enable)
+			if tt.wantSASL {
+				assert.Equal(t, tt.wantMechanism, saramaConfig.Net.SASL.Mechanism)
+				assert.NoError(t, saramaConfig.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package link
+
+import (
+	"fmt"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/sirupsen/logrus"
+)
+
+// linksKey stores the endpoints of each link, the impairments are stored at the interfaces of both endpoints
+const linksKey = "links"
+
+type DefaultManager struct {
+	log     *logrus.Entry
+	config  config.Config
+	mapper  naming.Mapper
+	applier scenario.Applier
+}
+
+// NewDefaultManager applies both endpoints of a link in one transaction of the applier
+func NewDefaultManager(config config.Config, mapper naming.Mapper, applier scenario.Applier) *DefaultManager {
+	return &DefaultManager{
+		log:     logging.DefaultLogger.WithField("subsystem", subsystem),
+		config:  config,
+		mapper:  mapper,
+		applier: applier,
+	}
+}
+
+func (manager *DefaultManager) getClabInterfaceName(name string) string {
+	clabName, err := manager.mapper.ToClabName(name)
+	if err != nil {
+		manager.log.Debugf("Use interface name %s as given: %v\n", name, err)
+		return name
+	}
+	return clabName
+}
+
+// resolve maps the interface names to the containerlab names, so a link has the same key independent of the given names
+func (manager *DefaultManager) resolve(link Link) (Link, error) {
+	link.A.Interface = manager.getClabInterfaceName(link.A.Interface)
+	link.B.Interface = manager.getClabInterfaceName(link.B.Interface)
+	if link.A == link.B {
+		return Link{}, fmt.Errorf("Link endpoints are both %s", link.A)
+	}
+	return link, nil
+}
+
+func (manager *DefaultManager) createScenario(link Link, aToB, bToA scenario.Impairments) *scenario.Scenario {
+	nodes := map[string]map[string]scenario.Impairments{link.A.Node: {}, link.B.Node: {}}
+	nodes[link.A.Node][link.A.Interface] = aToB
+	nodes[link.B.Node][link.B.Interface] = bToA
+	return &scenario.Scenario{Nodes: nodes}
+}
+
+func (manager *DefaultManager) setEndpoints(link Link) error {
+	prefix := linksKey + "." + link.key() + "."
+	values := map[string]string{
+		"a.node":      link.A.Node,
+		"a.interface": link.A.Interface,
+		"b.node":      link.B.Node,
+		"b.interface": link.B.Interface,
+	}
+	for key, value := range values {
+		if err := manager.config.SetValue(prefix+key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set applies aToB on the egress of endpoint A and bToA on the egress of endpoint B, the link is only stored if both are applied
+func (manager *DefaultManager) Set(link Link, aToB, bToA scenario.Impairments) error {
+	link, err := manager.resolve(link)
+	if err != nil {
+		return err
+	}
+	if _, err := manager.applier.Apply(manager.createScenario(link, aToB, bToA)); err != nil {
+		return err
+	}
+	if err := manager.setEndpoints(link); err != nil {
+		return fmt.Errorf("Unable to store link %s: %v", link, err)
+	}
+	if err := manager.config.WriteConfig(); err != nil {
+		return fmt.Errorf("Unable to store link %s: %v", link, err)
+	}
+	manager.log.Infof("Set impairments of link %s\n", link)
+	return nil
+}
+
+// Delete removes the impairments of both endpoints and then the link, the link is kept if the impairments can not be removed
+func (manager *DefaultManager) Delete(link Link) error {
+	link, err := manager.resolve(link)
+	if err != nil {
+		return err
+	}
+	if _, err := manager.applier.Apply(manager.createScenario(link, scenario.Impairments{}, scenario.Impairments{})); err != nil {
+		return err
+	}
+	manager.config.DeleteValue(linksKey + "." + link.key())
+	if err := manager.config.WriteConfig(); err != nil {
+		return fmt.Errorf("Unable to remove link %s: %v", link, err)
+	}
+	manager.log.Infof("Deleted impairments of link %s\n", link)
+	return nil
+}
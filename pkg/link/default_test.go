@@ -0,0 +1,142 @@
+package link
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var defaultMapper, _ = naming.NewDefaultMapper(nil)
+
+func TestNewDefaultManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	assert.NotNil(t, NewDefaultManager(config.NewMockConfig(ctrl), defaultMapper, scenario.NewMockApplier(ctrl)))
+}
+
+func TestDefaultManager_Set(t *testing.T) {
+	aToB := scenario.Impairments{Delay: 10, Loss: 1}
+	bToA := scenario.Impairments{Delay: 20}
+	tests := []struct {
+		name     string
+		link     Link
+		want     *scenario.Scenario
+		applyErr error
+		writeErr error
+		wantErr  bool
+	}{
+		{
+			name: "Test set asymmetric link with telemetry interface names",
+			link: Link{A: Endpoint{Node: "XR-2", Interface: "GigabitEthernet0/0/0/1"}, B: Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}},
+			want: &scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+				"XR-2": {"Gi0-0-0-1": aToB},
+				"XR-1": {"Gi0-0-0-0": bToA},
+			}},
+		},
+		{
+			name: "Test set link between two interfaces of the same node",
+			link: Link{A: Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: Endpoint{Node: "XR-1", Interface: "Gi0-0-0-1"}},
+			want: &scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+				"XR-1": {"Gi0-0-0-0": aToB, "Gi0-0-0-1": bToA},
+			}},
+		},
+		{
+			name: "Test set link fails if storing the link fails",
+			link: Link{A: Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}},
+			want: &scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+				"XR-1": {"Gi0-0-0-0": aToB},
+				"XR-2": {"Gi0-0-0-1": bToA},
+			}},
+			writeErr: fmt.Errorf("permission denied"),
+			wantErr:  true,
+		},
+		{
+			name: "Test set link fails and keeps the config unchanged if an endpoint fails",
+			link: Link{A: Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}},
+			want: &scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+				"XR-1": {"Gi0-0-0-0": aToB},
+				"XR-2": {"Gi0-0-0-1": bToA},
+			}},
+			applyErr: fmt.Errorf("node not found, all changes are rolled back"),
+			wantErr:  true,
+		},
+		{
+			name:    "Test set link with the same endpoints",
+			link:    Link{A: Endpoint{Node: "XR-1", Interface: "GigabitEthernet0/0/0/0"}, B: Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockConfig := config.NewMockConfig(ctrl)
+			applier := scenario.NewMockApplier(ctrl)
+			if tt.want != nil {
+				applier.EXPECT().Apply(tt.want).Return(nil, tt.applyErr)
+			}
+			// the mock fails on any config change if the impairments are not applied
+			if tt.want != nil && tt.applyErr == nil {
+				a, b := tt.link.A.Node, tt.link.B.Node
+				key := "links.XR-1:Gi0-0-0-0<->XR-2:Gi0-0-0-1."
+				if a == b {
+					key = "links.XR-1:Gi0-0-0-0<->XR-1:Gi0-0-0-1."
+				}
+				mockConfig.EXPECT().SetValue(key+"a.node", a).Return(nil)
+				mockConfig.EXPECT().SetValue(key+"b.node", b).Return(nil)
+				mockConfig.EXPECT().SetValue(key+"a.interface", gomock.Any()).Return(nil)
+				mockConfig.EXPECT().SetValue(key+"b.interface", gomock.Any()).Return(nil)
+				mockConfig.EXPECT().WriteConfig().Return(tt.writeErr)
+			}
+			err := NewDefaultManager(mockConfig, defaultMapper, applier).Set(tt.link, aToB, bToA)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultManager_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		applyErr error
+		wantErr  bool
+	}{
+		{
+			name:    "Test delete link",
+			wantErr: false,
+		},
+		{
+			name:     "Test delete link fails and keeps the config unchanged if an endpoint fails",
+			applyErr: fmt.Errorf("node not found, all changes are rolled back"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockConfig := config.NewMockConfig(ctrl)
+			applier := scenario.NewMockApplier(ctrl)
+			applier.EXPECT().Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{
+				"XR-1": {"Gi0-0-0-0": {}},
+				"XR-2": {"Gi0-0-0-1": {}},
+			}}).Return(nil, tt.applyErr)
+			if tt.applyErr == nil {
+				mockConfig.EXPECT().DeleteValue("links.XR-1:Gi0-0-0-0<->XR-2:Gi0-0-0-1")
+				mockConfig.EXPECT().WriteConfig().Return(nil)
+			}
+			link := Link{A: Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}, B: Endpoint{Node: "XR-1", Interface: "GigabitEthernet0/0/0/0"}}
+			err := NewDefaultManager(mockConfig, defaultMapper, applier).Delete(link)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package link
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Endpoint is an interface of a node, given as node:interface
+type Endpoint struct {
+	Node      string `json:"node" yaml:"node"`
+	Interface string `json:"interface" yaml:"interface"`
+}
+
+func (endpoint Endpoint) String() string {
+	return endpoint.Node + ":" + endpoint.Interface
+}
+
+// ParseEndpoint splits node:interface at the first colon, interface names like GigabitEthernet0/0/0/0 are allowed
+func ParseEndpoint(value string) (Endpoint, error) {
+	node, interface_, found := strings.Cut(value, ":")
+	if !found || node == "" || interface_ == "" {
+		return Endpoint{}, fmt.Errorf("Invalid link endpoint %q, use node:interface", value)
+	}
+	return Endpoint{Node: node, Interface: interface_}, nil
+}
+
+// Link connects two endpoints, impairments of the direction A to B are applied on the egress of A and vice versa
+type Link struct {
+	A Endpoint `json:"a" yaml:"a"`
+	B Endpoint `json:"b" yaml:"b"`
+}
+
+func (link Link) String() string {
+	return link.A.String() + " <-> " + link.B.String()
+}
+
+// key identifies the link in the config independent of the order of the endpoints
+func (link Link) key() string {
+	a, b := link.A.String(), link.B.String()
+	if b < a {
+		a, b = b, a
+	}
+	return a + "<->" + b
+}
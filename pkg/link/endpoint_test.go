@@ -0,0 +1,56 @@
+package link
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Endpoint
+		wantErr bool
+	}{
+		{
+			name:  "Test containerlab interface name",
+			value: "XR-1:Gi0-0-0-0",
+			want:  Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"},
+		},
+		{
+			name:  "Test telemetry interface name",
+			value: "XR-1:GigabitEthernet0/0/0/0",
+			want:  Endpoint{Node: "XR-1", Interface: "GigabitEthernet0/0/0/0"},
+		},
+		{
+			name:    "Test endpoint without interface",
+			value:   "XR-1",
+			wantErr: true,
+		},
+		{
+			name:    "Test endpoint without node",
+			value:   ":Gi0-0-0-0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := ParseEndpoint(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, endpoint)
+		})
+	}
+}
+
+func TestLink_key(t *testing.T) {
+	a := Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}
+	b := Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}
+	assert.Equal(t, "XR-1:Gi0-0-0-0<->XR-2:Gi0-0-0-1", Link{A: a, B: b}.key())
+	assert.Equal(t, Link{A: a, B: b}.key(), Link{A: b, B: a}.key())
+	assert.Equal(t, "XR-1:Gi0-0-0-0 <-> XR-2:Gi0-0-0-1", Link{A: a, B: b}.String())
+}
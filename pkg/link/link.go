@@ -0,0 +1,10 @@
+package link
+
+import "github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+
+var subsystem = "link"
+
+type Manager interface {
+	Set(link Link, aToB, bToA scenario.Impairments) error
+	Delete(link Link) error
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: link.go
+//
+// Generated by this command:
+//
+//	mockgen -source=link.go -destination=link_mock.go -package=link
+//
+
+// Package link is a generated GoMock package.
+package link
+
+import (
+	reflect "reflect"
+
+	scenario "github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockManager is a mock of Manager interface.
+type MockManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockManagerMockRecorder
+}
+
+// MockManagerMockRecorder is the mock recorder for MockManager.
+type MockManagerMockRecorder struct {
+	mock *MockManager
+}
+
+// NewMockManager creates a new mock instance.
+func NewMockManager(ctrl *gomock.Controller) *MockManager {
+	mock := &MockManager{ctrl: ctrl}
+	mock.recorder = &MockManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockManager) EXPECT() *MockManagerMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockManager) Delete(link Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockManagerMockRecorder) Delete(link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockManager)(nil).Delete), link)
+}
+
+// Set mocks base method.
+func (m *MockManager) Set(link Link, aToB, bToA scenario.Impairments) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", link, aToB, bToA)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockManagerMockRecorder) Set(link, aToB, bToA any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockManager)(nil).Set), link, aToB, bToA)
+}
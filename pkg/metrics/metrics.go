@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var subsystem = "metrics"
+
+const namespace = "clab_telemetry_linker"
+
+// Reasons why messages are dropped, they are used as reason label
+const (
+	ReasonInvalidJSON           = "invalid_json"
+	ReasonInvalidFields         = "invalid_fields"
+	ReasonUnknownMeasurement    = "unknown_measurement"
+	ReasonFiltered              = "filtered"
+	ReasonInterfaceNameMismatch = "interface_name_mismatch"
+	ReasonInvalidImpairments    = "invalid_impairments"
+	ReasonUnknownType           = "unknown_type"
+	ReasonEncode                = "encode"
+	ReasonProduce               = "produce"
+)
+
+type Server interface {
+	Start()
+	Stop()
+}
+
+// Registry contains the pipeline metrics and the Go runtime and process metrics served on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	ConsumerReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "consumer",
+		Name:      "messages_received_total",
+		Help:      "Messages received from the receiver topic by measurement and node.",
+	}, []string{"measurement", "node"})
+	ConsumerDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "consumer",
+		Name:      "messages_dropped_total",
+		Help:      "Messages which were not handed to the processor by measurement, node and reason.",
+	}, []string{"measurement", "node", "reason"})
+	ProcessorProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "processor",
+		Name:      "messages_processed_total",
+		Help:      "Messages handed to the publisher by message type and node.",
+	}, []string{"type", "node"})
+	ProcessorDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "processor",
+		Name:      "messages_dropped_total",
+		Help:      "Messages which could not be processed by message type, node and reason.",
+	}, []string{"type", "node", "reason"})
+	ProcessorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "processor",
+		Name:      "processing_duration_seconds",
+		Help:      "Time to apply the impairments to a message by message type.",
+		Buckets:   []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05},
+	}, []string{"type"})
+	PublisherPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publisher",
+		Name:      "messages_published_total",
+		Help:      "Messages acknowledged by Kafka or written to InfluxDB or the file by measurement and node.",
+	}, []string{"measurement", "node"})
+	PublisherFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publisher",
+		Name:      "messages_failed_total",
+		Help:      "Messages which could not be encoded or produced by measurement, node and reason.",
+	}, []string{"measurement", "node", "reason"})
+	PublisherQueueDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publisher",
+		Name:      "queue_dropped_total",
+		Help:      "Messages dropped because the queue of a sink of the fan-out publisher was full by sink, measurement and node.",
+	}, []string{"sink", "measurement", "node"})
+	PublisherMessageSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "publisher",
+		Name:      "message_size_bytes",
+		Help:      "Size of the encoded messages by measurement.",
+		Buckets:   prometheus.ExponentialBuckets(64, 2, 8),
+	}, []string{"measurement"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ConsumerReceived,
+		ConsumerDropped,
+		ProcessorProcessed,
+		ProcessorDropped,
+		ProcessorDuration,
+		PublisherPublished,
+		PublisherFailed,
+		PublisherQueueDropped,
+		PublisherMessageSize,
+	)
+}
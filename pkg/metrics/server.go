@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+type HTTPServer struct {
+	log    *logrus.Entry
+	server *http.Server
+}
+
+// NewHTTPServer serves the metrics of the Registry on address under /metrics
+func NewHTTPServer(address string) *HTTPServer {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	return &HTTPServer{
+		log:    logging.DefaultLogger.WithField("subsystem", subsystem),
+		server: &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+	}
+}
+
+// Start serves the metrics until Stop is called
+func (server *HTTPServer) Start() {
+	server.log.Infof("Serving metrics on %s/metrics\n", server.server.Addr)
+	if err := server.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		server.log.Errorf("Error serving metrics: %v\n", err)
+	}
+}
+
+func (server *HTTPServer) Stop() {
+	server.log.Infoln("Stopping metrics server")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.server.Shutdown(ctx); err != nil {
+		server.log.Errorf("Error stopping metrics server: %v\n", err)
+	}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPServer_Metrics(t *testing.T) {
+	ConsumerReceived.WithLabelValues("isis", "XR-1").Inc()
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantContains string
+	}{
+		{
+			name:         "Test metrics are served",
+			path:         "/metrics",
+			wantStatus:   http.StatusOK,
+			wantContains: `clab_telemetry_linker_consumer_messages_received_total{measurement="isis",node="XR-1"}`,
+		},
+		{
+			name:         "Test runtime metrics are served",
+			path:         "/metrics",
+			wantStatus:   http.StatusOK,
+			wantContains: "go_goroutines",
+		},
+		{
+			name:       "Test unknown path",
+			path:       "/",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			NewHTTPServer(":0").server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tt.wantContains)
+		})
+	}
+}
+
+func TestHTTPServer_StartStop(t *testing.T) {
+	server := NewHTTPServer("127.0.0.1:0")
+	done := make(chan struct{})
+	go func() {
+		server.Start()
+		close(done)
+	}()
+	server.Stop()
+	<-done
+}
@@ -0,0 +1,82 @@
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoMatchingRule is returned if no rule matches the interface name
+var ErrNoMatchingRule = errors.New("No interface naming rule matches")
+
+// rulesKey is the config key of the custom rules, they are tried before the built-in rules
+const rulesKey = "interface-names"
+
+// Rule replaces an interface name matching Pattern with Replacement, capture groups are referenced as $1, $2, ...
+type Rule struct {
+	Pattern     string `koanf:"pattern"`
+	Replacement string `koanf:"replacement"`
+}
+
+// subinterface matches an optional subinterface suffix, subinterfaces are mapped to their parent interface
+const subinterface = `(?:\.\d+)?`
+
+// DefaultRules map the IOS XR interface naming families to the names used by containerlab
+var DefaultRules = []Rule{
+	{Pattern: `^GigabitEthernet(\d+)/(\d+)/(\d+)/(\d+)` + subinterface + `$`, Replacement: "Gi$1-$2-$3-$4"},
+	{Pattern: `^TenGigE(\d+)/(\d+)/(\d+)/(\d+)` + subinterface + `$`, Replacement: "Te$1-$2-$3-$4"},
+	{Pattern: `^TwentyFiveGigE(\d+)/(\d+)/(\d+)/(\d+)` + subinterface + `$`, Replacement: "TF$1-$2-$3-$4"},
+	{Pattern: `^FortyGigE(\d+)/(\d+)/(\d+)/(\d+)` + subinterface + `$`, Replacement: "Fo$1-$2-$3-$4"},
+	{Pattern: `^HundredGigE(\d+)/(\d+)/(\d+)/(\d+)` + subinterface + `$`, Replacement: "Hu$1-$2-$3-$4"},
+	{Pattern: `^FourHundredGigE(\d+)/(\d+)/(\d+)/(\d+)` + subinterface + `$`, Replacement: "FH$1-$2-$3-$4"},
+	{Pattern: `^Bundle-Ether(\d+)` + subinterface + `$`, Replacement: "BE$1"},
+}
+
+type compiledRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type DefaultMapper struct {
+	log   *logrus.Entry
+	rules []compiledRule
+}
+
+// NewDefaultMapper compiles the given rules followed by the DefaultRules, the first matching rule is applied
+func NewDefaultMapper(rules []Rule) (*DefaultMapper, error) {
+	mapper := &DefaultMapper{
+		log: logging.DefaultLogger.WithField("subsystem", subsystem),
+	}
+	for _, rule := range append(append([]Rule{}, rules...), DefaultRules...) {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid interface naming pattern %q: %v", rule.Pattern, err)
+		}
+		mapper.rules = append(mapper.rules, compiledRule{pattern: pattern, replacement: rule.Replacement})
+	}
+	return mapper, nil
+}
+
+// NewDefaultMapperFromConfig reads the custom rules from the config file
+func NewDefaultMapperFromConfig(config config.Config) (*DefaultMapper, error) {
+	var rules []Rule
+	if err := config.Unmarshal(rulesKey, &rules); err != nil {
+		return nil, fmt.Errorf("Unable to read interface naming rules: %v", err)
+	}
+	return NewDefaultMapper(rules)
+}
+
+func (mapper *DefaultMapper) ToClabName(name string) (string, error) {
+	for _, rule := range mapper.rules {
+		if rule.pattern.MatchString(name) {
+			clabName := rule.pattern.ReplaceAllString(name, rule.replacement)
+			mapper.log.Debugf("Map interface %s to %s", name, clabName)
+			return clabName, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNoMatchingRule, name)
+}
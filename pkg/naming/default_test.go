@@ -0,0 +1,179 @@
+package naming
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewDefaultMapper(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr bool
+	}{
+		{
+			name:  "Test with built-in rules only",
+			rules: nil,
+		},
+		{
+			name:  "Test with valid custom rule",
+			rules: []Rule{{Pattern: `^Ethernet(\d+)$`, Replacement: "eth$1"}},
+		},
+		{
+			name:    "Test with invalid custom rule",
+			rules:   []Rule{{Pattern: `^Ethernet(\d+$`, Replacement: "eth$1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := NewDefaultMapper(tt.rules)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, mapper)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, mapper.rules, len(tt.rules)+len(DefaultRules))
+			}
+		})
+	}
+}
+
+func TestDefaultMapper_ToClabName(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         []Rule
+		interfaceName string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:          "Test GigabitEthernet",
+			interfaceName: "GigabitEthernet0/0/0/0",
+			want:          "Gi0-0-0-0",
+		},
+		{
+			name:          "Test TenGigE",
+			interfaceName: "TenGigE0/0/0/1",
+			want:          "Te0-0-0-1",
+		},
+		{
+			name:          "Test TwentyFiveGigE",
+			interfaceName: "TwentyFiveGigE0/0/0/2",
+			want:          "TF0-0-0-2",
+		},
+		{
+			name:          "Test FortyGigE",
+			interfaceName: "FortyGigE0/0/0/3",
+			want:          "Fo0-0-0-3",
+		},
+		{
+			name:          "Test HundredGigE",
+			interfaceName: "HundredGigE0/0/0/4",
+			want:          "Hu0-0-0-4",
+		},
+		{
+			name:          "Test FourHundredGigE",
+			interfaceName: "FourHundredGigE0/0/0/5",
+			want:          "FH0-0-0-5",
+		},
+		{
+			name:          "Test Bundle-Ether",
+			interfaceName: "Bundle-Ether10",
+			want:          "BE10",
+		},
+		{
+			name:          "Test subinterface is mapped to parent",
+			interfaceName: "TenGigE0/0/0/1.100",
+			want:          "Te0-0-0-1",
+		},
+		{
+			name:          "Test Bundle-Ether subinterface is mapped to parent",
+			interfaceName: "Bundle-Ether10.200",
+			want:          "BE10",
+		},
+		{
+			name:          "Test custom rule takes precedence",
+			rules:         []Rule{{Pattern: `^GigabitEthernet0/0/0/(\d+)$`, Replacement: "eth$1"}},
+			interfaceName: "GigabitEthernet0/0/0/3",
+			want:          "eth3",
+		},
+		{
+			name:          "Test built-in rule is used if custom rule does not match",
+			rules:         []Rule{{Pattern: `^Ethernet(\d+)$`, Replacement: "eth$1"}},
+			interfaceName: "GigabitEthernet0/0/0/3",
+			want:          "Gi0-0-0-3",
+		},
+		{
+			name:          "Test unknown interface",
+			interfaceName: "Loopback0",
+			wantErr:       true,
+		},
+		{
+			name:          "Test clab name is not mapped",
+			interfaceName: "Gi0-0-0-0",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := NewDefaultMapper(tt.rules)
+			assert.NoError(t, err)
+			got, err := mapper.ToClabName(tt.interfaceName)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNoMatchingRule)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewDefaultMapperFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []Rule
+		configErr error
+		wantErr   bool
+	}{
+		{
+			name:  "Test with rules from config",
+			rules: []Rule{{Pattern: `^Ethernet(\d+)$`, Replacement: "eth$1"}},
+		},
+		{
+			name:  "Test without rules in config",
+			rules: nil,
+		},
+		{
+			name:      "Test with config error",
+			configErr: errors.New("error"),
+			wantErr:   true,
+		},
+		{
+			name:    "Test with invalid rule in config",
+			rules:   []Rule{{Pattern: `(`, Replacement: "eth$1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig := config.NewMockConfig(gomock.NewController(t))
+			mockConfig.EXPECT().Unmarshal(rulesKey, gomock.Any()).DoAndReturn(func(key string, out interface{}) error {
+				*out.(*[]Rule) = tt.rules
+				return tt.configErr
+			})
+			mapper, err := NewDefaultMapperFromConfig(mockConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, mapper.rules, len(tt.rules)+len(DefaultRules))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package naming
+
+var subsystem = "naming"
+
+// Mapper translates interface names used in the telemetry (e.g. GigabitEthernet0/0/0/0) to containerlab interface names (e.g. Gi0-0-0-0)
+type Mapper interface {
+	ToClabName(name string) (string, error)
+}
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: naming.go
+//
+// Generated by this command:
+//
+//	mockgen -source=naming.go -destination=naming_mock.go -package=naming
+//
+
+// Package naming is a generated GoMock package.
+package naming
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMapper is a mock of Mapper interface.
+type MockMapper struct {
+	ctrl     *gomock.Controller
+	recorder *MockMapperMockRecorder
+}
+
+// MockMapperMockRecorder is the mock recorder for MockMapper.
+type MockMapperMockRecorder struct {
+	mock *MockMapper
+}
+
+// NewMockMapper creates a new mock instance.
+func NewMockMapper(ctrl *gomock.Controller) *MockMapper {
+	mock := &MockMapper{ctrl: ctrl}
+	mock.recorder = &MockMapperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMapper) EXPECT() *MockMapperMockRecorder {
+	return m.recorder
+}
+
+// ToClabName mocks base method.
+func (m *MockMapper) ToClabName(name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToClabName", name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToClabName indicates an expected call of ToClabName.
+func (mr *MockMapperMockRecorder) ToClabName(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToClabName", reflect.TypeOf((*MockMapper)(nil).ToClabName), name)
+}
@@ -0,0 +1,51 @@
+package naming
+
+import (
+	"sync"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// ReloadingMapper reads the custom rules again after each reload of the config file.
+// If the changed rules are invalid, the previous rules are kept until they are fixed.
+type ReloadingMapper struct {
+	log    *logrus.Entry
+	config config.Config
+	mutex  sync.RWMutex
+	mapper *DefaultMapper
+}
+
+// NewReloadingMapper fails if the rules in the config file are invalid, like NewDefaultMapperFromConfig
+func NewReloadingMapper(config config.Config, notifier config.ChangeNotifier) (*ReloadingMapper, error) {
+	mapper, err := NewDefaultMapperFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	reloadingMapper := &ReloadingMapper{
+		log:    logging.DefaultLogger.WithField("subsystem", subsystem),
+		config: config,
+		mapper: mapper,
+	}
+	notifier.OnChange(reloadingMapper.reload)
+	return reloadingMapper, nil
+}
+
+func (mapper *ReloadingMapper) reload() {
+	defaultMapper, err := NewDefaultMapperFromConfig(mapper.config)
+	if err != nil {
+		mapper.log.Errorf("Keeping the previous interface naming rules: %v", err)
+		return
+	}
+	mapper.mutex.Lock()
+	defer mapper.mutex.Unlock()
+	mapper.mapper = defaultMapper
+}
+
+func (mapper *ReloadingMapper) ToClabName(name string) (string, error) {
+	mapper.mutex.RLock()
+	defaultMapper := mapper.mapper
+	mapper.mutex.RUnlock()
+	return defaultMapper.ToClabName(name)
+}
@@ -0,0 +1,74 @@
+package naming
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewReloadingMapper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockConfig := config.NewMockConfig(ctrl)
+	mockConfig.EXPECT().Unmarshal(rulesKey, gomock.Any()).Return(errors.New("error"))
+	mapper, err := NewReloadingMapper(mockConfig, config.NewMockChangeNotifier(ctrl))
+	assert.Error(t, err)
+	assert.Nil(t, mapper)
+}
+
+func TestReloadingMapper_ToClabName(t *testing.T) {
+	tests := []struct {
+		name        string
+		reloaded    []Rule
+		reloadedErr error
+		want        string
+	}{
+		{
+			name:     "Test changed rules are applied after reload",
+			reloaded: []Rule{{Pattern: `^Ethernet(\d+)$`, Replacement: "e$1"}},
+			want:     "e1",
+		},
+		{
+			name:     "Test invalid changed rules keep the previous rules",
+			reloaded: []Rule{{Pattern: `(`, Replacement: "e$1"}},
+			want:     "eth1",
+		},
+		{
+			name:        "Test config error keeps the previous rules",
+			reloadedErr: errors.New("error"),
+			want:        "eth1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockConfig := config.NewMockConfig(ctrl)
+			notifier := config.NewMockChangeNotifier(ctrl)
+			rules := []Rule{{Pattern: `^Ethernet(\d+)$`, Replacement: "eth$1"}}
+			mockConfig.EXPECT().Unmarshal(rulesKey, gomock.Any()).DoAndReturn(func(key string, out interface{}) error {
+				*out.(*[]Rule) = rules
+				return nil
+			})
+			var reload func()
+			notifier.EXPECT().OnChange(gomock.Any()).Do(func(callback func()) {
+				reload = callback
+			})
+			mapper, err := NewReloadingMapper(mockConfig, notifier)
+			assert.NoError(t, err)
+			got, err := mapper.ToClabName("Ethernet1")
+			assert.NoError(t, err)
+			assert.Equal(t, "eth1", got)
+
+			mockConfig.EXPECT().Unmarshal(rulesKey, gomock.Any()).DoAndReturn(func(key string, out interface{}) error {
+				*out.(*[]Rule) = tt.reloaded
+				return tt.reloadedErr
+			})
+			reload()
+			got, err = mapper.ToClabName("Ethernet1")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+)
+
+// Write renders value as JSON or YAML, the table is created by the caller as header followed by the rows
+func Write(writer io.Writer, format string, value interface{}, table func() [][]string) error {
+	switch format {
+	case "", Table:
+		return WriteTable(writer, table())
+	case JSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case YAML:
+		encoder := yaml.NewEncoder(writer)
+		defer encoder.Close()
+		return encoder.Encode(value)
+	default:
+		return fmt.Errorf("Unknown output format %q, use %s, %s or %s", format, Table, JSON, YAML)
+	}
+}
+
+// WriteTable prints the first row as header, all columns are padded to the widest cell
+func WriteTable(writer io.Writer, table [][]string) error {
+	if len(table) == 0 {
+		return nil
+	}
+	widths := make([]int, len(table[0]))
+	for _, columns := range table {
+		for i, column := range columns {
+			if len(column) > widths[i] {
+				widths[i] = len(column)
+			}
+		}
+	}
+	separator := "+"
+	for _, width := range widths {
+		separator += strings.Repeat("-", width+2) + "+"
+	}
+	var builder strings.Builder
+	builder.WriteString(separator + "\n")
+	for i, columns := range table {
+		builder.WriteString("|")
+		for j, column := range columns {
+			builder.WriteString(fmt.Sprintf(" %-*s |", widths[j], column))
+		}
+		builder.WriteString("\n")
+		if i == 0 {
+			builder.WriteString(separator + "\n")
+		}
+	}
+	builder.WriteString(separator + "\n")
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
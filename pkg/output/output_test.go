@@ -0,0 +1,89 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	type entry struct {
+		Name  string `json:"name" yaml:"name"`
+		Value int    `json:"value" yaml:"value"`
+	}
+	value := []entry{{Name: "a", Value: 1}}
+	table := func() [][]string {
+		return [][]string{{"Name", "Value"}, {"a", "1"}}
+	}
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Test default table output",
+			format: "",
+			want:   "+------+-------+\n| Name | Value |\n+------+-------+\n| a    | 1     |\n+------+-------+\n",
+		},
+		{
+			name:   "Test JSON output",
+			format: JSON,
+			want:   "[\n  {\n    \"name\": \"a\",\n    \"value\": 1\n  }\n]\n",
+		},
+		{
+			name:   "Test YAML output",
+			format: YAML,
+			want:   "- name: a\n  value: 1\n",
+		},
+		{
+			name:    "Test unknown output",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			err := Write(&buffer, tt.format, value, table)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	tests := []struct {
+		name  string
+		table [][]string
+		want  string
+	}{
+		{
+			name:  "Test empty table",
+			table: nil,
+			want:  "",
+		},
+		{
+			name:  "Test header only",
+			table: [][]string{{"Interface"}},
+			want:  "+-----------+\n| Interface |\n+-----------+\n+-----------+\n",
+		},
+		{
+			name:  "Test columns are padded to the widest cell",
+			table: [][]string{{"A", "B"}, {"long value", "x"}},
+			want:  "+------------+---+\n| A          | B |\n+------------+---+\n| long value | x |\n+------------+---+\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			assert.NoError(t, WriteTable(&buffer, tt.table))
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
@@ -1,17 +1,20 @@
 package processor
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
-	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/deadletter"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,9 +25,23 @@ type DefaultProcessor struct {
 	processedMsgChan   chan consumer.Message
 	quitChan           chan bool
 	helper             helpers.Helper
+	deadLetter         deadletter.Writer
+	mapper             naming.Mapper
+	utilization        map[string]*utilizationState
+	// processingStart is the time the current message was taken from the consumer, the processor handles one message at a time
+	processingStart time.Time
 }
 
-func NewDefaultProcessor(config config.Config, unprocessedMsgChan chan consumer.Message, processedMsgChan chan consumer.Message, helper helpers.Helper) *DefaultProcessor {
+// utilizationState keeps the last received and published counters of an interface
+type utilizationState struct {
+	timestamp         int64
+	inOctets          uint64
+	outOctets         uint64
+	impairedInOctets  uint64
+	impairedOutOctets uint64
+}
+
+func NewDefaultProcessor(config config.Config, unprocessedMsgChan chan consumer.Message, processedMsgChan chan consumer.Message, helper helpers.Helper, deadLetter deadletter.Writer, mapper naming.Mapper) *DefaultProcessor {
 	return &DefaultProcessor{
 		log:                logging.DefaultLogger.WithField("subsystem", subsystem),
 		config:             config,
@@ -32,15 +49,14 @@ func NewDefaultProcessor(config config.Config, unprocessedMsgChan chan consumer.
 		processedMsgChan:   processedMsgChan,
 		quitChan:           make(chan bool),
 		helper:             helper,
+		deadLetter:         deadLetter,
+		mapper:             mapper,
+		utilization:        make(map[string]*utilizationState),
 	}
 }
 
 func (processor *DefaultProcessor) shortenInterfaceName(name string) (string, error) {
-	re := regexp.MustCompile(`GigabitEthernet(\d+)/(\d+)/(\d+)/(\d+)`)
-	if !re.MatchString(name) {
-		return "", fmt.Errorf("interface name %s does not match expected pattern", name)
-	}
-	return re.ReplaceAllString(name, "Gi$1-$2-$3-$4"), nil
+	return processor.mapper.ToClabName(name)
 }
 
 func (processor *DefaultProcessor) getDelayValues(impairmentsPrefix string) (uint32, uint32, error) {
@@ -96,16 +112,18 @@ func (processor *DefaultProcessor) setDelayValues(msg *consumer.DelayMessage, de
 }
 
 func (processor *DefaultProcessor) processDelayMessage(msg *consumer.DelayMessage) {
-	processor.log.Debugf("Process delay of node %s of interface %s", msg.Tags.Source, msg.Tags.InterfaceName)
-	shortInterfaceName, err := processor.shortenInterfaceName(msg.Tags.InterfaceName)
+	processor.log.Debugf("Process delay of node %s of interface %s", msg.Tags.Source(), msg.Tags.InterfaceName())
+	shortInterfaceName, err := processor.shortenInterfaceName(msg.Tags.InterfaceName())
 	if err != nil {
 		processor.log.Debugf("Failed to shorten interface name: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInterfaceNameMismatch, err)
 		return
 	}
-	impairmentsPrefix := processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source, shortInterfaceName)
+	impairmentsPrefix := processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source(), shortInterfaceName)
 	delay, jitter, err := processor.getDelayValues(impairmentsPrefix)
 	if err != nil {
 		processor.log.Errorf("Failed to get delay values: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInvalidImpairments, err)
 		return
 	}
 
@@ -113,8 +131,8 @@ func (processor *DefaultProcessor) processDelayMessage(msg *consumer.DelayMessag
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	randomFactor := (math.Log10(float64(delay+1))*0.2 - 0.1) * 0.05 * (r.Float64()*2 - 1)
 	processor.setDelayValues(msg, delay, jitter, randomFactor)
-	processor.log.Debugf("Adjusted delay of node %s of interface %s to: %d", msg.Tags.Source, msg.Tags.InterfaceName, delay)
-	processor.processedMsgChan <- msg
+	processor.log.Debugf("Adjusted delay of node %s of interface %s to: %d", msg.Tags.Source(), msg.Tags.InterfaceName(), delay)
+	processor.forwardMessage(msg)
 }
 
 func (processor *DefaultProcessor) getLossValue(impairmentsPrefix string) (float64, error) {
@@ -133,15 +151,17 @@ func (processor *DefaultProcessor) setLossValue(msg *consumer.LossMessage, loss
 	msg.LossPercentage = loss + loss*randomFactor
 }
 func (processor *DefaultProcessor) processLossMessage(msg *consumer.LossMessage) {
-	processor.log.Debugf("Process loss of node %s of interface %s", msg.Tags.Source, msg.Tags.InterfaceName)
-	shortInterfaceName, err := processor.shortenInterfaceName(msg.Tags.InterfaceName)
+	processor.log.Debugf("Process loss of node %s of interface %s", msg.Tags.Source(), msg.Tags.InterfaceName())
+	shortInterfaceName, err := processor.shortenInterfaceName(msg.Tags.InterfaceName())
 	if err != nil {
 		processor.log.Debugf("Failed to shorten interface name: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInterfaceNameMismatch, err)
 		return
 	}
-	loss, err := processor.getLossValue(processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source, shortInterfaceName))
+	loss, err := processor.getLossValue(processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source(), shortInterfaceName))
 	if err != nil {
 		processor.log.Errorf("Failed to get loss value: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInvalidImpairments, err)
 		return
 	}
 
@@ -150,8 +170,8 @@ func (processor *DefaultProcessor) processLossMessage(msg *consumer.LossMessage)
 	randomFactor := (math.Log10(loss+1)*0.2 - 0.1) * 0.1 * (r.Float64()*2 - 1)
 	processor.setLossValue(msg, loss, randomFactor)
 
-	processor.log.Debugf("Adjusted loss of node %s of interface %s to: %f", msg.Tags.Source, msg.Tags.InterfaceName, loss)
-	processor.processedMsgChan <- msg
+	processor.log.Debugf("Adjusted loss of node %s of interface %s to: %f", msg.Tags.Source(), msg.Tags.InterfaceName(), loss)
+	processor.forwardMessage(msg)
 }
 
 func (proessor *DefaultProcessor) getBandwidthValue(impairmentsPrefix string) (float64, error) {
@@ -166,22 +186,160 @@ func (proessor *DefaultProcessor) getBandwidthValue(impairmentsPrefix string) (f
 	return 1000000, nil // 1Gbps
 }
 func (processor *DefaultProcessor) processBandwidthMessage(msg *consumer.BandwidthMessage) {
-	processor.log.Debugf("Process bandwidth of node %s of interface %s", msg.Tags.Source, msg.Tags.InterfaceName)
-	shortInterfaceName, err := processor.shortenInterfaceName(msg.Tags.InterfaceName)
+	processor.log.Debugf("Process bandwidth of node %s of interface %s", msg.Tags.Source(), msg.Tags.InterfaceName())
+	shortInterfaceName, err := processor.shortenInterfaceName(msg.Tags.InterfaceName())
 	if err != nil {
 		processor.log.Debugf("Failed to shorten interface name: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInterfaceNameMismatch, err)
 		return
 	}
-	bandwidth, err := processor.getBandwidthValue(processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source, shortInterfaceName))
+	bandwidth, err := processor.getBandwidthValue(processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source(), shortInterfaceName))
 	if err != nil {
 		processor.log.Errorf("Failed to get bandwidth value: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInvalidImpairments, err)
 		return
 	}
 	msg.Bandwidth = bandwidth
-	processor.processedMsgChan <- msg
+	processor.forwardMessage(msg)
+}
+
+func (processor *DefaultProcessor) getBackgroundLoadValue(impairmentsPrefix string) (float64, error) {
+	backgroundLoad := processor.config.GetValue(impairmentsPrefix + "background-load")
+	if backgroundLoad != "" {
+		if backgroundLoadValue, err := strconv.ParseFloat(backgroundLoad, 64); err != nil {
+			return 0, fmt.Errorf("Failed to convert background load to float64: %v", err)
+		} else {
+			return backgroundLoadValue, nil
+		}
+	}
+	return 0, nil
+}
+
+// impairCounter continues the published counter with the received progression plus background load, capped at the capacity of the interval
+func (processor *DefaultProcessor) impairCounter(previous, current, previousImpaired uint64, capacity, backgroundOctets float64) uint64 {
+	delta := float64(current)
+	if current >= previous {
+		delta = float64(current - previous)
+	} // otherwise the counter was reset and the current value is the progression since the reset
+	delta += backgroundOctets
+	if delta > capacity {
+		delta = capacity
+	}
+	return previousImpaired + uint64(delta)
+}
+
+func (processor *DefaultProcessor) setUtilizationValues(msg *consumer.UtilizationMessage, state *utilizationState, bandwidth float64, backgroundLoad float64, randomFactor float64) {
+	interval := float64(msg.Timestamp - state.timestamp)
+	capacity := bandwidth * 1000 / 8 * interval // kbit/s to octets
+	backgroundOctets := capacity * backgroundLoad / 100 * (1 + randomFactor)
+	impairedInOctets := processor.impairCounter(state.inOctets, msg.InOctets, state.impairedInOctets, capacity, backgroundOctets)
+	impairedOutOctets := processor.impairCounter(state.outOctets, msg.OutOctets, state.impairedOutOctets, capacity, backgroundOctets)
+	state.timestamp, state.inOctets, state.outOctets = msg.Timestamp, msg.InOctets, msg.OutOctets
+	state.impairedInOctets, state.impairedOutOctets = impairedInOctets, impairedOutOctets
+	msg.InOctets, msg.OutOctets = impairedInOctets, impairedOutOctets
+}
+
+func (processor *DefaultProcessor) processUtilizationMessage(msg *consumer.UtilizationMessage) {
+	processor.log.Debugf("Process utilization of node %s of interface %s", msg.Tags.Source(), msg.GetInterfaceName())
+	shortInterfaceName, err := processor.shortenInterfaceName(msg.GetInterfaceName())
+	if err != nil {
+		processor.log.Debugf("Failed to shorten interface name: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInterfaceNameMismatch, err)
+		return
+	}
+	impairmentsPrefix := processor.helper.GetDefaultImpairmentsPrefix(msg.Tags.Source(), shortInterfaceName)
+	bandwidth, err := processor.getBandwidthValue(impairmentsPrefix)
+	if err != nil {
+		processor.log.Errorf("Failed to get bandwidth value: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInvalidImpairments, err)
+		return
+	}
+	backgroundLoad, err := processor.getBackgroundLoadValue(impairmentsPrefix)
+	if err != nil {
+		processor.log.Errorf("Failed to get background load value: %v", err)
+		processor.dropMessage(msg, metrics.ReasonInvalidImpairments, err)
+		return
+	}
+
+	key := msg.Tags.Source() + "/" + shortInterfaceName
+	state, ok := processor.utilization[key]
+	if !ok || msg.Timestamp <= state.timestamp {
+		// without a previous sample there is no interval, the counters are published as received
+		processor.utilization[key] = &utilizationState{
+			timestamp:         msg.Timestamp,
+			inOctets:          msg.InOctets,
+			outOctets:         msg.OutOctets,
+			impairedInOctets:  msg.InOctets,
+			impairedOutOctets: msg.OutOctets,
+		}
+		processor.forwardMessage(msg)
+		return
+	}
+
+	// Vary the background load by up to 10%
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	randomFactor := 0.1 * (r.Float64()*2 - 1)
+	processor.setUtilizationValues(msg, state, bandwidth, backgroundLoad, randomFactor)
+	processor.log.Debugf("Adjusted utilization of node %s of interface %s to in: %d out: %d octets", msg.Tags.Source(), msg.GetInterfaceName(), msg.InOctets, msg.OutOctets)
+	processor.forwardMessage(msg)
+}
+
+// messageType is the type label of the metrics
+func messageType(msg consumer.Message) string {
+	switch msg.(type) {
+	case *consumer.DelayMessage:
+		return "delay"
+	case *consumer.LossMessage:
+		return "loss"
+	case *consumer.BandwidthMessage:
+		return "bandwidth"
+	case *consumer.UtilizationMessage:
+		return "utilization"
+	case *consumer.PassthroughMessage:
+		return "passthrough"
+	default:
+		return "unknown"
+	}
+}
+
+// observeDuration records the processing time of the current message, before it is handed over or dropped
+func (processor *DefaultProcessor) observeDuration(msgType string) {
+	if !processor.processingStart.IsZero() {
+		metrics.ProcessorDuration.WithLabelValues(msgType).Observe(time.Since(processor.processingStart).Seconds())
+	}
+}
+
+// dropMessage counts and stores a message which can not be processed
+func (processor *DefaultProcessor) dropMessage(msg consumer.Message, label string, reason error) {
+	msgType := messageType(msg)
+	processor.observeDuration(msgType)
+	metrics.ProcessorDropped.WithLabelValues(msgType, msg.GetTags().Source(), label).Inc()
+	processor.writeDeadLetter(msg, reason)
+}
+
+// writeDeadLetter stores a message which can not be processed together with the reason
+func (processor *DefaultProcessor) writeDeadLetter(msg consumer.Message, reason error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		processor.log.Errorln("Error encoding dead letter: ", err)
+		return
+	}
+	processor.deadLetter.Write(deadletter.NewLetter(deadletter.StageProcessor, reason, payload))
+}
+
+// forwardMessage hands the message to the publisher, it gives up if the processor is stopped while the publisher is not reading
+func (processor *DefaultProcessor) forwardMessage(msg consumer.Message) {
+	msgType := messageType(msg)
+	processor.observeDuration(msgType)
+	select {
+	case processor.processedMsgChan <- msg:
+		metrics.ProcessorProcessed.WithLabelValues(msgType, msg.GetTags().Source()).Inc()
+	case <-processor.quitChan:
+	}
 }
 
 func (processor *DefaultProcessor) processMessage(msg consumer.Message) {
+	processor.processingStart = time.Now()
 	switch msg := msg.(type) {
 	case *consumer.DelayMessage:
 		processor.processDelayMessage(msg)
@@ -189,8 +347,13 @@ func (processor *DefaultProcessor) processMessage(msg consumer.Message) {
 		processor.processLossMessage(msg)
 	case *consumer.BandwidthMessage:
 		processor.processBandwidthMessage(msg)
+	case *consumer.UtilizationMessage:
+		processor.processUtilizationMessage(msg)
+	case *consumer.PassthroughMessage:
+		processor.forwardMessage(msg)
 	default:
 		processor.log.Errorf("Skipping unknown message type: %v", msg)
+		processor.dropMessage(msg, metrics.ReasonUnknownType, fmt.Errorf("Unknown message type %T", msg))
 	}
 }
 
@@ -208,5 +371,5 @@ func (processor *DefaultProcessor) Start() {
 }
 
 func (processor *DefaultProcessor) Stop() {
-	processor.quitChan <- true
+	close(processor.quitChan)
 }
@@ -1,16 +1,23 @@
 package processor
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/deadletter"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+var defaultMapper, _ = naming.NewDefaultMapper(nil)
+
 func TestNewDefaultProcessor(t *testing.T) {
 	tests := []struct {
 		name string
@@ -26,7 +33,7 @@ func TestNewDefaultProcessor(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			defaultProcessor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			defaultProcessor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			assert.NotNil(t, defaultProcessor)
 		})
 	}
@@ -50,6 +57,14 @@ func TestDefaultProcessor_shortenInterfaceName(t *testing.T) {
 			want:    "Gi0-0-0-0",
 			wantErr: false,
 		},
+		{
+			name: "Test Shorten Interface Name with HundredGigE subinterface",
+			args: args{
+				name: "HundredGigE0/0/0/1.100",
+			},
+			want:    "Hu0-0-0-1",
+			wantErr: false,
+		},
 		{
 			name: "Test Shorten Interface Name with invalid name",
 			args: args{
@@ -66,7 +81,7 @@ func TestDefaultProcessor_shortenInterfaceName(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			got, err := processor.shortenInterfaceName(tt.args.name)
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -130,7 +145,7 @@ func TestDefaultProcessor_getDelayValues(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return("nodes.XR-1.config.Gi0-0-0-0.impairments.")
 			impairmentsPrefix := helper.GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0")
 			config.EXPECT().GetValue(impairmentsPrefix + "delay").Return(tt.delay)
@@ -206,12 +221,12 @@ func TestDefaultProcessor_setDelayValues(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "performance-measurement",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Node:          "0/RP0/CPU0",
-						Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"node":           "0/RP0/CPU0",
+						"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -225,7 +240,7 @@ func TestDefaultProcessor_setDelayValues(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			processor.setDelayValues(&msg, tt.delay, tt.jitter, tt.randomFactor)
 			assert.Equal(t, tt.want.Average, msg.Average)
 			assert.Equal(t, tt.want.Maximum, msg.Maximum)
@@ -290,12 +305,12 @@ func TestDefaultProcessor_processDelayMessage(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "performance-measurement",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: tt.fields.Interface,
-						Node:          "0/RP0/CPU0",
-						Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": tt.fields.Interface,
+						"node":           "0/RP0/CPU0",
+						"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -309,7 +324,14 @@ func TestDefaultProcessor_processDelayMessage(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			deadLetter := deadletter.NewMockWriter(ctrl)
+			if tt.want.Err {
+				deadLetter.EXPECT().Write(gomock.Any()).Do(func(letter *deadletter.Letter) {
+					assert.Equal(t, deadletter.StageProcessor, letter.Stage)
+					assert.Contains(t, letter.Payload, tt.fields.Interface)
+				})
+			}
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadLetter, defaultMapper)
 			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return(impairmentsPrefix).AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "delay").Return(tt.delay).AnyTimes()
@@ -367,7 +389,7 @@ func TestDefaultProcessor_getLossValue(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return("nodes.XR-1.config.Gi0-0-0-0.impairments.")
 			impairmentsPrefix := helper.GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0")
 			config.EXPECT().GetValue(impairmentsPrefix + "loss").Return(tt.loss)
@@ -416,11 +438,11 @@ func TestDefaultProcessor_setLossValue(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "isis",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -431,7 +453,7 @@ func TestDefaultProcessor_setLossValue(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			processor.setLossValue(&msg, tt.loss, tt.randomFactor)
 			assert.Equal(t, tt.want.Loss, msg.LossPercentage)
 		})
@@ -488,11 +510,11 @@ func TestDefaultProcessor_processLossMessage(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "isis",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: tt.fields.Interface,
-						Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": tt.fields.Interface,
+						"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -503,7 +525,14 @@ func TestDefaultProcessor_processLossMessage(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			deadLetter := deadletter.NewMockWriter(ctrl)
+			if tt.want.Err {
+				deadLetter.EXPECT().Write(gomock.Any()).Do(func(letter *deadletter.Letter) {
+					assert.Equal(t, deadletter.StageProcessor, letter.Stage)
+					assert.Contains(t, letter.Payload, tt.fields.Interface)
+				})
+			}
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadLetter, defaultMapper)
 			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return(impairmentsPrefix).AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "loss").Return(tt.loss).AnyTimes()
@@ -560,7 +589,7 @@ func TestDefaultProcessor_getBandwidthValue(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return("nodes.XR-1.config.Gi0-0-0-0.impairments.")
 			impairmentsPrefix := helper.GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0")
 			config.EXPECT().GetValue(impairmentsPrefix + "rate").Return(tt.rate)
@@ -633,11 +662,11 @@ func TestDefaultProcessor_processBandwidthMessage(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "isis",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: tt.fields.Interface,
-						Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": tt.fields.Interface,
+						"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -648,7 +677,14 @@ func TestDefaultProcessor_processBandwidthMessage(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			deadLetter := deadletter.NewMockWriter(ctrl)
+			if tt.want.Err {
+				deadLetter.EXPECT().Write(gomock.Any()).Do(func(letter *deadletter.Letter) {
+					assert.Equal(t, deadletter.StageProcessor, letter.Stage)
+					assert.Contains(t, letter.Payload, tt.fields.Interface)
+				})
+			}
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadLetter, defaultMapper)
 			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return(impairmentsPrefix).AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "rate").Return(tt.rate).AnyTimes()
@@ -673,6 +709,211 @@ func TestDefaultProcessor_processBandwidthMessage(t *testing.T) {
 	}
 }
 
+func TestDefaultProcessor_getBackgroundLoadValue(t *testing.T) {
+	tests := []struct {
+		name                string
+		backgroundLoad      string
+		backgroundLoadValue float64
+		wantErr             bool
+	}{
+		{
+			name:                "Test Get Background Load with valid value",
+			backgroundLoad:      "20",
+			backgroundLoadValue: 20,
+			wantErr:             false,
+		},
+		{
+			name:                "Test Get Background Load without value",
+			backgroundLoad:      "",
+			backgroundLoadValue: 0,
+			wantErr:             false,
+		},
+		{
+			name:           "Test Get Background Load with invalid value",
+			backgroundLoad: "invalid",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			config := config.NewMockConfig(ctrl)
+			helper := helpers.NewMockHelper(ctrl)
+			processor := NewDefaultProcessor(config, make(chan consumer.Message), make(chan consumer.Message), helper, deadletter.NewMultiWriter(), defaultMapper)
+			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
+			config.EXPECT().GetValue(impairmentsPrefix + "background-load").Return(tt.backgroundLoad)
+			backgroundLoad, err := processor.getBackgroundLoadValue(impairmentsPrefix)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.backgroundLoadValue, backgroundLoad)
+		})
+	}
+}
+
+func TestDefaultProcessor_impairCounter(t *testing.T) {
+	type args struct {
+		previous         uint64
+		current          uint64
+		previousImpaired uint64
+		capacity         float64
+		backgroundOctets float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want uint64
+	}{
+		{
+			name: "Test progression below capacity is kept",
+			args: args{previous: 1000, current: 3000, previousImpaired: 500, capacity: 10000},
+			want: 2500,
+		},
+		{
+			name: "Test progression above capacity is capped",
+			args: args{previous: 1000, current: 21000, previousImpaired: 500, capacity: 10000},
+			want: 10500,
+		},
+		{
+			name: "Test background load is added",
+			args: args{previous: 1000, current: 3000, previousImpaired: 500, capacity: 10000, backgroundOctets: 4000},
+			want: 6500,
+		},
+		{
+			name: "Test background load is capped",
+			args: args{previous: 1000, current: 3000, previousImpaired: 500, capacity: 10000, backgroundOctets: 9000},
+			want: 10500,
+		},
+		{
+			name: "Test counter reset continues the published counter",
+			args: args{previous: 5000, current: 2000, previousImpaired: 8000, capacity: 10000},
+			want: 10000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			processor := NewDefaultProcessor(config.NewMockConfig(ctrl), make(chan consumer.Message), make(chan consumer.Message), helpers.NewMockHelper(ctrl), deadletter.NewMultiWriter(), defaultMapper)
+			assert.Equal(t, tt.want, processor.impairCounter(tt.args.previous, tt.args.current, tt.args.previousImpaired, tt.args.capacity, tt.args.backgroundOctets))
+		})
+	}
+}
+
+func TestDefaultProcessor_processUtilizationMessage(t *testing.T) {
+	type sample struct {
+		timestamp     int64
+		inOctets      uint64
+		outOctets     uint64
+		wantInOctets  uint64
+		wantOutOctets uint64
+	}
+	tests := []struct {
+		name           string
+		interfaceName  string
+		rate           string
+		backgroundLoad string
+		samples        []sample
+		wantErr        bool
+	}{
+		{
+			name:          "Test with invalid name",
+			interfaceName: "Loopback0",
+			samples:       []sample{{timestamp: 1704728433}},
+			wantErr:       true,
+		},
+		{
+			name:          "Test with invalid rate",
+			interfaceName: "GigabitEthernet0/0/0/0",
+			rate:          "invalid",
+			samples:       []sample{{timestamp: 1704728433}},
+			wantErr:       true,
+		},
+		{
+			name:           "Test with invalid background load",
+			interfaceName:  "GigabitEthernet0/0/0/0",
+			rate:           "1000",
+			backgroundLoad: "invalid",
+			samples:        []sample{{timestamp: 1704728433}},
+			wantErr:        true,
+		},
+		{
+			name:          "Test counters are capped at the rate",
+			interfaceName: "GigabitEthernet0/0/0/0",
+			rate:          "1000", // 125000 octets/s
+			samples: []sample{
+				{timestamp: 1704728433, inOctets: 1000000, outOctets: 2000000, wantInOctets: 1000000, wantOutOctets: 2000000},
+				{timestamp: 1704728443, inOctets: 1500000, outOctets: 5000000, wantInOctets: 1500000, wantOutOctets: 3250000},
+				{timestamp: 1704728453, inOctets: 100000, outOctets: 5500000, wantInOctets: 1600000, wantOutOctets: 3750000},
+			},
+		},
+		{
+			name:          "Test out of order sample restarts the progression",
+			interfaceName: "GigabitEthernet0/0/0/0",
+			rate:          "1000",
+			samples: []sample{
+				{timestamp: 1704728433, inOctets: 1000000, outOctets: 2000000, wantInOctets: 1000000, wantOutOctets: 2000000},
+				{timestamp: 1704728423, inOctets: 900000, outOctets: 1900000, wantInOctets: 900000, wantOutOctets: 1900000},
+			},
+		},
+		{
+			name:           "Test background load is added within 10% variation",
+			interfaceName:  "GigabitEthernet0/0/0/0",
+			rate:           "1000",
+			backgroundLoad: "50",
+			samples: []sample{
+				{timestamp: 1704728433, inOctets: 0, outOctets: 0, wantInOctets: 0, wantOutOctets: 0},
+				{timestamp: 1704728443, inOctets: 0, outOctets: 0, wantInOctets: 625000, wantOutOctets: 625000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			config := config.NewMockConfig(ctrl)
+			helper := helpers.NewMockHelper(ctrl)
+			deadLetter := deadletter.NewMockWriter(ctrl)
+			if tt.wantErr {
+				deadLetter.EXPECT().Write(gomock.Any()).Do(func(letter *deadletter.Letter) {
+					assert.Equal(t, deadletter.StageProcessor, letter.Stage)
+					assert.Contains(t, letter.Payload, tt.interfaceName)
+				})
+			}
+			processedMsgChan := make(chan consumer.Message, 1)
+			processor := NewDefaultProcessor(config, make(chan consumer.Message), processedMsgChan, helper, deadLetter, defaultMapper)
+			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
+			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return(impairmentsPrefix).AnyTimes()
+			config.EXPECT().GetValue(impairmentsPrefix + "rate").Return(tt.rate).AnyTimes()
+			config.EXPECT().GetValue(impairmentsPrefix + "background-load").Return(tt.backgroundLoad).AnyTimes()
+			for _, sample := range tt.samples {
+				msg := consumer.UtilizationMessage{
+					TelemetryMessage: consumer.TelemetryMessage{
+						Name:      "utilization",
+						Tags:      consumer.MessageTags{"name": tt.interfaceName, "source": "XR-1"},
+						Timestamp: sample.timestamp,
+					},
+					InOctets:  sample.inOctets,
+					OutOctets: sample.outOctets,
+				}
+				processor.processUtilizationMessage(&msg)
+				if tt.wantErr {
+					assert.Empty(t, processedMsgChan)
+					continue
+				}
+				got := (<-processedMsgChan).(*consumer.UtilizationMessage)
+				if tt.backgroundLoad != "" {
+					assert.InEpsilon(t, float64(sample.wantInOctets)+1, float64(got.InOctets)+1, 0.1)
+					assert.InEpsilon(t, float64(sample.wantOutOctets)+1, float64(got.OutOctets)+1, 0.1)
+				} else {
+					assert.Equal(t, sample.wantInOctets, got.InOctets)
+					assert.Equal(t, sample.wantOutOctets, got.OutOctets)
+				}
+			}
+		})
+	}
+}
+
 func TestDefaultProcessor_processMessage(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -685,12 +926,12 @@ func TestDefaultProcessor_processMessage(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "performance-measurement",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Node:          "0/RP0/CPU0",
-						Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"node":           "0/RP0/CPU0",
+						"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -707,11 +948,11 @@ func TestDefaultProcessor_processMessage(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "isis",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -725,11 +966,11 @@ func TestDefaultProcessor_processMessage(t *testing.T) {
 				TelemetryMessage: consumer.TelemetryMessage{
 					Name: "isis",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-						Source:        "XR-1",
-						Subscription:  "hawk-metrics",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
 					},
 					Timestamp: 1704728135,
 				},
@@ -737,6 +978,32 @@ func TestDefaultProcessor_processMessage(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Test with valid Utilization message",
+			msg: &consumer.UtilizationMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Name:      "utilization",
+					Tags:      map[string]string{"name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+					Fields:    map[string]interface{}{"in_octets": json.Number("47912820356"), "out_octets": json.Number("1864216230")},
+					Timestamp: 1704728433,
+				},
+				InOctets:  47912820356,
+				OutOctets: 1864216230,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test with passthrough message",
+			msg: &consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Name:      "errors",
+					Tags:      map[string]string{"name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+					Fields:    map[string]interface{}{"in_errors": json.Number("47912820356")},
+					Timestamp: 1704728433,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Test with invalid message",
 			msg: &consumer.TelemetryMessage{
@@ -752,13 +1019,14 @@ func TestDefaultProcessor_processMessage(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
 			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return(impairmentsPrefix).AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "delay").Return("").AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "jitter").Return("").AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "loss").Return("").AnyTimes()
 			config.EXPECT().GetValue(impairmentsPrefix + "rate").Return("").AnyTimes()
+			config.EXPECT().GetValue(impairmentsPrefix + "background-load").Return("").AnyTimes()
 			go processor.processMessage(tt.msg)
 			time.Sleep(time.Second * 1)
 			if tt.wantErr {
@@ -795,7 +1063,7 @@ func TestDefaultProcessor_Stop(t *testing.T) {
 			unprocessedMsgChan := make(chan consumer.Message)
 			processedMsgChan := make(chan consumer.Message)
 			helper := helpers.NewMockHelper(ctrl)
-			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper)
+			processor := NewDefaultProcessor(config, unprocessedMsgChan, processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
 			go processor.Start()
 			time.Sleep(time.Second * 1)
 			go processor.Stop()
@@ -803,3 +1071,82 @@ func TestDefaultProcessor_Stop(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultProcessor_processMessage_Metrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       consumer.Message
+		msgType   string
+		reason    string
+		forwarded bool
+	}{
+		{
+			name: "Test processed message is counted",
+			msg: &consumer.LossMessage{TelemetryMessage: consumer.TelemetryMessage{
+				Name: "isis",
+				Tags: consumer.MessageTags{"interface_name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+			}},
+			msgType:   "loss",
+			forwarded: true,
+		},
+		{
+			name: "Test interface name mismatch is counted",
+			msg: &consumer.DelayMessage{TelemetryMessage: consumer.TelemetryMessage{
+				Name: "performance-measurement",
+				Tags: consumer.MessageTags{"interface_name": "Loopback0", "source": "XR-1"},
+			}},
+			msgType: "delay",
+			reason:  metrics.ReasonInterfaceNameMismatch,
+		},
+		{
+			name: "Test invalid impairments are counted",
+			msg: &consumer.BandwidthMessage{TelemetryMessage: consumer.TelemetryMessage{
+				Name: "isis",
+				Tags: consumer.MessageTags{"interface_name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+			}},
+			msgType: "bandwidth",
+			reason:  metrics.ReasonInvalidImpairments,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			config := config.NewMockConfig(ctrl)
+			helper := helpers.NewMockHelper(ctrl)
+			processedMsgChan := make(chan consumer.Message, 1)
+			processor := NewDefaultProcessor(config, make(chan consumer.Message), processedMsgChan, helper, deadletter.NewMultiWriter(), defaultMapper)
+			impairmentsPrefix := "nodes.XR-1.config.Gi0-0-0-0.impairments."
+			helper.EXPECT().GetDefaultImpairmentsPrefix("XR-1", "Gi0-0-0-0").Return(impairmentsPrefix).AnyTimes()
+			config.EXPECT().GetValue(impairmentsPrefix + "loss").Return("").AnyTimes()
+			config.EXPECT().GetValue(impairmentsPrefix + "rate").Return("fast").AnyTimes()
+
+			processed := testutil.ToFloat64(metrics.ProcessorProcessed.WithLabelValues(tt.msgType, "XR-1"))
+			dropped := testutil.ToFloat64(metrics.ProcessorDropped.WithLabelValues(tt.msgType, "XR-1", tt.reason))
+			processor.processMessage(tt.msg)
+			if tt.forwarded {
+				assert.Len(t, processedMsgChan, 1)
+				assert.Equal(t, processed+1, testutil.ToFloat64(metrics.ProcessorProcessed.WithLabelValues(tt.msgType, "XR-1")))
+			} else {
+				assert.Empty(t, processedMsgChan)
+				assert.Equal(t, dropped+1, testutil.ToFloat64(metrics.ProcessorDropped.WithLabelValues(tt.msgType, "XR-1", tt.reason)))
+			}
+		})
+	}
+}
+
+func TestDefaultProcessor_forwardMessage_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := NewDefaultProcessor(config.NewMockConfig(ctrl), make(chan consumer.Message), make(chan consumer.Message), helpers.NewMockHelper(ctrl), deadletter.NewMultiWriter(), defaultMapper)
+	msg := &consumer.LossMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "isis", Tags: consumer.MessageTags{"source": "XR-1"}}}
+	processed := testutil.ToFloat64(metrics.ProcessorProcessed.WithLabelValues("loss", "XR-1"))
+	done := make(chan struct{})
+	go func() {
+		// the publisher does not read, so the send blocks until the processor is stopped
+		processor.forwardMessage(msg)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	processor.Stop()
+	<-done
+	assert.Equal(t, processed, testutil.ToFloat64(metrics.ProcessorProcessed.WithLabelValues("loss", "XR-1")))
+}
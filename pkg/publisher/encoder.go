@@ -0,0 +1,32 @@
+package publisher
+
+import (
+	"fmt"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+)
+
+const (
+	OutputFormatLineProtocol = "line-protocol"
+	OutputFormatJSON         = "json"
+	OutputFormatProtobuf     = "protobuf"
+)
+
+// Encoder converts a processed message into the payload of a published message
+type Encoder interface {
+	Encode(msg consumer.Message) ([]byte, error)
+}
+
+// NewEncoder returns the encoder of the output format
+func NewEncoder(format string) (Encoder, error) {
+	switch format {
+	case OutputFormatLineProtocol:
+		return &LineProtocolEncoder{}, nil
+	case OutputFormatJSON:
+		return &JSONEncoder{}, nil
+	case OutputFormatProtobuf:
+		return &ProtobufEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown output format %q, use %s, %s or %s", format, OutputFormatLineProtocol, OutputFormatJSON, OutputFormatProtobuf)
+	}
+}
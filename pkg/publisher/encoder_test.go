@@ -0,0 +1,249 @@
+package publisher
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/publisher/timeseriespb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewEncoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    Encoder
+		wantErr bool
+	}{
+		{
+			name:   "Test line protocol encoder",
+			format: OutputFormatLineProtocol,
+			want:   &LineProtocolEncoder{},
+		},
+		{
+			name:   "Test JSON encoder",
+			format: OutputFormatJSON,
+			want:   &JSONEncoder{},
+		},
+		{
+			name:   "Test protobuf encoder",
+			format: OutputFormatProtobuf,
+			want:   &ProtobufEncoder{},
+		},
+		{
+			name:    "Test unknown output format",
+			format:  "avro",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, err := NewEncoder(tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, encoder)
+		})
+	}
+}
+
+func TestLineProtocolEncoder_Encode(t *testing.T) {
+	newPassthroughMessage := func(fields map[string]interface{}) consumer.Message {
+		return &consumer.PassthroughMessage{
+			TelemetryMessage: consumer.TelemetryMessage{
+				Fields:    fields,
+				Name:      "isis",
+				Tags:      consumer.MessageTags{"source": "XR-1"},
+				Timestamp: 1704728296,
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		msgs []consumer.Message
+		want []string
+	}{
+		{
+			name: "Test encode gauge keeps the float type for integer and float values",
+			msgs: []consumer.Message{
+				newPassthroughMessage(map[string]interface{}{"interface_status_and_data/enabled/packet_loss_percentage": json.Number("2")}),
+				newPassthroughMessage(map[string]interface{}{"interface_status_and_data/enabled/packet_loss_percentage": json.Number("2.5")}),
+			},
+			want: []string{
+				"isis,source=XR-1 interface_status_and_data/enabled/packet_loss_percentage=2 1704728296000000000\n",
+				"isis,source=XR-1 interface_status_and_data/enabled/packet_loss_percentage=2.5 1704728296000000000\n",
+			},
+		},
+		{
+			name: "Test encode counter as integer",
+			msgs: []consumer.Message{
+				newPassthroughMessage(map[string]interface{}{"interface_status_and_data/enabled/adjacency_count": json.Number("2")}),
+			},
+			want: []string{
+				"isis,source=XR-1 interface_status_and_data/enabled/adjacency_count=2i 1704728296000000000\n",
+			},
+		},
+		{
+			name: "Test encode counter with fraction as integer",
+			msgs: []consumer.Message{
+				newPassthroughMessage(map[string]interface{}{"in_octets": json.Number("2.5")}),
+			},
+			want: []string{
+				"isis,source=XR-1 in_octets=2i 1704728296000000000\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := &LineProtocolEncoder{}
+			var got []string
+			for _, msg := range tt.msgs {
+				byteMsg, err := encoder.Encode(msg)
+				assert.NoError(t, err)
+				got = append(got, string(byteMsg))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSONEncoder_Encode(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  consumer.Message
+		want string
+	}{
+		{
+			name: "Test encode passthrough message keeps integers",
+			msg: &consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"out_octets": json.Number("1864216230"),
+						"in_octets":  json.Number("47912820356"),
+					},
+					Name:      "utilization",
+					Tags:      consumer.MessageTags{"source": "XR-1", "name": "GigabitEthernet0/0/0/0"},
+					Timestamp: 1704728433,
+				},
+			},
+			want: `{"fields":{"in_octets":47912820356,"out_octets":1864216230},"name":"utilization","tags":{"name":"GigabitEthernet0/0/0/0","source":"XR-1"},"timestamp":1704728433}`,
+		},
+		{
+			name: "Test encode loss message with impaired field",
+			msg: &consumer.LossMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"interface_status_and_data/enabled/packet_loss_percentage": json.Number("0"),
+					},
+					Name:      "isis",
+					Tags:      consumer.MessageTags{"interface_name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+					Timestamp: 1704728296,
+				},
+				LossPercentage: 2.5,
+			},
+			want: `{"fields":{"interface_status_and_data/enabled/packet_loss_percentage":2.5},"name":"isis","tags":{"interface_name":"GigabitEthernet0/0/0/0","source":"XR-1"},"timestamp":1704728296}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := &JSONEncoder{}
+			byteMsg, err := encoder.Encode(tt.msg)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(byteMsg))
+		})
+	}
+}
+
+func TestProtobufEncoder_Encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     consumer.Message
+		want    *timeseriespb.WriteRequest
+		wantErr bool
+	}{
+		{
+			name: "Test encode loss message skips string fields and empty tags",
+			msg: &consumer.LossMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"interface_status_and_data/enabled/packet_loss_percentage": json.Number("0"),
+						"interface_status_and_data/enabled/interface_state":        "up",
+					},
+					Name:      "isis",
+					Tags:      consumer.MessageTags{"source": "XR-1", "node": ""},
+					Timestamp: 1704728296,
+				},
+				LossPercentage: 2.5,
+			},
+			want: &timeseriespb.WriteRequest{Timeseries: []*timeseriespb.TimeSeries{
+				{
+					Labels: []*timeseriespb.Label{
+						{Name: "__name__", Value: "isis"},
+						{Name: "__field__", Value: "interface_status_and_data/enabled/packet_loss_percentage"},
+						{Name: "source", Value: "XR-1"},
+					},
+					Samples: []*timeseriespb.Sample{{Value: 2.5, Timestamp: 1704728296000}},
+				},
+			}},
+		},
+		{
+			name: "Test encode passthrough message with bool and float fields",
+			msg: &consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields:    map[string]interface{}{"enabled": true, "ratio": json.Number("0.5")},
+					Name:      "interfaces",
+					Tags:      consumer.MessageTags{"source": "XR-1"},
+					Timestamp: 1704728433,
+				},
+			},
+			want: &timeseriespb.WriteRequest{Timeseries: []*timeseriespb.TimeSeries{
+				{
+					Labels:  []*timeseriespb.Label{{Name: "__name__", Value: "interfaces"}, {Name: "__field__", Value: "enabled"}, {Name: "source", Value: "XR-1"}},
+					Samples: []*timeseriespb.Sample{{Value: 1, Timestamp: 1704728433000}},
+				},
+				{
+					Labels:  []*timeseriespb.Label{{Name: "__name__", Value: "interfaces"}, {Name: "__field__", Value: "ratio"}, {Name: "source", Value: "XR-1"}},
+					Samples: []*timeseriespb.Sample{{Value: 0.5, Timestamp: 1704728433000}},
+				},
+			}},
+		},
+		{
+			name: "Test encode message with nested field",
+			msg: &consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{"nested": map[string]interface{}{"value": 1}},
+					Name:   "interfaces",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test encode message with only string fields",
+			msg: &consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{"state": "up"},
+					Name:   "interfaces",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := &ProtobufEncoder{}
+			byteMsg, err := encoder.Encode(tt.msg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			request := &timeseriespb.WriteRequest{}
+			assert.NoError(t, proto.Unmarshal(byteMsg, request))
+			assert.True(t, proto.Equal(tt.want, request), "got %v", request)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+type fanoutSink struct {
+	name  string
+	queue chan consumer.Message
+}
+
+// FanoutPublisher copies the processed messages to the queues of several sinks, each sink is a publisher reading its own queue.
+// A sink which is slow or disconnected only fills its own queue, further messages are dropped for this sink only.
+type FanoutPublisher struct {
+	log              *logrus.Entry
+	processedMsgChan chan consumer.Message
+	sinks            []fanoutSink
+	ctx              context.Context
+	cancel           context.CancelFunc
+	running          runGuard
+}
+
+func NewFanoutPublisher(msgChan chan consumer.Message) *FanoutPublisher {
+	return &FanoutPublisher{
+		log:              logging.DefaultLogger.WithField("subsystem", subsystem),
+		processedMsgChan: msgChan,
+	}
+}
+
+// AddSink returns the queue of a new sink which has to be passed to the publisher of the sink, it has to be called before Start
+func (publisher *FanoutPublisher) AddSink(name string, queueSize int) chan consumer.Message {
+	queue := make(chan consumer.Message, queueSize)
+	publisher.sinks = append(publisher.sinks, fanoutSink{name: name, queue: queue})
+	return queue
+}
+
+func (publisher *FanoutPublisher) Init() error {
+	publisher.running.arm()
+	publisher.ctx, publisher.cancel = context.WithCancel(context.Background())
+	return nil
+}
+
+func (publisher *FanoutPublisher) distributeMessage(msg consumer.Message) {
+	for _, sink := range publisher.sinks {
+		select {
+		case sink.queue <- msg:
+		default:
+			publisher.log.Debugf("Queue of sink %s is full, dropping message %s", sink.name, msg.GetName())
+			metrics.PublisherQueueDropped.WithLabelValues(sink.name, msg.GetName(), msg.GetTags().Source()).Inc()
+		}
+	}
+}
+
+// Start distributes messages until Stop is called, it never fails since the sinks handle their connections themselves
+func (publisher *FanoutPublisher) Start() error {
+	if !publisher.running.begin() {
+		return nil
+	}
+	defer publisher.running.end()
+	publisher.log.Infof("Starting fan-out to %d sinks", len(publisher.sinks))
+	for {
+		select {
+		case msg := <-publisher.processedMsgChan:
+			publisher.distributeMessage(msg)
+		case <-publisher.ctx.Done():
+			publisher.log.Infoln("Stopping fan-out")
+			return nil
+		}
+	}
+}
+
+// Stop ends distributing, messages which are still waiting are copied to the queues of the sinks, which write them on their Stop
+func (publisher *FanoutPublisher) Stop() error {
+	if publisher.cancel != nil {
+		publisher.cancel()
+	}
+	publisher.running.wait()
+	drained, _ := drainQueue(publisher.processedMsgChan, func(msg consumer.Message) error {
+		publisher.distributeMessage(msg)
+		return nil
+	})
+	if drained > 0 {
+		publisher.log.Debugf("Distributed %d waiting messages on stop", drained)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package publisher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanoutPublisher_Start(t *testing.T) {
+	tests := []struct {
+		name        string
+		messages    int
+		wantFast    int
+		wantSlow    int
+		wantDropped float64
+	}{
+		{
+			name:     "Test all sinks receive the messages",
+			messages: 2,
+			wantFast: 2,
+			wantSlow: 2,
+		},
+		{
+			name:        "Test full queue drops messages only for its sink",
+			messages:    5,
+			wantFast:    5,
+			wantSlow:    2,
+			wantDropped: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgChan := make(chan consumer.Message)
+			publisher := NewFanoutPublisher(msgChan)
+			fastQueue := publisher.AddSink("fast", 10)
+			slowQueue := publisher.AddSink("slow", 2)
+			assert.NoError(t, publisher.Init())
+			done := make(chan error)
+			go func() {
+				done <- publisher.Start()
+			}()
+			msg := &consumer.PassthroughMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "fanout", Tags: consumer.MessageTags{"source": "XR-1"}}}
+			dropped := testutil.ToFloat64(metrics.PublisherQueueDropped.WithLabelValues("slow", "fanout", "XR-1"))
+			for i := 0; i < tt.messages; i++ {
+				msgChan <- msg
+			}
+			assert.Eventually(t, func() bool {
+				return len(fastQueue) == tt.wantFast
+			}, time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.wantSlow, len(slowQueue))
+			assert.Equal(t, dropped+tt.wantDropped, testutil.ToFloat64(metrics.PublisherQueueDropped.WithLabelValues("slow", "fanout", "XR-1")))
+			assert.NoError(t, publisher.Stop())
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+func TestFanoutPublisher_Stop(t *testing.T) {
+	tests := []struct {
+		name    string
+		waiting int
+	}{
+		{
+			name:    "Test Stop without Init",
+			waiting: 0,
+		},
+		{
+			name:    "Test Stop copies waiting messages to the sinks",
+			waiting: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgChan := make(chan consumer.Message, tt.waiting)
+			publisher := NewFanoutPublisher(msgChan)
+			firstQueue := publisher.AddSink("first", 10)
+			secondQueue := publisher.AddSink("second", 10)
+			for i := 0; i < tt.waiting; i++ {
+				msgChan <- &consumer.PassthroughMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "fanout"}}
+			}
+			assert.NoError(t, publisher.Stop())
+			assert.Equal(t, tt.waiting, len(firstQueue))
+			assert.Equal(t, tt.waiting, len(secondQueue))
+			assert.Empty(t, msgChan)
+		})
+	}
+}
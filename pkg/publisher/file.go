@@ -0,0 +1,118 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"sync"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// FilePublisher appends the processed messages as Telegraf JSON lines to a local file, e.g. to archive an experiment
+type FilePublisher struct {
+	encoder          JSONEncoder
+	log              *logrus.Entry
+	path             string
+	processedMsgChan chan consumer.Message
+	ctx              context.Context
+	cancel           context.CancelFunc
+	running          runGuard
+	mutex            sync.Mutex
+	file             *os.File
+	writer           *bufio.Writer
+}
+
+func NewFilePublisher(path string, msgChan chan consumer.Message) *FilePublisher {
+	return &FilePublisher{
+		log:              logging.DefaultLogger.WithField("subsystem", subsystem),
+		path:             path,
+		processedMsgChan: msgChan,
+	}
+}
+
+// Init opens the file for appending, it is called again to reopen the file after Start returned an error
+func (publisher *FilePublisher) Init() error {
+	publisher.running.arm()
+	publisher.ctx, publisher.cancel = context.WithCancel(context.Background())
+	file, err := os.OpenFile(publisher.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	publisher.file = file
+	publisher.writer = bufio.NewWriter(file)
+	return nil
+}
+
+// writeMessage returns an error if the file can not be written, messages which can not be encoded are dropped
+func (publisher *FilePublisher) writeMessage(msg consumer.Message) error {
+	labels := messageLabels{measurement: msg.GetName(), node: msg.GetTags().Source()}
+	encodedMsg, err := publisher.encoder.Encode(msg)
+	if err != nil {
+		publisher.log.Errorln("Error encoding message: ", err)
+		metrics.PublisherFailed.WithLabelValues(labels.measurement, labels.node, metrics.ReasonEncode).Inc()
+		return nil
+	}
+	if _, err := publisher.writer.Write(append(encodedMsg, '\n')); err != nil {
+		metrics.PublisherFailed.WithLabelValues(labels.measurement, labels.node, metrics.ReasonProduce).Inc()
+		return err
+	}
+	metrics.PublisherPublished.WithLabelValues(labels.measurement, labels.node).Inc()
+	metrics.PublisherMessageSize.WithLabelValues(labels.measurement).Observe(float64(len(encodedMsg)))
+	// the buffer is only flushed once no further message is waiting, so bursts are written at once
+	if len(publisher.processedMsgChan) == 0 {
+		return publisher.writer.Flush()
+	}
+	return nil
+}
+
+// Start writes messages until Stop is called (returns nil) or writing the file fails (returns the error)
+func (publisher *FilePublisher) Start() error {
+	if !publisher.running.begin() {
+		return nil
+	}
+	defer publisher.running.end()
+	publisher.log.Infoln("Starting writing messages to file", publisher.path)
+	for {
+		select {
+		case msg := <-publisher.processedMsgChan:
+			if err := publisher.writeMessage(msg); err != nil {
+				publisher.log.Errorln("Error writing message to file: ", err)
+				return err
+			}
+		case <-publisher.ctx.Done():
+			publisher.log.Infoln("Stopping writing messages to file", publisher.path)
+			return nil
+		}
+	}
+}
+
+// Stop ends writing, writes the messages still waiting in the queue and closes the file.
+// It is safe to call if Init failed or Start already returned
+func (publisher *FilePublisher) Stop() error {
+	if publisher.cancel != nil {
+		publisher.cancel()
+	}
+	publisher.running.wait()
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	if publisher.file == nil {
+		return nil
+	}
+	// if the file can not be written, the remaining messages stay queued for the next Start
+	if _, err := drainQueue(publisher.processedMsgChan, publisher.writeMessage); err != nil {
+		publisher.log.Errorf("Unable to write the waiting messages on stop, %d messages are still queued: %v\n", len(publisher.processedMsgChan), err)
+	}
+	flushErr := publisher.writer.Flush()
+	closeErr := publisher.file.Close()
+	publisher.file = nil
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
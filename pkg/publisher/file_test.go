@@ -0,0 +1,131 @@
+package publisher
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilePublisher_Init(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{
+			name: "Test init creates the file",
+			path: "archive.jsonl",
+		},
+		{
+			name:    "Test init with missing directory",
+			path:    filepath.Join("missing", "archive.jsonl"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.path)
+			publisher := NewFilePublisher(path, make(chan consumer.Message))
+			err := publisher.Init()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.FileExists(t, path)
+			}
+			assert.NoError(t, publisher.Stop())
+		})
+	}
+}
+
+func TestFilePublisher_Start(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		msgs     []consumer.Message
+		want     string
+	}{
+		{
+			name: "Test messages are written as JSON lines",
+			msgs: []consumer.Message{
+				&consumer.LossMessage{
+					TelemetryMessage: consumer.TelemetryMessage{
+						Fields:    map[string]interface{}{"interface_status_and_data/enabled/packet_loss_percentage": json.Number("0")},
+						Name:      "isis",
+						Tags:      consumer.MessageTags{"source": "XR-1"},
+						Timestamp: 1704728296,
+					},
+					LossPercentage: 2.5,
+				},
+				&consumer.PassthroughMessage{
+					TelemetryMessage: consumer.TelemetryMessage{
+						Fields:    map[string]interface{}{"in_errors": json.Number("3")},
+						Name:      "errors",
+						Tags:      consumer.MessageTags{"source": "XR-2"},
+						Timestamp: 1704728297,
+					},
+				},
+			},
+			want: `{"fields":{"interface_status_and_data/enabled/packet_loss_percentage":2.5},"name":"isis","tags":{"source":"XR-1"},"timestamp":1704728296}` + "\n" +
+				`{"fields":{"in_errors":3},"name":"errors","tags":{"source":"XR-2"},"timestamp":1704728297}` + "\n",
+		},
+		{
+			name:     "Test messages are appended to an existing file",
+			existing: "{}\n",
+			msgs: []consumer.Message{
+				&consumer.PassthroughMessage{TelemetryMessage: consumer.TelemetryMessage{Fields: map[string]interface{}{"in_errors": json.Number("3")}, Name: "errors"}},
+			},
+			want: "{}\n" + `{"fields":{"in_errors":3},"name":"errors"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "archive.jsonl")
+			if tt.existing != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(tt.existing), 0644))
+			}
+			msgChan := make(chan consumer.Message)
+			publisher := NewFilePublisher(path, msgChan)
+			assert.NoError(t, publisher.Init())
+			done := make(chan error)
+			go func() {
+				done <- publisher.Start()
+			}()
+			for _, msg := range tt.msgs {
+				msgChan <- msg
+			}
+			assert.Eventually(t, func() bool {
+				content, err := os.ReadFile(path)
+				return err == nil && string(content) == tt.want
+			}, time.Second, 10*time.Millisecond)
+			assert.NoError(t, publisher.Stop())
+			assert.NoError(t, <-done)
+		})
+	}
+}
+
+func TestFilePublisher_Stop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	msgChan := make(chan consumer.Message, 2)
+	publisher := NewFilePublisher(path, msgChan)
+	assert.NoError(t, publisher.Init())
+	done := make(chan error)
+	go func() {
+		done <- publisher.Start()
+	}()
+	publisher.cancel()
+	assert.NoError(t, <-done)
+	// messages which are still queued when the publisher stops are written before the file is closed
+	msgChan <- &consumer.PassthroughMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "first"}}
+	msgChan <- &consumer.PassthroughMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "second"}}
+	assert.NoError(t, publisher.Stop())
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"first"}`+"\n"+`{"name":"second"}`+"\n", string(content))
+	assert.Empty(t, msgChan)
+}
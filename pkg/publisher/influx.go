@@ -0,0 +1,239 @@
+package publisher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	InfluxTokenEnv       = "CLAB_TELEMETRY_LINKER_INFLUX_TOKEN"
+	influxRequestTimeout = 10 * time.Second
+	// maxInfluxErrorBody limits how much of an error response is logged
+	maxInfluxErrorBody = 512
+)
+
+// InfluxConfig configures the InfluxDB v2 write API, lines are written once BatchSize lines are buffered or every FlushInterval
+type InfluxConfig struct {
+	URL           string
+	Org           string
+	Bucket        string
+	Token         string
+	BatchSize     int
+	FlushInterval time.Duration
+	Gzip          bool
+}
+
+// errInfluxUnavailable marks write errors after which the publisher reconnects, the batch is kept and written again
+var errInfluxUnavailable = errors.New("InfluxDB unavailable")
+
+type InfluxPublisher struct {
+	// the InfluxDB write API only accepts line protocol
+	encoder          LineProtocolEncoder
+	log              *logrus.Entry
+	config           InfluxConfig
+	client           *http.Client
+	processedMsgChan chan consumer.Message
+	ctx              context.Context
+	cancel           context.CancelFunc
+	running          runGuard
+	// batch is kept across reconnects, so lines which could not be written are retried
+	batch       bytes.Buffer
+	batchLabels []messageLabels
+}
+
+func NewInfluxPublisher(config InfluxConfig, msgChan chan consumer.Message) *InfluxPublisher {
+	return &InfluxPublisher{
+		log:              logging.DefaultLogger.WithField("subsystem", subsystem),
+		config:           config,
+		client:           &http.Client{Timeout: influxRequestTimeout},
+		processedMsgChan: msgChan,
+	}
+}
+
+func (publisher *InfluxPublisher) getWriteURL() string {
+	query := url.Values{}
+	query.Set("org", publisher.config.Org)
+	query.Set("bucket", publisher.config.Bucket)
+	query.Set("precision", "ns")
+	return strings.TrimSuffix(publisher.config.URL, "/") + "/api/v2/write?" + query.Encode()
+}
+
+// Init checks that InfluxDB is reachable, it is called again to reconnect after Start returned an error
+func (publisher *InfluxPublisher) Init() error {
+	publisher.running.arm()
+	publisher.ctx, publisher.cancel = context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(publisher.ctx, http.MethodGet, strings.TrimSuffix(publisher.config.URL, "/")+"/health", nil)
+	if err != nil {
+		return err
+	}
+	response, err := publisher.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInfluxUnavailable, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: health check returned %s", errInfluxUnavailable, response.Status)
+	}
+	return nil
+}
+
+func (publisher *InfluxPublisher) createBody() (io.Reader, error) {
+	if !publisher.config.Gzip {
+		return bytes.NewReader(publisher.batch.Bytes()), nil
+	}
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	if _, err := writer.Write(publisher.batch.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return &body, nil
+}
+
+// write sends the batch, errors which are not caused by the connection or the server are returned as is
+func (publisher *InfluxPublisher) write(ctx context.Context) error {
+	body, err := publisher.createBody()
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, publisher.getWriteURL(), body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if publisher.config.Token != "" {
+		request.Header.Set("Authorization", "Token "+publisher.config.Token)
+	}
+	if publisher.config.Gzip {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+	response, err := publisher.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInfluxUnavailable, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNoContent || response.StatusCode == http.StatusOK {
+		return nil
+	}
+	message, _ := io.ReadAll(io.LimitReader(response.Body, maxInfluxErrorBody))
+	err = fmt.Errorf("Write returned %s: %s", response.Status, strings.TrimSpace(string(message)))
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %v", errInfluxUnavailable, err)
+	}
+	return err
+}
+
+func (publisher *InfluxPublisher) resetBatch() {
+	publisher.batch.Reset()
+	publisher.batchLabels = publisher.batchLabels[:0]
+}
+
+// flush writes the batch, it returns an error only if InfluxDB is unavailable, rejected batches are dropped
+func (publisher *InfluxPublisher) flush(ctx context.Context) error {
+	if len(publisher.batchLabels) == 0 {
+		return nil
+	}
+	err := publisher.write(ctx)
+	if errors.Is(err, errInfluxUnavailable) {
+		return err
+	}
+	for _, labels := range publisher.batchLabels {
+		if err != nil {
+			metrics.PublisherFailed.WithLabelValues(labels.measurement, labels.node, metrics.ReasonProduce).Inc()
+		} else {
+			metrics.PublisherPublished.WithLabelValues(labels.measurement, labels.node).Inc()
+		}
+	}
+	if err != nil {
+		publisher.log.Errorf("Dropping %d lines rejected by InfluxDB: %v\n", len(publisher.batchLabels), err)
+	} else {
+		publisher.log.Debugf("Successfully wrote %d lines to bucket %s\n", len(publisher.batchLabels), publisher.config.Bucket)
+	}
+	publisher.resetBatch()
+	return nil
+}
+
+// addMessage appends the encoded message to the batch and returns true if the batch is full
+func (publisher *InfluxPublisher) addMessage(msg consumer.Message) bool {
+	labels := messageLabels{measurement: msg.GetName(), node: msg.GetTags().Source()}
+	encodedMsg, err := publisher.encoder.Encode(msg)
+	if err != nil {
+		publisher.log.Errorln("Error encoding message: ", err)
+		metrics.PublisherFailed.WithLabelValues(labels.measurement, labels.node, metrics.ReasonEncode).Inc()
+		return false
+	}
+	metrics.PublisherMessageSize.WithLabelValues(labels.measurement).Observe(float64(len(encodedMsg)))
+	publisher.batch.Write(encodedMsg)
+	publisher.batchLabels = append(publisher.batchLabels, labels)
+	return len(publisher.batchLabels) >= publisher.config.BatchSize
+}
+
+// finalFlush writes the remaining lines and the messages still waiting in the queue on Stop, the context of the publisher is already canceled
+func (publisher *InfluxPublisher) finalFlush() {
+	ctx, cancel := context.WithTimeout(context.Background(), influxRequestTimeout)
+	defer cancel()
+	drainQueue(publisher.processedMsgChan, func(msg consumer.Message) error {
+		publisher.addMessage(msg)
+		return nil
+	})
+	if err := publisher.flush(ctx); err != nil {
+		publisher.log.Errorf("Unable to write %d lines on shutdown: %v\n", len(publisher.batchLabels), err)
+	}
+}
+
+// Start writes batches until Stop is called (returns nil) or InfluxDB is unavailable (returns the error)
+func (publisher *InfluxPublisher) Start() error {
+	if !publisher.running.begin() {
+		return nil
+	}
+	defer publisher.running.end()
+	publisher.log.Infof("Starting writing messages to %s, org %s and bucket %s\n", publisher.config.URL, publisher.config.Org, publisher.config.Bucket)
+	if err := publisher.flush(publisher.ctx); err != nil && publisher.ctx.Err() == nil {
+		return err
+	}
+	ticker := time.NewTicker(publisher.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-publisher.processedMsgChan:
+			if !publisher.addMessage(msg) {
+				continue
+			}
+			if err := publisher.flush(publisher.ctx); err != nil && publisher.ctx.Err() == nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := publisher.flush(publisher.ctx); err != nil && publisher.ctx.Err() == nil {
+				return err
+			}
+		case <-publisher.ctx.Done():
+			publisher.finalFlush()
+			publisher.log.Infof("Stopping writing messages to %s\n", publisher.config.URL)
+			return nil
+		}
+	}
+}
+
+// Stop ends writing, it is safe to call if Init failed or Start already returned
+func (publisher *InfluxPublisher) Stop() error {
+	if publisher.cancel != nil {
+		publisher.cancel()
+	}
+	publisher.running.wait()
+	return nil
+}
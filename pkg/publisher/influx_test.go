@@ -0,0 +1,221 @@
+package publisher
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/stretchr/testify/assert"
+)
+
+// influxStandIn records the write requests and answers with the configured status
+type influxStandIn struct {
+	mutex        sync.Mutex
+	healthStatus int
+	writeStatus  int
+	requests     []*http.Request
+	bodies       []string
+}
+
+func newInfluxStandIn(t *testing.T, healthStatus, writeStatus int) (*influxStandIn, *httptest.Server) {
+	standIn := &influxStandIn{healthStatus: healthStatus, writeStatus: writeStatus}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/health" {
+			writer.WriteHeader(standIn.healthStatus)
+			return
+		}
+		var body io.Reader = request.Body
+		if request.Header.Get("Content-Encoding") == "gzip" {
+			reader, err := gzip.NewReader(request.Body)
+			assert.NoError(t, err)
+			body = reader
+		}
+		data, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		standIn.mutex.Lock()
+		standIn.requests = append(standIn.requests, request)
+		standIn.bodies = append(standIn.bodies, string(data))
+		status := standIn.writeStatus
+		standIn.mutex.Unlock()
+		writer.WriteHeader(status)
+		if status != http.StatusNoContent {
+			_, _ = writer.Write([]byte(`{"code":"invalid","message":"unable to parse"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func (standIn *influxStandIn) getBodies() []string {
+	standIn.mutex.Lock()
+	defer standIn.mutex.Unlock()
+	return append([]string{}, standIn.bodies...)
+}
+
+func newBandwidthMessage(bandwidth float64) *consumer.BandwidthMessage {
+	return &consumer.BandwidthMessage{
+		TelemetryMessage: consumer.TelemetryMessage{
+			Name:      "isis",
+			Tags:      consumer.MessageTags{"interface_name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+			Timestamp: 1704728135,
+		},
+		Bandwidth: bandwidth,
+	}
+}
+
+func TestInfluxPublisher_Init(t *testing.T) {
+	tests := []struct {
+		name         string
+		healthStatus int
+		url          string
+		wantErr      bool
+	}{
+		{
+			name:         "Test init with healthy InfluxDB",
+			healthStatus: http.StatusOK,
+		},
+		{
+			name:         "Test init with unhealthy InfluxDB",
+			healthStatus: http.StatusServiceUnavailable,
+			wantErr:      true,
+		},
+		{
+			name:    "Test init with unreachable InfluxDB",
+			url:     "http://127.0.0.1:1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, server := newInfluxStandIn(t, tt.healthStatus, http.StatusNoContent)
+			url := server.URL
+			if tt.url != "" {
+				url = tt.url
+			}
+			publisher := NewInfluxPublisher(InfluxConfig{URL: url, BatchSize: 1, FlushInterval: time.Second}, make(chan consumer.Message))
+			err := publisher.Init()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInfluxUnavailable)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInfluxPublisher_flush(t *testing.T) {
+	tests := []struct {
+		name        string
+		gzip        bool
+		writeStatus int
+		wantErr     bool
+		wantKept    bool
+	}{
+		{
+			name:        "Test flush batch",
+			writeStatus: http.StatusNoContent,
+		},
+		{
+			name:        "Test flush gzip compressed batch",
+			gzip:        true,
+			writeStatus: http.StatusNoContent,
+		},
+		{
+			name:        "Test flush rejected batch is dropped",
+			writeStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Test flush with unavailable InfluxDB keeps the batch",
+			writeStatus: http.StatusServiceUnavailable,
+			wantErr:     true,
+			wantKept:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn, server := newInfluxStandIn(t, http.StatusOK, tt.writeStatus)
+			publisher := NewInfluxPublisher(InfluxConfig{URL: server.URL + "/", Org: "hawkv6", Bucket: "telemetry", Token: "secret", BatchSize: 2, FlushInterval: time.Second, Gzip: tt.gzip}, make(chan consumer.Message))
+			assert.False(t, publisher.addMessage(newBandwidthMessage(1000)))
+			assert.True(t, publisher.addMessage(newBandwidthMessage(2000)))
+			err := publisher.flush(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInfluxUnavailable)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantKept, len(publisher.batchLabels) == 2)
+
+			bodies := standIn.getBodies()
+			assert.Len(t, bodies, 1)
+			assert.Equal(t, "isis,interface_name=GigabitEthernet0/0/0/0,source=XR-1 interface_status_and_data/enabled/bandwidth=1000 1704728135000000000\n"+
+				"isis,interface_name=GigabitEthernet0/0/0/0,source=XR-1 interface_status_and_data/enabled/bandwidth=2000 1704728135000000000\n", bodies[0])
+			request := standIn.requests[0]
+			assert.Equal(t, "/api/v2/write", request.URL.Path)
+			assert.Equal(t, "hawkv6", request.URL.Query().Get("org"))
+			assert.Equal(t, "telemetry", request.URL.Query().Get("bucket"))
+			assert.Equal(t, "ns", request.URL.Query().Get("precision"))
+			assert.Equal(t, "Token secret", request.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestInfluxPublisher_Start(t *testing.T) {
+	tests := []struct {
+		name        string
+		batchSize   int
+		writeStatus int
+		wantErr     bool
+	}{
+		{
+			name:        "Test Start writes full batches and flushes the rest on Stop",
+			batchSize:   2,
+			writeStatus: http.StatusNoContent,
+		},
+		{
+			name:        "Test Start returns error if InfluxDB is unavailable",
+			batchSize:   1,
+			writeStatus: http.StatusInternalServerError,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn, server := newInfluxStandIn(t, http.StatusOK, tt.writeStatus)
+			msgChan := make(chan consumer.Message)
+			publisher := NewInfluxPublisher(InfluxConfig{URL: server.URL, BatchSize: tt.batchSize, FlushInterval: time.Hour}, msgChan)
+			assert.NoError(t, publisher.Init())
+			errChan := make(chan error)
+			go func() {
+				errChan <- publisher.Start()
+			}()
+			if tt.wantErr {
+				msgChan <- newBandwidthMessage(1000)
+				assert.ErrorIs(t, <-errChan, errInfluxUnavailable)
+				assert.Len(t, publisher.batchLabels, 1)
+				assert.NoError(t, publisher.Stop())
+				return
+			}
+			for i := 0; i < 3; i++ {
+				msgChan <- newBandwidthMessage(float64(i))
+			}
+			assert.NoError(t, publisher.Stop())
+			assert.NoError(t, <-errChan)
+			bodies := standIn.getBodies()
+			assert.Len(t, bodies, 2)
+			assert.Equal(t, 2, strings.Count(bodies[0], "\n"))
+			assert.Equal(t, 1, strings.Count(bodies[1], "\n"))
+		})
+	}
+}
+
+func TestInfluxPublisher_Stop(t *testing.T) {
+	publisher := NewInfluxPublisher(InfluxConfig{}, make(chan consumer.Message))
+	assert.NoError(t, publisher.Stop())
+}
@@ -0,0 +1,20 @@
+package publisher
+
+import (
+	"encoding/json"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+)
+
+// JSONEncoder encodes messages in the JSON format of Telegraf, which is also the format of the received messages
+type JSONEncoder struct{}
+
+// Encode keeps the names of all tags and fields, numbers received as json.Number are written unchanged
+func (encoder *JSONEncoder) Encode(msg consumer.Message) ([]byte, error) {
+	return json.Marshal(consumer.TelemetryMessage{
+		Fields:    msg.GetFields(),
+		Name:      msg.GetName(),
+		Tags:      msg.GetTags(),
+		Timestamp: msg.GetTimestamp(),
+	})
+}
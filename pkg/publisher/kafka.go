@@ -1,142 +1,213 @@
 package publisher
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
-	"github.com/influxdata/line-protocol/v2/lineprotocol"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
 	"github.com/sirupsen/logrus"
 )
 
 type KafkaPublisher struct {
+	encoder          Encoder
+	keyTemplate      *KeyTemplate
 	log              *logrus.Entry
-	kafkaBroker      string
+	kafkaBrokers     []string
 	kafkaTopic       string
+	security         *kafka.SecurityConfig
+	producerConfig   *kafka.ProducerConfig
 	processedMsgChan chan consumer.Message
-	quitChan         chan bool
+	ctx              context.Context
+	cancel           context.CancelFunc
+	mutex            sync.Mutex
+	running          runGuard
+	draining         sync.WaitGroup
 	producer         sarama.AsyncProducer
+	producerClosed   bool
+	// connectionErr receives the first connection error reported by the producer, Start returns it to reconnect
+	connectionErr chan error
 }
 
-func NewKafkaPublisher(kafkaBroker, kafkaTopic string, msgChan chan consumer.Message) *KafkaPublisher {
+// NewKafkaPublisher creates a publisher with the default producer settings if producerConfig is nil, without key template
+// the messages are published without key
+func NewKafkaPublisher(kafkaBrokers []string, kafkaTopic string, security *kafka.SecurityConfig, producerConfig *kafka.ProducerConfig, encoder Encoder, keyTemplate *KeyTemplate, msgChan chan consumer.Message) *KafkaPublisher {
+	if producerConfig == nil {
+		producerConfig = kafka.NewProducerConfig()
+	}
 	return &KafkaPublisher{
+		encoder:          encoder,
+		keyTemplate:      keyTemplate,
 		log:              logging.DefaultLogger.WithField("subsystem", subsystem),
-		kafkaBroker:      kafkaBroker,
+		kafkaBrokers:     kafkaBrokers,
 		kafkaTopic:       kafkaTopic,
+		security:         security,
+		producerConfig:   producerConfig,
 		processedMsgChan: msgChan,
-		quitChan:         make(chan bool),
 	}
 }
 
+func (publisher *KafkaPublisher) createConfig() (*sarama.Config, error) {
+	saramaConfig := sarama.NewConfig()
+	if err := publisher.security.Apply(saramaConfig); err != nil {
+		return nil, err
+	}
+	if err := publisher.producerConfig.Apply(saramaConfig); err != nil {
+		return nil, err
+	}
+	return saramaConfig, nil
+}
+
+// Init creates a new producer, it is called again to reconnect after Start returned an error
 func (publisher *KafkaPublisher) Init() error {
-	producer, err := sarama.NewAsyncProducer([]string{publisher.kafkaBroker}, nil)
+	publisher.running.arm()
+	publisher.ctx, publisher.cancel = context.WithCancel(context.Background())
+	saramaConfig, err := publisher.createConfig()
+	if err != nil {
+		return err
+	}
+	producer, err := sarama.NewAsyncProducer(publisher.kafkaBrokers, saramaConfig)
 	if err != nil {
 		publisher.log.Debugln("Error creating producer: ", err)
 		return err
 	}
-	publisher.producer = producer
+	publisher.setProducer(producer)
 	return nil
 }
 
-func (publisher *KafkaPublisher) createEncoder(msg consumer.TelemetryMessage) lineprotocol.Encoder {
-	var enc lineprotocol.Encoder
-	enc.SetPrecision(lineprotocol.Nanosecond)
-	enc.StartLine(msg.Name)
-	return enc
+// setProducer starts draining the successes and errors of the producer, which have to be read for the producer to make progress
+func (publisher *KafkaPublisher) setProducer(producer sarama.AsyncProducer) {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	publisher.producer = producer
+	publisher.producerClosed = false
+	publisher.connectionErr = make(chan error, 1)
+	publisher.draining.Add(1)
+	go publisher.drain(producer, publisher.connectionErr)
 }
 
-func (publisher *KafkaPublisher) encodeTags(enc *lineprotocol.Encoder, tags consumer.MessageTags) {
-	enc.AddTag("host", tags.Host)
-	enc.AddTag("interface_name", tags.InterfaceName)
-	if tags.Node != "" {
-		enc.AddTag("node", tags.Node)
-	}
-	enc.AddTag("path", tags.Path)
-	enc.AddTag("source", tags.Source)
-	enc.AddTag("subscription", tags.Subscription)
+// messageLabels are attached as metadata to the produced messages to count acknowledged and failed messages
+type messageLabels struct {
+	measurement string
+	node        string
 }
 
-func (publisher *KafkaPublisher) encodeDelayMessage(msg consumer.DelayMessage) ([]byte, error) {
-	enc := publisher.createEncoder(msg.TelemetryMessage)
-	publisher.encodeTags(&enc, msg.Tags)
-	enc.AddField("delay_measurement_session/last_advertisement_information/advertised_values/average", lineprotocol.MustNewValue(float64(msg.Average)))
-	enc.AddField("delay_measurement_session/last_advertisement_information/advertised_values/maximum", lineprotocol.MustNewValue(float64(msg.Maximum)))
-	enc.AddField("delay_measurement_session/last_advertisement_information/advertised_values/minimum", lineprotocol.MustNewValue(float64(msg.Minimum)))
-	enc.AddField("delay_measurement_session/last_advertisement_information/advertised_values/variance", lineprotocol.MustNewValue(float64(msg.Variance)))
-	enc.EndLine(time.Unix(msg.Timestamp, 0))
-	if err := enc.Err(); err != nil {
-		return nil, err
-	}
-	return enc.Bytes(), nil
+// handleProduceError counts a message which the producer failed to deliver after all retries
+func (publisher *KafkaPublisher) handleProduceError(err *sarama.ProducerError) {
+	publisher.log.Errorln("Failed to produce message", err)
+	labels, _ := err.Msg.Metadata.(messageLabels)
+	metrics.PublisherFailed.WithLabelValues(labels.measurement, labels.node, metrics.ReasonProduce).Inc()
 }
 
-func (publisher *KafkaPublisher) encodeLossMessage(msg consumer.LossMessage) ([]byte, error) {
-	enc := publisher.createEncoder(msg.TelemetryMessage)
-	publisher.encodeTags(&enc, msg.Tags)
-	enc.AddField("interface_status_and_data/enabled/packet_loss_percentage", lineprotocol.MustNewValue(msg.LossPercentage))
-	enc.EndLine(time.Unix(msg.Timestamp, 0))
-	if err := enc.Err(); err != nil {
-		return nil, err
-	}
-	return enc.Bytes(), nil
-}
-
-func (publisher *KafkaPublisher) encodeBandwidthMessage(msg consumer.BandwidthMessage) ([]byte, error) {
-	enc := publisher.createEncoder(msg.TelemetryMessage)
-	publisher.encodeTags(&enc, msg.Tags)
-	enc.AddField("interface_status_and_data/enabled/bandwidth", lineprotocol.MustNewValue(msg.Bandwidth))
-	enc.EndLine(time.Unix(msg.Timestamp, 0))
-	if err := enc.Err(); err != nil {
-		return nil, err
+// drain reads the acknowledged and failed messages until the producer is closed, connection errors are passed to Start
+func (publisher *KafkaPublisher) drain(producer sarama.AsyncProducer, connectionErr chan error) {
+	defer publisher.draining.Done()
+	successes, errs := producer.Successes(), producer.Errors()
+	for successes != nil || errs != nil {
+		select {
+		case msg, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			labels, _ := msg.Metadata.(messageLabels)
+			metrics.PublisherPublished.WithLabelValues(labels.measurement, labels.node).Inc()
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			publisher.handleProduceError(err)
+			if publisher.isConnectionError(err) {
+				select {
+				case connectionErr <- err:
+				default:
+				}
+			}
+		}
 	}
-	return enc.Bytes(), nil
 }
 
-func (publisher *KafkaPublisher) encodeMessage(msg consumer.Message) ([]byte, error) {
-	switch msg := msg.(type) {
-	case *consumer.DelayMessage:
-		return publisher.encodeDelayMessage(*msg)
-	case *consumer.LossMessage:
-		return publisher.encodeLossMessage(*msg)
-	case *consumer.BandwidthMessage:
-		return publisher.encodeBandwidthMessage(*msg)
-	default:
-		return nil, fmt.Errorf("Skipping unknown message type: %v", msg)
-	}
-}
 func (publisher *KafkaPublisher) publishMessage(msg consumer.Message) {
-	encodedMsg, err := publisher.encodeMessage(msg)
+	labels := messageLabels{measurement: msg.GetName(), node: msg.GetTags().Source()}
+	encodedMsg, err := publisher.encoder.Encode(msg)
 	if err != nil {
 		publisher.log.Errorln("Error encoding message: ", err)
+		metrics.PublisherFailed.WithLabelValues(labels.measurement, labels.node, metrics.ReasonEncode).Inc()
 		return
 	}
+	producerMsg := &sarama.ProducerMessage{Topic: publisher.kafkaTopic, Value: sarama.ByteEncoder(encodedMsg), Metadata: labels}
+	// the hash partitioner writes messages with the same key to the same partition
+	key := publisher.keyTemplate.Key(msg)
+	if key != nil {
+		producerMsg.Key = sarama.ByteEncoder(key)
+	}
 	select {
-	case publisher.producer.Input() <- &sarama.ProducerMessage{Topic: publisher.kafkaTopic, Key: nil, Value: sarama.ByteEncoder(encodedMsg)}:
-		publisher.log.Debugf("Successfully enqueued message %v on topic %s\n", string(encodedMsg), publisher.kafkaTopic)
-	case err := <-publisher.producer.Errors():
-		publisher.log.Errorln("Failed to produce message", err)
+	case publisher.producer.Input() <- producerMsg:
+		publisher.log.Debugf("Successfully enqueued message %v with key %q on topic %s\n", string(encodedMsg), key, publisher.kafkaTopic)
+		metrics.PublisherMessageSize.WithLabelValues(labels.measurement).Observe(float64(len(encodedMsg)))
+	case <-publisher.ctx.Done():
 	}
 }
 
-func (publisher *KafkaPublisher) Start() {
-	publisher.log.Infoln("Starting publishing messages to broker", publisher.kafkaBroker, "and topic", publisher.kafkaTopic)
+// isConnectionError reports whether the producer lost the connection to the Kafka cluster
+func (publisher *KafkaPublisher) isConnectionError(err error) bool {
+	return errors.Is(err, sarama.ErrOutOfBrokers) || errors.Is(err, sarama.ErrNotConnected) || errors.Is(err, sarama.ErrClosedClient)
+}
+
+// Start publishes messages until Stop is called (returns nil) or the connection to Kafka is lost (returns the error)
+func (publisher *KafkaPublisher) Start() error {
+	if !publisher.running.begin() {
+		return nil
+	}
+	defer publisher.running.end()
+	publisher.log.Infoln("Starting publishing messages to brokers", strings.Join(publisher.kafkaBrokers, ","), "and topic", publisher.kafkaTopic)
 	for {
 		select {
 		case msg := <-publisher.processedMsgChan:
 			publisher.publishMessage(msg)
-		case <-publisher.quitChan:
-			publisher.log.Infoln("Stopping publisher with broker ", publisher.kafkaBroker, " and topic ", publisher.kafkaTopic)
-			return
+		case err := <-publisher.connectionErr:
+			return err
+		case <-publisher.ctx.Done():
+			publisher.log.Infoln("Stopping publisher with brokers ", strings.Join(publisher.kafkaBrokers, ","), " and topic ", publisher.kafkaTopic)
+			return nil
 		}
 	}
 }
 
+// Stop ends publishing and closes the producer, it returns once all enqueued messages are acknowledged or failed,
+// but at most after the flush timeout. It is safe to call if Init failed or Start already returned
 func (publisher *KafkaPublisher) Stop() error {
-	publisher.quitChan <- true
-	if err := publisher.producer.Close(); err != nil {
-		return err
+	if publisher.cancel != nil {
+		publisher.cancel()
+	}
+	// the producer input must not be closed while Start is still sending on it
+	publisher.running.wait()
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	// closing a sarama producer twice panics, therefore the producer is only closed once per Init
+	if publisher.producer == nil || publisher.producerClosed {
+		return nil
+	}
+	publisher.producerClosed = true
+	// AsyncClose flushes the buffered messages, the successes and errors channels are closed once all of them are delivered
+	publisher.producer.AsyncClose()
+	drained := make(chan struct{})
+	go func() {
+		publisher.draining.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(publisher.producerConfig.FlushTimeout):
+		return fmt.Errorf("Unable to flush the producer within %s, pending messages may be lost", publisher.producerConfig.FlushTimeout)
 	}
-	return nil
 }
@@ -1,19 +1,25 @@
 package publisher
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/IBM/sarama/mocks"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/kafka"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewKafkaPublisher(t *testing.T) {
 	type args struct {
-		kafkaBroker string
-		kafkaTopic  string
-		msgChan     chan consumer.Message
+		kafkaBrokers []string
+		kafkaTopic   string
+		msgChan      chan consumer.Message
 	}
 	tests := []struct {
 		name string
@@ -22,24 +28,67 @@ func TestNewKafkaPublisher(t *testing.T) {
 		{
 			name: "TestNewKafkaPublisher",
 			args: args{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
-				msgChan:     make(chan consumer.Message),
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
+				msgChan:      make(chan consumer.Message),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kafkaPublisher := NewKafkaPublisher(tt.args.kafkaBroker, tt.args.kafkaTopic, tt.args.msgChan)
+			kafkaPublisher := NewKafkaPublisher(tt.args.kafkaBrokers, tt.args.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, tt.args.msgChan)
 			assert.NotNil(t, kafkaPublisher)
 		})
 	}
 }
 
+func TestKafkaPublisher_createConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		security *kafka.SecurityConfig
+		producer *kafka.ProducerConfig
+		wantErr  bool
+	}{
+		{
+			name:     "Test create config without security",
+			security: nil,
+			wantErr:  false,
+		},
+		{
+			name:     "Test create config with TLS",
+			security: &kafka.SecurityConfig{TLSEnabled: true},
+			wantErr:  false,
+		},
+		{
+			name:     "Test create config with invalid SASL settings",
+			security: &kafka.SecurityConfig{SASLMechanism: "PLAIN"},
+			wantErr:  true,
+		},
+		{
+			name:     "Test create config with invalid producer settings",
+			producer: &kafka.ProducerConfig{Acks: "leader", Idempotent: true, Retries: 3},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", tt.security, tt.producer, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			saramaConfig, err := publisher.createConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, publisher.Init())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, saramaConfig.Producer.Return.Successes)
+			}
+		})
+	}
+}
+
 func TestKafkaPublisher_Init(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	tests := []struct {
 		name   string
@@ -48,14 +97,14 @@ func TestKafkaPublisher_Init(t *testing.T) {
 		{
 			name: "TestKafkaPublisher_Init",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
+			publisher := NewKafkaPublisher(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
 			assert.Error(t, publisher.Init())
 		})
 	}
@@ -63,8 +112,8 @@ func TestKafkaPublisher_Init(t *testing.T) {
 
 func TestKafkaPublisher_encodeTags(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	type args struct {
 		msg  consumer.TelemetryMessage
@@ -79,17 +128,17 @@ func TestKafkaPublisher_encodeTags(t *testing.T) {
 		{
 			name: "Test encode tags with Node",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				tags: consumer.MessageTags{
-					Host:          "telegraf",
-					InterfaceName: "GigabitEthernet0/0/0/0",
-					Node:          "0/RP0/CPU0",
-					Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-					Source:        "XR-1",
-					Subscription:  "hawk-metrics",
+					"host":           "telegraf",
+					"interface_name": "GigabitEthernet0/0/0/0",
+					"node":           "0/RP0/CPU0",
+					"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+					"source":         "XR-1",
+					"subscription":   "hawk-metrics",
 				},
 				msg: consumer.TelemetryMessage{
 					Name:      "performance-measurement",
@@ -101,16 +150,16 @@ func TestKafkaPublisher_encodeTags(t *testing.T) {
 		{
 			name: "Test encode tags without Node",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				tags: consumer.MessageTags{
-					Host:          "telegraf",
-					InterfaceName: "GigabitEthernet0/0/0/0",
-					Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-					Source:        "XR-1",
-					Subscription:  "hawk-metrics",
+					"host":           "telegraf",
+					"interface_name": "GigabitEthernet0/0/0/0",
+					"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+					"source":         "XR-1",
+					"subscription":   "hawk-metrics",
 				},
 				msg: consumer.TelemetryMessage{
 					Name:      "performance-measurement",
@@ -119,22 +168,42 @@ func TestKafkaPublisher_encodeTags(t *testing.T) {
 			},
 			want: "performance-measurement,host=telegraf,interface_name=GigabitEthernet0/0/0/0,path=Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail,source=XR-1,subscription=hawk-metrics",
 		},
+		{
+			name: "Test encode tags keeps additional tags and skips empty tags",
+			fields: fields{
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
+			},
+			args: args{
+				tags: consumer.MessageTags{
+					"source":         "XR-1",
+					"instance_name":  "1",
+					"interface_name": "GigabitEthernet0/0/0/0",
+					"node":           "",
+				},
+				msg: consumer.TelemetryMessage{
+					Name:      "isis",
+					Timestamp: 1704728369,
+				},
+			},
+			want: "isis,instance_name=1,interface_name=GigabitEthernet0/0/0/0,source=XR-1",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
+			encoder := &LineProtocolEncoder{}
 			tt.args.msg.Tags = tt.args.tags
-			enc := publisher.createEncoder(tt.args.msg)
-			publisher.encodeTags(&enc, tt.args.tags)
+			enc := encoder.createEncoder(tt.args.msg.Name)
+			encoder.encodeTags(&enc, tt.args.tags)
 			assert.Equal(t, tt.want, string(enc.Bytes()))
 		})
 	}
 }
 
-func TestKafkaPublisher_encodeDelayMessage(t *testing.T) {
+func TestKafkaPublisher_encodeMessage_Delay(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	type args struct {
 		msg consumer.DelayMessage
@@ -149,20 +218,20 @@ func TestKafkaPublisher_encodeDelayMessage(t *testing.T) {
 		{
 			name: "Test encode delay message without error",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: consumer.DelayMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "performance-measurement",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Node:          "0/RP0/CPU0",
-							Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"node":           "0/RP0/CPU0",
+							"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -177,8 +246,8 @@ func TestKafkaPublisher_encodeDelayMessage(t *testing.T) {
 		{
 			name: "Test create Encoder without Node",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: consumer.DelayMessage{
@@ -191,8 +260,8 @@ func TestKafkaPublisher_encodeDelayMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
-			byteMsg, err := publisher.encodeDelayMessage(tt.args.msg)
+			publisher := NewKafkaPublisher(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			byteMsg, err := publisher.encoder.Encode(&tt.args.msg)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -203,10 +272,10 @@ func TestKafkaPublisher_encodeDelayMessage(t *testing.T) {
 	}
 }
 
-func TestKafkaPublisher_encodeLossMessage(t *testing.T) {
+func TestKafkaPublisher_encodeMessage_Loss(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	type args struct {
 		msg consumer.LossMessage
@@ -221,19 +290,19 @@ func TestKafkaPublisher_encodeLossMessage(t *testing.T) {
 		{
 			name: "Test encode loss message without error",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: consumer.LossMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "isis",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -245,8 +314,8 @@ func TestKafkaPublisher_encodeLossMessage(t *testing.T) {
 		{
 			name: "Test encode loss message with error",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: consumer.LossMessage{},
@@ -256,8 +325,8 @@ func TestKafkaPublisher_encodeLossMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
-			byteMsg, err := publisher.encodeLossMessage(tt.args.msg)
+			publisher := NewKafkaPublisher(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			byteMsg, err := publisher.encoder.Encode(&tt.args.msg)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -268,10 +337,10 @@ func TestKafkaPublisher_encodeLossMessage(t *testing.T) {
 	}
 }
 
-func TestKafkaPublisher_encodeBandwidthMessage(t *testing.T) {
+func TestKafkaPublisher_encodeMessage_Bandwidth(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	type args struct {
 		msg consumer.BandwidthMessage
@@ -286,19 +355,19 @@ func TestKafkaPublisher_encodeBandwidthMessage(t *testing.T) {
 		{
 			name: "Test encode bw message without error",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: consumer.BandwidthMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "isis",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -310,8 +379,8 @@ func TestKafkaPublisher_encodeBandwidthMessage(t *testing.T) {
 		{
 			name: "Test encode bw message with error",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: consumer.BandwidthMessage{},
@@ -321,8 +390,91 @@ func TestKafkaPublisher_encodeBandwidthMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
-			byteMsg, err := publisher.encodeBandwidthMessage(tt.args.msg)
+			publisher := NewKafkaPublisher(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			byteMsg, err := publisher.encoder.Encode(&tt.args.msg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, string(byteMsg))
+			}
+		})
+	}
+}
+
+func TestKafkaPublisher_encodeMessage_Passthrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     consumer.PassthroughMessage
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Test encode utilization message",
+			msg: consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"out_octets": json.Number("1864216230"),
+						"in_octets":  json.Number("47912820356"),
+					},
+					Name: "utilization",
+					Tags: map[string]string{
+						"subscription": "hawk-metrics",
+						"source":       "XR-1",
+						"path":         "openconfig-interfaces:interfaces/interface/state/counters",
+						"name":         "GigabitEthernet0/0/0/0",
+						"host":         "telegraf",
+					},
+					Timestamp: 1704728433,
+				},
+			},
+			want:    "utilization,host=telegraf,name=GigabitEthernet0/0/0/0,path=openconfig-interfaces:interfaces/interface/state/counters,source=XR-1,subscription=hawk-metrics in_octets=47912820356i,out_octets=1864216230i 1704728433000000000\n",
+			wantErr: false,
+		},
+		{
+			name: "Test encode message with float, string and bool fields and empty tag",
+			msg: consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"ratio":   json.Number("0.5"),
+						"state":   "up",
+						"enabled": true,
+						"average": 3.5,
+					},
+					Name:      "interfaces",
+					Tags:      map[string]string{"source": "XR-1", "node": ""},
+					Timestamp: 1704728433,
+				},
+			},
+			want:    "interfaces,source=XR-1 average=3.5,enabled=true,ratio=0.5,state=\"up\" 1704728433000000000\n",
+			wantErr: false,
+		},
+		{
+			name: "Test encode message with nested field",
+			msg: consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields:    map[string]interface{}{"nested": map[string]interface{}{"value": 1}},
+					Name:      "interfaces",
+					Timestamp: 1704728433,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test encode message without fields",
+			msg: consumer.PassthroughMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Name:      "interfaces",
+					Timestamp: 1704728433,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			byteMsg, err := publisher.encoder.Encode(&tt.msg)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -333,10 +485,69 @@ func TestKafkaPublisher_encodeBandwidthMessage(t *testing.T) {
 	}
 }
 
+func TestKafkaPublisher_encodeMessage_PreservesFields(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  consumer.Message
+		want string
+	}{
+		{
+			name: "Test encode loss message keeps additional fields and tags",
+			msg: &consumer.LossMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"interface_status_and_data/enabled/packet_loss_percentage": json.Number("0"),
+						"interface_status_and_data/enabled/adjacency_count":        json.Number("1"),
+					},
+					Name: "isis",
+					Tags: consumer.MessageTags{
+						"instance_name":  "1",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"source":         "XR-1",
+					},
+					Timestamp: 1704728296,
+				},
+				LossPercentage: 2.5,
+			},
+			want: "isis,instance_name=1,interface_name=GigabitEthernet0/0/0/0,source=XR-1 interface_status_and_data/enabled/adjacency_count=1i,interface_status_and_data/enabled/packet_loss_percentage=2.5 1704728296000000000\n",
+		},
+		{
+			name: "Test encode delay message overrides only impaired fields",
+			msg: &consumer.DelayMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Fields: map[string]interface{}{
+						"delay_measurement_session/last_advertisement_information/advertised_values/average":  json.Number("8553"),
+						"delay_measurement_session/last_advertisement_information/advertised_values/maximum":  json.Number("8836"),
+						"delay_measurement_session/last_advertisement_information/advertised_values/minimum":  json.Number("8242"),
+						"delay_measurement_session/last_advertisement_information/advertised_values/variance": json.Number("307"),
+						"delay_measurement_session/session_name":                                              "default",
+					},
+					Name:      "performance-measurement",
+					Tags:      consumer.MessageTags{"source": "XR-1"},
+					Timestamp: 1704728135,
+				},
+				Average:  10000,
+				Maximum:  11000,
+				Minimum:  9000,
+				Variance: 2000,
+			},
+			want: "performance-measurement,source=XR-1 delay_measurement_session/last_advertisement_information/advertised_values/average=10000,delay_measurement_session/last_advertisement_information/advertised_values/maximum=11000,delay_measurement_session/last_advertisement_information/advertised_values/minimum=9000,delay_measurement_session/last_advertisement_information/advertised_values/variance=2000,delay_measurement_session/session_name=\"default\" 1704728135000000000\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			byteMsg, err := publisher.encoder.Encode(tt.msg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(byteMsg))
+		})
+	}
+}
+
 func TestKafkaPublisher_encodeMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	type args struct {
 		msg consumer.Message
@@ -351,19 +562,19 @@ func TestKafkaPublisher_encodeMessage(t *testing.T) {
 		{
 			name: "Test encode message with BW message",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: &consumer.BandwidthMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "isis",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -375,19 +586,19 @@ func TestKafkaPublisher_encodeMessage(t *testing.T) {
 		{
 			name: "Test encode message with loss message",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: &consumer.LossMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "isis",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -399,20 +610,20 @@ func TestKafkaPublisher_encodeMessage(t *testing.T) {
 		{
 			name: "Test encode message with delay message",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: &consumer.DelayMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "performance-measurement",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Node:          "0/RP0/CPU0",
-							Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"node":           "0/RP0/CPU0",
+							"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -430,11 +641,11 @@ func TestKafkaPublisher_encodeMessage(t *testing.T) {
 				msg: &consumer.TelemetryMessage{
 					Name: "unknown",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Node:          "0/RP0/CPU0",
-						Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-						Source:        "XR-1",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"node":           "0/RP0/CPU0",
+						"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+						"source":         "XR-1",
 					},
 					Timestamp: 1704728135,
 				},
@@ -444,8 +655,8 @@ func TestKafkaPublisher_encodeMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
-			byteMsg, err := publisher.encodeMessage(tt.args.msg)
+			publisher := NewKafkaPublisher(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			byteMsg, err := publisher.encoder.Encode(tt.args.msg)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -456,10 +667,17 @@ func TestKafkaPublisher_encodeMessage(t *testing.T) {
 	}
 }
 
+// newMockProducer returns a mock producer which returns successes and errors like the producer created by Init
+func newMockProducer(t *testing.T) *mocks.AsyncProducer {
+	saramaConfig := sarama.NewConfig()
+	assert.NoError(t, kafka.NewProducerConfig().Apply(saramaConfig))
+	return mocks.NewAsyncProducer(t, saramaConfig)
+}
+
 func TestKafkaPublisher_publishMessage(t *testing.T) {
 	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+		kafkaBrokers []string
+		kafkaTopic   string
 	}
 	type args struct {
 		msg consumer.Message
@@ -473,19 +691,19 @@ func TestKafkaPublisher_publishMessage(t *testing.T) {
 		{
 			name: "Test publish message with BW message",
 			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
+				kafkaBrokers: []string{"localhost:9092"},
+				kafkaTopic:   "test",
 			},
 			args: args{
 				msg: &consumer.BandwidthMessage{
 					TelemetryMessage: consumer.TelemetryMessage{
 						Name: "isis",
 						Tags: consumer.MessageTags{
-							Host:          "telegraf",
-							InterfaceName: "GigabitEthernet0/0/0/0",
-							Path:          "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
-							Source:        "XR-1",
-							Subscription:  "hawk-metrics",
+							"host":           "telegraf",
+							"interface_name": "GigabitEthernet0/0/0/0",
+							"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+							"source":         "XR-1",
+							"subscription":   "hawk-metrics",
 						},
 						Timestamp: 1704728135,
 					},
@@ -500,11 +718,11 @@ func TestKafkaPublisher_publishMessage(t *testing.T) {
 				msg: &consumer.TelemetryMessage{
 					Name: "unknown",
 					Tags: consumer.MessageTags{
-						Host:          "telegraf",
-						InterfaceName: "GigabitEthernet0/0/0/0",
-						Node:          "0/RP0/CPU0",
-						Path:          "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
-						Source:        "XR-1",
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"node":           "0/RP0/CPU0",
+						"path":           "Cisco-IOS-XR-perf-meas-oper:performance-measurement/nodes/node/interfaces/interface-details/interface-detail",
+						"source":         "XR-1",
 					},
 					Timestamp: 1704728135,
 				},
@@ -514,40 +732,190 @@ func TestKafkaPublisher_publishMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
+			publisher := NewKafkaPublisher(tt.fields.kafkaBrokers, tt.fields.kafkaTopic, nil, nil, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+			publisher.ctx = context.Background()
+			name, source := tt.args.msg.GetName(), tt.args.msg.GetTags().Source()
+			published := testutil.ToFloat64(metrics.PublisherPublished.WithLabelValues(name, source))
+			failed := testutil.ToFloat64(metrics.PublisherFailed.WithLabelValues(name, source, metrics.ReasonEncode))
 			if tt.wantErr {
 				publisher.publishMessage(tt.args.msg)
+				assert.Equal(t, failed+1, testutil.ToFloat64(metrics.PublisherFailed.WithLabelValues(name, source, metrics.ReasonEncode)))
 			} else {
-				publisher.producer = mocks.NewAsyncProducer(t, nil).ExpectInputAndSucceed()
+				publisher.setProducer(newMockProducer(t).ExpectInputAndSucceed())
 				publisher.publishMessage(tt.args.msg)
+				assert.NoError(t, publisher.Stop())
+				assert.Equal(t, published+1, testutil.ToFloat64(metrics.PublisherPublished.WithLabelValues(name, source)))
 			}
 		})
 	}
 }
+
+func TestKafkaPublisher_Start(t *testing.T) {
+	tests := []struct {
+		name       string
+		produceErr error
+		wantErr    bool
+	}{
+		{
+			name:       "Test Start returns nil when stopped",
+			produceErr: nil,
+			wantErr:    false,
+		},
+		{
+			name:       "Test Start ignores message errors",
+			produceErr: sarama.ErrMessageSizeTooLarge,
+			wantErr:    false,
+		},
+		{
+			name:       "Test Start returns error when brokers are lost",
+			produceErr: sarama.ErrOutOfBrokers,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgChan := make(chan consumer.Message)
+			publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", nil, nil, &LineProtocolEncoder{}, nil, msgChan)
+			publisher.ctx, publisher.cancel = context.WithCancel(context.Background())
+			publisher.running.arm()
+			producer := newMockProducer(t)
+			if tt.produceErr != nil {
+				producer.ExpectInputAndFail(tt.produceErr)
+			} else {
+				producer.ExpectInputAndSucceed()
+			}
+			publisher.setProducer(producer)
+			errChan := make(chan error)
+			go func() {
+				errChan <- publisher.Start()
+			}()
+			msgChan <- &consumer.BandwidthMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Name: "isis",
+					Tags: consumer.MessageTags{
+						"host":           "telegraf",
+						"interface_name": "GigabitEthernet0/0/0/0",
+						"path":           "Cisco-IOS-XR-clns-isis-oper:isis/instances/instance/interfaces/interface",
+						"source":         "XR-1",
+						"subscription":   "hawk-metrics",
+					},
+					Timestamp: 1704728135,
+				},
+				Bandwidth: 100000,
+			}
+			if tt.wantErr {
+				assert.ErrorIs(t, <-errChan, tt.produceErr)
+			} else {
+				time.Sleep(100 * time.Millisecond)
+				assert.NoError(t, publisher.Stop())
+				assert.NoError(t, <-errChan)
+			}
+			assert.NoError(t, publisher.Stop())
+		})
+	}
+}
+
 func TestKafkaPublisher_Stop(t *testing.T) {
-	type fields struct {
-		kafkaBroker string
-		kafkaTopic  string
+	tests := []struct {
+		name        string
+		initialized bool
+		started     bool
+	}{
+		{
+			name:        "Test Stop without Init",
+			initialized: false,
+		},
+		{
+			name:        "Test Stop of running publisher",
+			initialized: true,
+			started:     true,
+		},
+		{
+			name:        "Test Stop of initialized publisher before Start",
+			initialized: true,
+			started:     false,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgChan := make(chan consumer.Message, 1)
+			publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", nil, nil, &LineProtocolEncoder{}, nil, msgChan)
+			if tt.initialized {
+				publisher.ctx, publisher.cancel = context.WithCancel(context.Background())
+				publisher.running.arm()
+				publisher.setProducer(newMockProducer(t))
+			}
+			if tt.started {
+				go publisher.Start()
+				time.Sleep(100 * time.Millisecond)
+			}
+			assert.NoError(t, publisher.Stop())
+			if tt.initialized && !tt.started {
+				// a Start racing with Stop must not send on the closed producer input
+				msgChan <- &consumer.BandwidthMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "isis"}}
+				assert.NoError(t, publisher.Start())
+			}
+			assert.NoError(t, publisher.Stop())
+		})
+	}
+}
+
+func TestKafkaPublisher_publishMessage_Key(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
+		name     string
+		template string
+		want     sarama.Encoder
 	}{
 		{
-			name: "Test Stop function",
-			fields: fields{
-				kafkaBroker: "localhost:9092",
-				kafkaTopic:  "test",
-			},
+			name:     "Test publish message with key",
+			template: "{source}/{interface_name|name}",
+			want:     sarama.ByteEncoder("XR-1/GigabitEthernet0/0/0/0"),
+		},
+		{
+			name:     "Test publish message without key template",
+			template: "",
+			want:     nil,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			publisher := NewKafkaPublisher(tt.fields.kafkaBroker, tt.fields.kafkaTopic, make(chan consumer.Message))
-			publisher.producer = mocks.NewAsyncProducer(t, nil)
-			go publisher.Start()
-			time.Sleep(1 * time.Second)
+			keyTemplate, err := NewKeyTemplate(tt.template)
+			assert.NoError(t, err)
+			publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", nil, nil, &LineProtocolEncoder{}, keyTemplate, make(chan consumer.Message))
+			publisher.ctx = context.Background()
+			producer := newMockProducer(t)
+			producer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+				assert.Equal(t, tt.want, msg.Key)
+				return nil
+			})
+			publisher.setProducer(producer)
+			publisher.publishMessage(&consumer.BandwidthMessage{
+				TelemetryMessage: consumer.TelemetryMessage{
+					Name:      "isis",
+					Tags:      consumer.MessageTags{"interface_name": "GigabitEthernet0/0/0/0", "source": "XR-1"},
+					Timestamp: 1704728135,
+				},
+				Bandwidth: 100000,
+			})
 			assert.NoError(t, publisher.Stop())
 		})
 	}
 }
+
+// hangingProducer never closes its successes and errors channels, like a producer which can not deliver its messages
+type hangingProducer struct {
+	*mocks.AsyncProducer
+}
+
+func (producer *hangingProducer) AsyncClose() {}
+
+func TestKafkaPublisher_Stop_FlushTimeout(t *testing.T) {
+	producerConfig := kafka.NewProducerConfig()
+	producerConfig.FlushTimeout = 50 * time.Millisecond
+	publisher := NewKafkaPublisher([]string{"localhost:9092"}, "test", nil, producerConfig, &LineProtocolEncoder{}, nil, make(chan consumer.Message))
+	producer := newMockProducer(t)
+	publisher.setProducer(&hangingProducer{AsyncProducer: producer})
+	assert.Error(t, publisher.Stop())
+	assert.NoError(t, publisher.Stop())
+	assert.NoError(t, producer.Close())
+}
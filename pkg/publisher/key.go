@@ -0,0 +1,83 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+)
+
+// keyPart is either a literal or a placeholder which is replaced by the first non-empty of its tags
+type keyPart struct {
+	literal string
+	tags    []string
+}
+
+// KeyTemplate creates the key of a published message from its tags, e.g. {source}/{interface_name|name}.
+// Messages with the same key are written to the same partition, which keeps the order per node and interface.
+type KeyTemplate struct {
+	parts []keyPart
+}
+
+// NewKeyTemplate parses a template, placeholders are tag names in braces, alternatives are separated by |
+func NewKeyTemplate(template string) (*KeyTemplate, error) {
+	keyTemplate := &KeyTemplate{}
+	remaining := template
+	for remaining != "" {
+		start := strings.IndexAny(remaining, "{}")
+		if start < 0 {
+			keyTemplate.parts = append(keyTemplate.parts, keyPart{literal: remaining})
+			break
+		}
+		if remaining[start] == '}' {
+			return nil, fmt.Errorf("Unexpected } in key template %q", template)
+		}
+		if start > 0 {
+			keyTemplate.parts = append(keyTemplate.parts, keyPart{literal: remaining[:start]})
+		}
+		end := strings.IndexAny(remaining[start+1:], "{}")
+		if end < 0 || remaining[start+1+end] != '}' {
+			return nil, fmt.Errorf("Unterminated placeholder in key template %q", template)
+		}
+		tags := strings.Split(remaining[start+1:start+1+end], "|")
+		for _, tag := range tags {
+			if strings.TrimSpace(tag) == "" {
+				return nil, fmt.Errorf("Empty tag name in key template %q", template)
+			}
+		}
+		keyTemplate.parts = append(keyTemplate.parts, keyPart{tags: tags})
+		remaining = remaining[start+2+end:]
+	}
+	return keyTemplate, nil
+}
+
+func (part keyPart) resolve(tags consumer.MessageTags) (string, bool) {
+	if part.tags == nil {
+		return part.literal, false
+	}
+	for _, tag := range part.tags {
+		if value := tags[strings.TrimSpace(tag)]; value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Key returns the key of the message, it is nil if the template is empty or none of the placeholders is found in the tags,
+// so these messages are spread over all partitions
+func (template *KeyTemplate) Key(msg consumer.Message) []byte {
+	if template == nil || len(template.parts) == 0 {
+		return nil
+	}
+	var key strings.Builder
+	found := false
+	for _, part := range template.parts {
+		value, ok := part.resolve(msg.GetTags())
+		found = found || ok
+		key.WriteString(value)
+	}
+	if !found {
+		return nil
+	}
+	return []byte(key.String())
+}
@@ -0,0 +1,109 @@
+package publisher
+
+import (
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKeyTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{
+			name:     "Test template with placeholders and alternatives",
+			template: "{source}/{interface_name|name}",
+		},
+		{
+			name:     "Test empty template",
+			template: "",
+		},
+		{
+			name:     "Test template with unterminated placeholder",
+			template: "{source}/{interface_name",
+			wantErr:  true,
+		},
+		{
+			name:     "Test template with nested placeholder",
+			template: "{source{name}}",
+			wantErr:  true,
+		},
+		{
+			name:     "Test template with unexpected closing brace",
+			template: "source}",
+			wantErr:  true,
+		},
+		{
+			name:     "Test template with empty tag name",
+			template: "{source|}",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyTemplate, err := NewKeyTemplate(tt.template)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, keyTemplate)
+			}
+		})
+	}
+}
+
+func TestKeyTemplate_Key(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		tags     consumer.MessageTags
+		want     []byte
+	}{
+		{
+			name:     "Test key of ISIS message",
+			template: "{source}/{interface_name|name}",
+			tags:     consumer.MessageTags{"source": "XR-1", "interface_name": "GigabitEthernet0/0/0/0"},
+			want:     []byte("XR-1/GigabitEthernet0/0/0/0"),
+		},
+		{
+			name:     "Test key of utilization message uses the alternative tag",
+			template: "{source}/{interface_name|name}",
+			tags:     consumer.MessageTags{"source": "XR-1", "name": "GigabitEthernet0/0/0/1", "interface_name": ""},
+			want:     []byte("XR-1/GigabitEthernet0/0/0/1"),
+		},
+		{
+			name:     "Test key with missing tag",
+			template: "{source}/{interface_name|name}",
+			tags:     consumer.MessageTags{"source": "XR-2"},
+			want:     []byte("XR-2/"),
+		},
+		{
+			name:     "Test no key without any tag of the template",
+			template: "{source}/{interface_name|name}",
+			tags:     consumer.MessageTags{"host": "telegraf"},
+			want:     nil,
+		},
+		{
+			name:     "Test no key with empty template",
+			template: "",
+			tags:     consumer.MessageTags{"source": "XR-1"},
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyTemplate, err := NewKeyTemplate(tt.template)
+			assert.NoError(t, err)
+			msg := &consumer.PassthroughMessage{TelemetryMessage: consumer.TelemetryMessage{Name: "test", Tags: tt.tags}}
+			assert.Equal(t, tt.want, keyTemplate.Key(msg))
+		})
+	}
+}
+
+func TestKeyTemplate_Key_Nil(t *testing.T) {
+	var keyTemplate *KeyTemplate
+	assert.Nil(t, keyTemplate.Key(&consumer.PassthroughMessage{}))
+}
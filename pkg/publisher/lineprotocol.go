@@ -0,0 +1,96 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/influxdata/line-protocol/v2/lineprotocol"
+)
+
+// LineProtocolEncoder encodes messages as InfluxDB line protocol
+type LineProtocolEncoder struct{}
+
+// counterFields are written as integers, all other numeric fields as floats.
+// InfluxDB rejects a field whose type differs from the one already stored, so the type must not depend on the value (e.g. 2 and 2.5).
+var counterFields = map[string]bool{
+	"in_octets":  true,
+	"out_octets": true,
+	"interface_status_and_data/enabled/adjacency_count": true,
+}
+
+func (encoder *LineProtocolEncoder) createEncoder(name string) lineprotocol.Encoder {
+	var enc lineprotocol.Encoder
+	enc.SetPrecision(lineprotocol.Nanosecond)
+	enc.StartLine(name)
+	return enc
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeTags adds all non-empty tags, line protocol requires them to be sorted by key
+func (encoder *LineProtocolEncoder) encodeTags(enc *lineprotocol.Encoder, tags consumer.MessageTags) {
+	for _, key := range sortedKeys(tags) {
+		if tags[key] != "" {
+			enc.AddTag(key, tags[key])
+		}
+	}
+}
+
+// convertFieldValue chooses the type of received numbers by the field name (see counterFields),
+// the values replaced by the processor already have the type of their field
+func (encoder *LineProtocolEncoder) convertFieldValue(key string, value interface{}) (lineprotocol.Value, error) {
+	if number, ok := value.(json.Number); ok {
+		floatValue, err := number.Float64()
+		if err != nil {
+			return lineprotocol.Value{}, err
+		}
+		value = floatValue
+		if counterFields[key] {
+			if intValue, err := number.Int64(); err == nil {
+				value = intValue
+			} else {
+				value = int64(floatValue)
+			}
+		}
+	}
+	fieldValue, ok := lineprotocol.NewValue(value)
+	if !ok {
+		return lineprotocol.Value{}, fmt.Errorf("Unsupported field value %v of type %T", value, value)
+	}
+	return fieldValue, nil
+}
+
+func (encoder *LineProtocolEncoder) encodeFields(enc *lineprotocol.Encoder, fields map[string]interface{}) error {
+	for _, key := range sortedKeys(fields) {
+		value, err := encoder.convertFieldValue(key, fields[key])
+		if err != nil {
+			return fmt.Errorf("Unable to encode field %s: %v", key, err)
+		}
+		enc.AddField(key, value)
+	}
+	return nil
+}
+
+// Encode encodes all original tags and fields, the values impaired by the processor are already replaced by the message
+func (encoder *LineProtocolEncoder) Encode(msg consumer.Message) ([]byte, error) {
+	enc := encoder.createEncoder(msg.GetName())
+	encoder.encodeTags(&enc, msg.GetTags())
+	if err := encoder.encodeFields(&enc, msg.GetFields()); err != nil {
+		return nil, err
+	}
+	enc.EndLine(time.Unix(msg.GetTimestamp(), 0))
+	if err := enc.Err(); err != nil {
+		return nil, err
+	}
+	return enc.Bytes(), nil
+}
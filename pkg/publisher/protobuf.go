@@ -0,0 +1,84 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/publisher/timeseriespb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	nameLabel  = "__name__"
+	fieldLabel = "__field__"
+)
+
+// ProtobufEncoder encodes messages as WriteRequest with the field numbers of the Prometheus remote write protocol
+type ProtobufEncoder struct{}
+
+// convertFieldValue returns the sample value of a field, false is returned for string fields which have no sample value
+func (encoder *ProtobufEncoder) convertFieldValue(value interface{}) (float64, bool, error) {
+	switch typedValue := value.(type) {
+	case json.Number:
+		floatValue, err := typedValue.Float64()
+		return floatValue, err == nil, err
+	case float64:
+		return typedValue, true, nil
+	case float32:
+		return float64(typedValue), true, nil
+	case int:
+		return float64(typedValue), true, nil
+	case int32:
+		return float64(typedValue), true, nil
+	case int64:
+		return float64(typedValue), true, nil
+	case uint32:
+		return float64(typedValue), true, nil
+	case uint64:
+		return float64(typedValue), true, nil
+	case bool:
+		if typedValue {
+			return 1, true, nil
+		}
+		return 0, true, nil
+	case string:
+		return 0, false, nil
+	default:
+		return 0, false, fmt.Errorf("Unsupported field value %v of type %T", value, value)
+	}
+}
+
+// createLabels returns the measurement and field name labels followed by all non-empty tags sorted by key
+func (encoder *ProtobufEncoder) createLabels(name, field string, tags consumer.MessageTags) []*timeseriespb.Label {
+	labels := []*timeseriespb.Label{{Name: nameLabel, Value: name}, {Name: fieldLabel, Value: field}}
+	for _, key := range sortedKeys(tags) {
+		if tags[key] != "" {
+			labels = append(labels, &timeseriespb.Label{Name: key, Value: tags[key]})
+		}
+	}
+	return labels
+}
+
+// Encode creates one time series per numeric or boolean field, string fields are skipped
+func (encoder *ProtobufEncoder) Encode(msg consumer.Message) ([]byte, error) {
+	request := &timeseriespb.WriteRequest{}
+	fields := msg.GetFields()
+	for _, key := range sortedKeys(fields) {
+		value, ok, err := encoder.convertFieldValue(fields[key])
+		if err != nil {
+			return nil, fmt.Errorf("Unable to encode field %s: %v", key, err)
+		}
+		if !ok {
+			continue
+		}
+		request.Timeseries = append(request.Timeseries, &timeseriespb.TimeSeries{
+			Labels:  encoder.createLabels(msg.GetName(), key, msg.GetTags()),
+			Samples: []*timeseriespb.Sample{{Value: value, Timestamp: msg.GetTimestamp() * 1000}},
+		})
+	}
+	if len(request.Timeseries) == 0 {
+		return nil, fmt.Errorf("Message %s has no numeric fields", msg.GetName())
+	}
+	return proto.Marshal(request)
+}
@@ -4,6 +4,6 @@ var subsystem = "publisher"
 
 type Publisher interface {
 	Init() error
-	Start()
+	Start() error
 	Stop() error
 }
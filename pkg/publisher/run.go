@@ -0,0 +1,70 @@
+package publisher
+
+import (
+	"sync"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
+)
+
+// runGuard lets Stop wait until Start returned. Init arms the guard before the supervisor runs Start,
+// so Stop can not miss a Start which is about to begin (e.g. close the producer input Start is going to send on).
+// If Stop comes first, the armed run is cancelled and Start returns immediately.
+type runGuard struct {
+	mutex   sync.Mutex
+	armed   bool
+	running sync.WaitGroup
+}
+
+// arm is called by Init, before Start is run
+func (guard *runGuard) arm() {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	if !guard.armed {
+		guard.armed = true
+		guard.running.Add(1)
+	}
+}
+
+// begin returns false if Stop already cancelled the run, otherwise end has to be called once Start returns
+func (guard *runGuard) begin() bool {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	if !guard.armed {
+		return false
+	}
+	guard.armed = false
+	return true
+}
+
+func (guard *runGuard) end() {
+	guard.running.Done()
+}
+
+// wait cancels a run which has not begun yet and waits for a running Start to return
+func (guard *runGuard) wait() {
+	guard.mutex.Lock()
+	if guard.armed {
+		guard.armed = false
+		guard.running.Done()
+	}
+	guard.mutex.Unlock()
+	guard.running.Wait()
+}
+
+// drainQueue passes the messages waiting in the queue to handle until handle fails and returns the number of handled messages.
+// It is called on Stop once Start returned, only the messages waiting when it is called are handled, so it ends even if the queue is still written.
+func drainQueue(queue chan consumer.Message, handle func(consumer.Message) error) (int, error) {
+	drained := 0
+	for waiting := len(queue); waiting > 0; waiting-- {
+		select {
+		case msg := <-queue:
+			if err := handle(msg); err != nil {
+				return drained, err
+			}
+			drained++
+		default:
+			return drained, nil
+		}
+	}
+	return drained, nil
+}
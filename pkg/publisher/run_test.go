@@ -0,0 +1,51 @@
+package publisher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunGuard(t *testing.T) {
+	tests := []struct {
+		name       string
+		stopFirst  bool
+		wantBegins bool
+	}{
+		{
+			name:       "Test Stop waits for a running Start",
+			stopFirst:  false,
+			wantBegins: true,
+		},
+		{
+			name:       "Test Stop before Start cancels the run",
+			stopFirst:  true,
+			wantBegins: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var guard runGuard
+			guard.arm()
+			if tt.stopFirst {
+				guard.wait()
+				assert.Equal(t, tt.wantBegins, guard.begin())
+				return
+			}
+			assert.Equal(t, tt.wantBegins, guard.begin())
+			stopped := make(chan struct{})
+			go func() {
+				guard.wait()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+				t.Fatal("wait returned before the run ended")
+			case <-time.After(50 * time.Millisecond):
+			}
+			guard.end()
+			<-stopped
+		})
+	}
+}
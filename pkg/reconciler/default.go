@@ -0,0 +1,141 @@
+package reconciler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/sirupsen/logrus"
+)
+
+const nodesKey = "nodes"
+
+// Drift is an interface whose live impairments differ from the impairments in the config
+type Drift struct {
+	Node       string               `json:"node" yaml:"node"`
+	Interface  string               `json:"interface" yaml:"interface"`
+	Configured *command.Impairments `json:"configured,omitempty" yaml:"configured,omitempty"`
+	Live       *command.Impairments `json:"live,omitempty" yaml:"live,omitempty"`
+	Applied    bool                 `json:"applied" yaml:"applied"`
+	Error      string               `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type DefaultReconciler struct {
+	log           *logrus.Entry
+	config        config.Config
+	dryRun        bool
+	newViewer     func(node string) (impairments.Viewer, error)
+	newSetCommand func(node, interface_ string) (command.SetCommand, error)
+}
+
+// NewDefaultReconciler re-applies the configured impairments with the configured backend, with dryRun drift is only reported
+func NewDefaultReconciler(config config.Config, helper helpers.Helper, dryRun bool) *DefaultReconciler {
+	return &DefaultReconciler{
+		log:    logging.DefaultLogger.WithField("subsystem", subsystem),
+		config: config,
+		dryRun: dryRun,
+		newViewer: func(node string) (impairments.Viewer, error) {
+			showCommand, err := command.NewShowCommand(config.GetValue(command.BackendKey), node, config.GetValue(helper.GetDefaultClabNameKey()))
+			if err != nil {
+				return nil, err
+			}
+			return impairments.NewDefaultViewer(config, node, helper, showCommand), nil
+		},
+		newSetCommand: func(node, interface_ string) (command.SetCommand, error) {
+			return command.NewSetCommand(config.GetValue(command.BackendKey), node, interface_, config.GetValue(helper.GetDefaultClabNameKey()))
+		},
+	}
+}
+
+// getNodes returns all nodes with impairments in the config
+func (reconciler *DefaultReconciler) getNodes() ([]string, error) {
+	nodes := make(map[string]interface{})
+	if err := reconciler.config.Unmarshal(nodesKey, &nodes); err != nil {
+		return nil, fmt.Errorf("Unable to read nodes from config: %v", err)
+	}
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// apply sets the configured impairments, an interface without configured impairments is reset
+func (reconciler *DefaultReconciler) apply(drift Drift) error {
+	setCommand, err := reconciler.newSetCommand(drift.Node, drift.Interface)
+	if err != nil {
+		return err
+	}
+	if drift.Configured == nil {
+		return setCommand.DeleteImpairments()
+	}
+	setCommand.AddDelay(drift.Configured.Delay)
+	setCommand.AddJitter(drift.Configured.Jitter)
+	setCommand.AddLoss(drift.Configured.Loss)
+	setCommand.AddRate(drift.Configured.Rate)
+	return setCommand.ApplyImpairments()
+}
+
+// reconcileNode holds the scenario lock while comparing and re-applying, so it never sees the config of a transaction in progress
+func (reconciler *DefaultReconciler) reconcileNode(node string) ([]Drift, error) {
+	scenario.Lock()
+	defer scenario.Unlock()
+	viewer, err := reconciler.newViewer(node)
+	if err != nil {
+		return nil, err
+	}
+	records, err := viewer.GetImpairments()
+	if err != nil {
+		return nil, err
+	}
+	var drifts []Drift
+	for _, record := range records {
+		if record.InSync {
+			continue
+		}
+		drift := Drift{Node: node, Interface: record.Interface, Configured: record.Configured, Live: record.Live}
+		if !reconciler.dryRun {
+			if err := reconciler.apply(drift); err != nil {
+				drift.Error = err.Error()
+				reconciler.log.Errorf("Unable to re-apply impairments of %s on node %s: %v\n", drift.Interface, node, err)
+			} else {
+				drift.Applied = true
+				reconciler.log.Infof("Re-applied impairments of %s on node %s\n", drift.Interface, node)
+			}
+		} else {
+			reconciler.log.Warnf("Impairments of %s on node %s differ from the config\n", drift.Interface, node)
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+// Reconcile compares the config with the live impairments of the node or of all configured nodes if node is empty,
+// errors of a node do not stop the reconciliation of the remaining nodes
+func (reconciler *DefaultReconciler) Reconcile(node string) ([]Drift, error) {
+	nodes := []string{node}
+	if node == "" {
+		var err error
+		if nodes, err = reconciler.getNodes(); err != nil {
+			return nil, err
+		}
+	}
+	var drifts []Drift
+	var errs []error
+	for _, node := range nodes {
+		nodeDrifts, err := reconciler.reconcileNode(node)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Unable to reconcile node %s: %v", node, err))
+			continue
+		}
+		drifts = append(drifts, nodeDrifts...)
+	}
+	return drifts, errors.Join(errs...)
+}
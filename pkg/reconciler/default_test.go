@@ -0,0 +1,179 @@
+package reconciler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeViewer struct {
+	records []impairments.Record
+	err     error
+}
+
+func (viewer fakeViewer) GetImpairments() ([]impairments.Record, error) {
+	return viewer.records, viewer.err
+}
+
+func TestNewDefaultReconciler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reconciler := NewDefaultReconciler(config.NewMockConfig(ctrl), helpers.NewDefaultHelper(), false)
+	assert.NotNil(t, reconciler)
+}
+
+func TestDefaultReconciler_Reconcile(t *testing.T) {
+	configured := &command.Impairments{Delay: 10, Jitter: 2, Loss: 5, Rate: 100000}
+	tests := []struct {
+		name      string
+		node      string
+		dryRun    bool
+		nodes     map[string]interface{}
+		nodesErr  error
+		viewers   map[string]fakeViewer
+		setErr    error
+		wantSet   []string
+		wantReset []string
+		want      []Drift
+		wantErr   bool
+	}{
+		{
+			name: "Test reconcile single node in sync",
+			node: "XR-1",
+			viewers: map[string]fakeViewer{
+				"XR-1": {records: []impairments.Record{{Interface: "Gi0-0-0-0", Configured: configured, Live: configured, InSync: true}}},
+			},
+			want: nil,
+		},
+		{
+			name: "Test reconcile re-applies missing impairments",
+			node: "XR-1",
+			viewers: map[string]fakeViewer{
+				"XR-1": {records: []impairments.Record{{Interface: "Gi0-0-0-0", Configured: configured}}},
+			},
+			wantSet: []string{"XR-1/Gi0-0-0-0"},
+			want:    []Drift{{Node: "XR-1", Interface: "Gi0-0-0-0", Configured: configured, Applied: true}},
+		},
+		{
+			name: "Test reconcile removes impairments which are not configured",
+			node: "XR-1",
+			viewers: map[string]fakeViewer{
+				"XR-1": {records: []impairments.Record{{Interface: "Gi0-0-0-1", Live: configured}}},
+			},
+			wantReset: []string{"XR-1/Gi0-0-0-1"},
+			want:      []Drift{{Node: "XR-1", Interface: "Gi0-0-0-1", Live: configured, Applied: true}},
+		},
+		{
+			name:   "Test reconcile dry run only reports drift",
+			node:   "XR-1",
+			dryRun: true,
+			viewers: map[string]fakeViewer{
+				"XR-1": {records: []impairments.Record{{Interface: "Gi0-0-0-0", Configured: configured}}},
+			},
+			want: []Drift{{Node: "XR-1", Interface: "Gi0-0-0-0", Configured: configured}},
+		},
+		{
+			name: "Test reconcile reports apply errors",
+			node: "XR-1",
+			viewers: map[string]fakeViewer{
+				"XR-1": {records: []impairments.Record{{Interface: "Gi0-0-0-0", Configured: configured}}},
+			},
+			wantSet: []string{"XR-1/Gi0-0-0-0"},
+			setErr:  fmt.Errorf("node not found"),
+			want:    []Drift{{Node: "XR-1", Interface: "Gi0-0-0-0", Configured: configured, Error: "node not found"}},
+		},
+		{
+			name:  "Test reconcile all configured nodes continues after errors",
+			nodes: map[string]interface{}{"XR-2": nil, "XR-1": nil},
+			viewers: map[string]fakeViewer{
+				"XR-1": {err: fmt.Errorf("lab not deployed")},
+				"XR-2": {records: []impairments.Record{{Interface: "Gi0-0-0-0", Configured: configured}}},
+			},
+			wantSet: []string{"XR-2/Gi0-0-0-0"},
+			want:    []Drift{{Node: "XR-2", Interface: "Gi0-0-0-0", Configured: configured, Applied: true}},
+			wantErr: true,
+		},
+		{
+			name:     "Test reconcile all nodes with invalid config",
+			nodesErr: fmt.Errorf("invalid nodes"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockConfig := config.NewMockConfig(ctrl)
+			if tt.node == "" {
+				mockConfig.EXPECT().Unmarshal(nodesKey, gomock.Any()).DoAndReturn(func(key string, value interface{}) error {
+					nodes := value.(*map[string]interface{})
+					for name := range tt.nodes {
+						(*nodes)[name] = nil
+					}
+					return tt.nodesErr
+				})
+			}
+			reconciler := NewDefaultReconciler(mockConfig, helpers.NewDefaultHelper(), tt.dryRun)
+			reconciler.newViewer = func(node string) (impairments.Viewer, error) {
+				return tt.viewers[node], nil
+			}
+			var set, reset []string
+			reconciler.newSetCommand = func(node, interface_ string) (command.SetCommand, error) {
+				setCommand := command.NewMockSetCommand(ctrl)
+				setCommand.EXPECT().AddDelay(configured.Delay).AnyTimes()
+				setCommand.EXPECT().AddJitter(configured.Jitter).AnyTimes()
+				setCommand.EXPECT().AddLoss(configured.Loss).AnyTimes()
+				setCommand.EXPECT().AddRate(configured.Rate).AnyTimes()
+				setCommand.EXPECT().ApplyImpairments().DoAndReturn(func() error {
+					set = append(set, node+"/"+interface_)
+					return tt.setErr
+				}).AnyTimes()
+				setCommand.EXPECT().DeleteImpairments().DoAndReturn(func() error {
+					reset = append(reset, node+"/"+interface_)
+					return tt.setErr
+				}).AnyTimes()
+				return setCommand, nil
+			}
+			drifts, err := reconciler.Reconcile(tt.node)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, drifts)
+			assert.Equal(t, tt.wantSet, set)
+			assert.Equal(t, tt.wantReset, reset)
+		})
+	}
+}
+
+func TestDefaultReconciler_Reconcile_DuringTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reconciler := NewDefaultReconciler(config.NewMockConfig(ctrl), helpers.NewDefaultHelper(), false)
+	viewed := make(chan struct{}, 1)
+	reconciler.newViewer = func(node string) (impairments.Viewer, error) {
+		viewed <- struct{}{}
+		return fakeViewer{records: []impairments.Record{{Interface: "Gi0-0-0-0", InSync: true}}}, nil
+	}
+	// an applier holds the lock for the whole transaction
+	scenario.Lock()
+	done := make(chan error)
+	go func() {
+		_, err := reconciler.Reconcile("XR-1")
+		done <- err
+	}()
+	select {
+	case <-viewed:
+		t.Fatal("reconciled node during a transaction")
+	case <-time.After(50 * time.Millisecond):
+	}
+	scenario.Unlock()
+	<-viewed
+	assert.NoError(t, <-done)
+}
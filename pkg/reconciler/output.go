@@ -0,0 +1,42 @@
+package reconciler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
+)
+
+func formatImpairments(values *command.Impairments) string {
+	if values == nil {
+		return "none"
+	}
+	return fmt.Sprintf("delay %s, jitter %s, loss %.2f%%, rate %d", impairments.FormatDuration(values.Delay), impairments.FormatDuration(values.Jitter), values.Loss, values.Rate)
+}
+
+func formatAction(drift Drift) string {
+	switch {
+	case drift.Error != "":
+		return "failed: " + drift.Error
+	case drift.Applied:
+		return "re-applied"
+	default:
+		return "drift"
+	}
+}
+
+// WriteDrifts renders the drifted interfaces in the given output format
+func WriteDrifts(writer io.Writer, drifts []Drift, format string) error {
+	if drifts == nil {
+		drifts = []Drift{}
+	}
+	return output.Write(writer, format, drifts, func() [][]string {
+		table := [][]string{{"Node", "Interface", "Configured", "Live", "Action"}}
+		for _, drift := range drifts {
+			table = append(table, []string{drift.Node, drift.Interface, formatImpairments(drift.Configured), formatImpairments(drift.Live), formatAction(drift)})
+		}
+		return table
+	})
+}
@@ -0,0 +1,50 @@
+package reconciler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDrifts(t *testing.T) {
+	drifts := []Drift{
+		{Node: "XR-1", Interface: "Gi0-0-0-0", Configured: &command.Impairments{Delay: 10, Loss: 5, Rate: 100000}, Applied: true},
+		{Node: "XR-2", Interface: "Gi0-0-0-1", Live: &command.Impairments{Jitter: 2}},
+		{Node: "XR-3", Interface: "Gi0-0-0-2", Configured: &command.Impairments{Delay: 1}, Error: "node not found"},
+	}
+	tests := []struct {
+		name   string
+		drifts []Drift
+		format string
+		want   string
+	}{
+		{
+			name:   "Test table output",
+			drifts: drifts,
+			format: output.Table,
+			want: "+------+-----------+------------------------------------------------+------------------------------------------+------------------------+\n" +
+				"| Node | Interface | Configured                                     | Live                                     | Action                 |\n" +
+				"+------+-----------+------------------------------------------------+------------------------------------------+------------------------+\n" +
+				"| XR-1 | Gi0-0-0-0 | delay 10ms, jitter 0s, loss 5.00%, rate 100000 | none                                     | re-applied             |\n" +
+				"| XR-2 | Gi0-0-0-1 | none                                           | delay 0s, jitter 2ms, loss 0.00%, rate 0 | drift                  |\n" +
+				"| XR-3 | Gi0-0-0-2 | delay 1ms, jitter 0s, loss 0.00%, rate 0       | none                                     | failed: node not found |\n" +
+				"+------+-----------+------------------------------------------------+------------------------------------------+------------------------+\n",
+		},
+		{
+			name:   "Test JSON output without drift",
+			drifts: nil,
+			format: output.JSON,
+			want:   "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			assert.NoError(t, WriteDrifts(&buffer, tt.drifts, tt.format))
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package reconciler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// PeriodicReconciler reconciles all configured nodes in a fixed interval until it is stopped
+type PeriodicReconciler struct {
+	log        *logrus.Entry
+	reconciler Reconciler
+	interval   time.Duration
+	quitChan   chan struct{}
+	stopOnce   sync.Once
+}
+
+func NewPeriodicReconciler(reconciler Reconciler, interval time.Duration) *PeriodicReconciler {
+	return &PeriodicReconciler{
+		log:        logging.DefaultLogger.WithField("subsystem", subsystem),
+		reconciler: reconciler,
+		interval:   interval,
+		quitChan:   make(chan struct{}),
+	}
+}
+
+func (periodic *PeriodicReconciler) reconcile() {
+	drifts, err := periodic.reconciler.Reconcile("")
+	if err != nil {
+		periodic.log.Errorln(err)
+	}
+	periodic.log.Debugf("Reconciliation finished with %d drifted interfaces\n", len(drifts))
+}
+
+// Start reconciles immediately and then on every tick, it blocks until Stop is called
+func (periodic *PeriodicReconciler) Start() {
+	periodic.log.Infof("Starting reconciliation every %s\n", periodic.interval)
+	ticker := time.NewTicker(periodic.interval)
+	defer ticker.Stop()
+	periodic.reconcile()
+	for {
+		select {
+		case <-periodic.quitChan:
+			return
+		case <-ticker.C:
+			periodic.reconcile()
+		}
+	}
+}
+
+func (periodic *PeriodicReconciler) Stop() {
+	periodic.log.Infoln("Stopping reconciliation")
+	periodic.stopOnce.Do(func() { close(periodic.quitChan) })
+}
@@ -0,0 +1,40 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPeriodicReconciler_Start(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reconciler := NewMockReconciler(ctrl)
+	reconciled := make(chan struct{}, 10)
+	reconciler.EXPECT().Reconcile("").DoAndReturn(func(string) ([]Drift, error) {
+		reconciled <- struct{}{}
+		return nil, nil
+	}).MinTimes(2)
+	periodic := NewPeriodicReconciler(reconciler, 10*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		periodic.Start()
+		close(done)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-reconciled:
+		case <-time.After(time.Second):
+			t.Fatal("reconciliation was not triggered")
+		}
+	}
+	periodic.Stop()
+	periodic.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("periodic reconciler did not stop")
+	}
+	assert.NotNil(t, periodic)
+}
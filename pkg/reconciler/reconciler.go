@@ -0,0 +1,7 @@
+package reconciler
+
+var subsystem = "reconciler"
+
+type Reconciler interface {
+	Reconcile(node string) ([]Drift, error)
+}
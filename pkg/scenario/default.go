@@ -0,0 +1,204 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/command"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/sirupsen/logrus"
+)
+
+const nodesKey = "nodes"
+
+// transactionMutex serializes the transactions of all appliers, so changes of the HTTP API, the gRPC API and the scheduler
+// running in one process never interleave between reading the previous and writing the desired impairments
+var transactionMutex sync.Mutex
+
+// Lock waits until no transaction is in progress and blocks further transactions until Unlock is called.
+// Components changing the live impairments outside of an applier (e.g. the reconciler) hold it, since during a transaction
+// the config already contains values which are not applied yet or are rolled back later
+func Lock() {
+	transactionMutex.Lock()
+}
+
+func Unlock() {
+	transactionMutex.Unlock()
+}
+
+// configuredNode is the config layout of a node, see helpers.GetDefaultNodeConfigKey
+type configuredNode struct {
+	Config map[string]struct {
+		Impairments Impairments `koanf:"impairments"`
+	} `koanf:"config"`
+}
+
+// Change sets the impairments of an interface from the previously configured to the desired values
+type Change struct {
+	Node      string
+	Interface string
+	Previous  Impairments
+	Desired   Impairments
+}
+
+type DefaultApplier struct {
+	log       *logrus.Entry
+	config    config.Config
+	mapper    naming.Mapper
+	replace   bool
+	newSetter func(node, interface_ string) (impairments.Setter, error)
+}
+
+// NewDefaultApplier applies scenarios with the configured backend, with replace configured interfaces missing in the scenario are reset
+func NewDefaultApplier(config config.Config, helper helpers.Helper, mapper naming.Mapper, replace bool) *DefaultApplier {
+	return &DefaultApplier{
+		log:     logging.DefaultLogger.WithField("subsystem", subsystem),
+		config:  config,
+		mapper:  mapper,
+		replace: replace,
+		newSetter: func(node, interface_ string) (impairments.Setter, error) {
+			setCommand, err := command.NewSetCommand(config.GetValue(command.BackendKey), node, interface_, config.GetValue(helper.GetDefaultClabNameKey()))
+			if err != nil {
+				return nil, err
+			}
+			return impairments.NewDefaultSetter(config, node, interface_, helper, setCommand), nil
+		},
+	}
+}
+
+func (applier *DefaultApplier) getClabInterfaceName(name string) string {
+	clabName, err := applier.mapper.ToClabName(name)
+	if err != nil {
+		applier.log.Debugf("Use interface name %s as given: %v\n", name, err)
+		return name
+	}
+	return clabName
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// plan returns the changes ordered by node and interface
+func (applier *DefaultApplier) plan(scenario *Scenario) ([]Change, error) {
+	configured := make(map[string]configuredNode)
+	if err := applier.config.Unmarshal(nodesKey, &configured); err != nil {
+		return nil, fmt.Errorf("Unable to read impairments from config: %v", err)
+	}
+	var changes []Change
+	for _, node := range sortedKeys(scenario.Nodes) {
+		seen := make(map[string]string)
+		for _, name := range sortedKeys(scenario.Nodes[node]) {
+			interface_ := applier.getClabInterfaceName(name)
+			if other, ok := seen[interface_]; ok {
+				return nil, fmt.Errorf("Interfaces %s and %s of node %s are both mapped to %s", other, name, node, interface_)
+			}
+			seen[interface_] = name
+			changes = append(changes, Change{
+				Node:      node,
+				Interface: interface_,
+				Previous:  configured[node].Config[interface_].Impairments,
+				Desired:   scenario.Nodes[node][name],
+			})
+		}
+	}
+	if !applier.replace {
+		return changes, nil
+	}
+	for _, node := range sortedKeys(configured) {
+		for _, interface_ := range sortedKeys(configured[node].Config) {
+			previous := configured[node].Config[interface_].Impairments
+			if previous == (Impairments{}) || applier.contains(scenario.Nodes[node], interface_) {
+				continue
+			}
+			changes = append(changes, Change{Node: node, Interface: interface_, Previous: previous})
+		}
+	}
+	return changes, nil
+}
+
+func (applier *DefaultApplier) contains(interfaces map[string]Impairments, interface_ string) bool {
+	for name := range interfaces {
+		if applier.getClabInterfaceName(name) == interface_ {
+			return true
+		}
+	}
+	return false
+}
+
+// set stores the impairments in the config and applies them, zero values remove the impairments
+func (applier *DefaultApplier) set(node, interface_ string, values Impairments) error {
+	setter, err := applier.newSetter(node, interface_)
+	if err != nil {
+		return err
+	}
+	if err := setter.SetDelay(values.Delay); err != nil {
+		return err
+	}
+	if err := setter.SetJitter(values.Jitter); err != nil {
+		return err
+	}
+	if err := setter.SetLoss(values.Loss); err != nil {
+		return err
+	}
+	if err := setter.SetRate(values.Rate); err != nil {
+		return err
+	}
+	if err := setter.SetBackgroundLoad(values.BackgroundLoad); err != nil {
+		return err
+	}
+	return setter.ApplyImpairments()
+}
+
+// rollback restores the previous impairments in reverse order
+func (applier *DefaultApplier) rollback(changes []Change) error {
+	var errs []error
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		applier.log.Infof("Roll back impairments of %s on node %s\n", change.Interface, change.Node)
+		if err := applier.set(change.Node, change.Interface, change.Previous); err != nil {
+			errs = append(errs, fmt.Errorf("Unable to roll back %s on node %s: %v", change.Interface, change.Node, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (applier *DefaultApplier) rollbackError(err error, changes []Change) error {
+	if rollbackErr := applier.rollback(changes); rollbackErr != nil {
+		return fmt.Errorf("%v, rollback failed: %v", err, rollbackErr)
+	}
+	return fmt.Errorf("%v, all changes are rolled back", err)
+}
+
+// Apply applies all interfaces of the scenario, the config is only written if all interfaces are applied,
+// otherwise the already applied interfaces are restored
+func (applier *DefaultApplier) Apply(scenario *Scenario) ([]Change, error) {
+	Lock()
+	defer Unlock()
+	changes, err := applier.plan(scenario)
+	if err != nil {
+		return nil, err
+	}
+	for i, change := range changes {
+		applier.log.Debugf("Apply impairments of %s on node %s\n", change.Interface, change.Node)
+		if err := applier.set(change.Node, change.Interface, change.Desired); err != nil {
+			// the failed interface may be partially applied and is restored as well
+			return nil, applier.rollbackError(fmt.Errorf("Unable to apply impairments of %s on node %s: %v", change.Interface, change.Node, err), changes[:i+1])
+		}
+	}
+	if err := applier.config.WriteConfig(); err != nil {
+		return nil, applier.rollbackError(fmt.Errorf("Unable to write config: %v", err), changes)
+	}
+	return changes, nil
+}
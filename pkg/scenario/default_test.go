@@ -0,0 +1,159 @@
+package scenario
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/impairments"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var defaultMapper, _ = naming.NewDefaultMapper(nil)
+
+func TestNewDefaultApplier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	assert.NotNil(t, NewDefaultApplier(config.NewMockConfig(ctrl), helpers.NewDefaultHelper(), defaultMapper, false))
+}
+
+func configuredNodes(interfaces map[string]map[string]Impairments) map[string]configuredNode {
+	nodes := make(map[string]configuredNode)
+	for node, values := range interfaces {
+		configured := configuredNode{Config: make(map[string]struct {
+			Impairments Impairments `koanf:"impairments"`
+		})}
+		for interface_, impairments := range values {
+			entry := configured.Config[interface_]
+			entry.Impairments = impairments
+			configured.Config[interface_] = entry
+		}
+		nodes[node] = configured
+	}
+	return nodes
+}
+
+func TestDefaultApplier_Apply(t *testing.T) {
+	previous := Impairments{Delay: 5}
+	desired := Impairments{Delay: 10, Jitter: 2, Loss: 5, Rate: 100000, BackgroundLoad: 20}
+	tests := []struct {
+		name       string
+		scenario   *Scenario
+		configured map[string]map[string]Impairments
+		replace    bool
+		failApply  string
+		writeErr   error
+		wantSet    []string
+		want       []Change
+		wantErr    bool
+	}{
+		{
+			name: "Test apply scenario and write config once",
+			scenario: &Scenario{Nodes: map[string]map[string]Impairments{
+				"XR-2": {"Gi0-0-0-0": desired},
+				"XR-1": {"GigabitEthernet0/0/0/1": desired},
+			}},
+			configured: map[string]map[string]Impairments{"XR-1": {"Gi0-0-0-1": previous}},
+			wantSet:    []string{"XR-1/Gi0-0-0-1=10", "XR-2/Gi0-0-0-0=10"},
+			want: []Change{
+				{Node: "XR-1", Interface: "Gi0-0-0-1", Previous: previous, Desired: desired},
+				{Node: "XR-2", Interface: "Gi0-0-0-0", Desired: desired},
+			},
+		},
+		{
+			name: "Test apply scenario with replace resets other configured interfaces",
+			scenario: &Scenario{Nodes: map[string]map[string]Impairments{
+				"XR-1": {"Gi0-0-0-0": desired},
+			}},
+			configured: map[string]map[string]Impairments{
+				"XR-1": {"Gi0-0-0-0": previous, "Gi0-0-0-1": previous, "Gi0-0-0-2": {}},
+				"XR-3": {"Gi0-0-0-0": previous},
+			},
+			replace: true,
+			wantSet: []string{"XR-1/Gi0-0-0-0=10", "XR-1/Gi0-0-0-1=0", "XR-3/Gi0-0-0-0=0"},
+			want: []Change{
+				{Node: "XR-1", Interface: "Gi0-0-0-0", Previous: previous, Desired: desired},
+				{Node: "XR-1", Interface: "Gi0-0-0-1", Previous: previous},
+				{Node: "XR-3", Interface: "Gi0-0-0-0", Previous: previous},
+			},
+		},
+		{
+			name: "Test failed interface rolls back all applied interfaces",
+			scenario: &Scenario{Nodes: map[string]map[string]Impairments{
+				"XR-1": {"Gi0-0-0-0": desired},
+				"XR-2": {"Gi0-0-0-0": desired},
+				"XR-3": {"Gi0-0-0-0": desired},
+			}},
+			configured: map[string]map[string]Impairments{"XR-1": {"Gi0-0-0-0": previous}},
+			failApply:  "XR-2/Gi0-0-0-0",
+			wantSet:    []string{"XR-1/Gi0-0-0-0=10", "XR-2/Gi0-0-0-0=10", "XR-2/Gi0-0-0-0=0", "XR-1/Gi0-0-0-0=5"},
+			wantErr:    true,
+		},
+		{
+			name: "Test failed config write rolls back all interfaces",
+			scenario: &Scenario{Nodes: map[string]map[string]Impairments{
+				"XR-1": {"Gi0-0-0-0": desired},
+			}},
+			writeErr: fmt.Errorf("read-only file system"),
+			wantSet:  []string{"XR-1/Gi0-0-0-0=10", "XR-1/Gi0-0-0-0=0"},
+			wantErr:  true,
+		},
+		{
+			name: "Test interfaces mapped to the same name",
+			scenario: &Scenario{Nodes: map[string]map[string]Impairments{
+				"XR-1": {"Gi0-0-0-0": desired, "GigabitEthernet0/0/0/0": desired},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockConfig := config.NewMockConfig(ctrl)
+			mockConfig.EXPECT().Unmarshal(nodesKey, gomock.Any()).DoAndReturn(func(key string, value interface{}) error {
+				for node, configured := range configuredNodes(tt.configured) {
+					(*value.(*map[string]configuredNode))[node] = configured
+				}
+				return nil
+			})
+			if tt.want != nil || tt.writeErr != nil {
+				mockConfig.EXPECT().WriteConfig().Return(tt.writeErr)
+			}
+			applier := NewDefaultApplier(mockConfig, helpers.NewDefaultHelper(), defaultMapper, tt.replace)
+			var set []string
+			failed := false
+			applier.newSetter = func(node, interface_ string) (impairments.Setter, error) {
+				setter := impairments.NewMockSetter(ctrl)
+				var delay uint64
+				setter.EXPECT().SetDelay(gomock.Any()).DoAndReturn(func(value uint64) error {
+					delay = value
+					return nil
+				})
+				setter.EXPECT().SetJitter(gomock.Any()).Return(nil)
+				setter.EXPECT().SetLoss(gomock.Any()).Return(nil)
+				setter.EXPECT().SetRate(gomock.Any()).Return(nil)
+				setter.EXPECT().SetBackgroundLoad(gomock.Any()).Return(nil)
+				setter.EXPECT().ApplyImpairments().DoAndReturn(func() error {
+					key := node + "/" + interface_
+					set = append(set, fmt.Sprintf("%s=%d", key, delay))
+					if key == tt.failApply && !failed {
+						failed = true
+						return fmt.Errorf("netem failed")
+					}
+					return nil
+				})
+				return setter, nil
+			}
+			changes, err := applier.Apply(tt.scenario)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, changes)
+			assert.Equal(t, tt.wantSet, set)
+		})
+	}
+}
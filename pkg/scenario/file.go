@@ -0,0 +1,70 @@
+package scenario
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Impairments of an interface: delay and jitter in ms, loss and background load in %, rate in kbit/s
+type Impairments struct {
+	Delay          uint64  `json:"delay,omitempty" yaml:"delay,omitempty" koanf:"delay"`
+	Jitter         uint64  `json:"jitter,omitempty" yaml:"jitter,omitempty" koanf:"jitter"`
+	Loss           float64 `json:"loss,omitempty" yaml:"loss,omitempty" koanf:"loss"`
+	Rate           uint64  `json:"rate,omitempty" yaml:"rate,omitempty" koanf:"rate"`
+	BackgroundLoad float64 `json:"background_load,omitempty" yaml:"background-load,omitempty" koanf:"background-load"`
+}
+
+// Scenario contains the impairments of the interfaces of several nodes, interfaces are given by the containerlab or telemetry name
+type Scenario struct {
+	Nodes map[string]map[string]Impairments `yaml:"nodes"`
+}
+
+// Validate checks the percentages, the remaining values are valid for every number
+func (impairments Impairments) Validate() error {
+	if impairments.Loss < 0 || impairments.Loss > 100 {
+		return fmt.Errorf("Loss %f is not between 0 and 100%%", impairments.Loss)
+	}
+	if impairments.BackgroundLoad < 0 || impairments.BackgroundLoad > 100 {
+		return fmt.Errorf("Background load %f is not between 0 and 100%%", impairments.BackgroundLoad)
+	}
+	return nil
+}
+
+func (scenario *Scenario) validate() error {
+	if len(scenario.Nodes) == 0 {
+		return fmt.Errorf("Scenario does not contain any nodes")
+	}
+	for node, interfaces := range scenario.Nodes {
+		for interface_, impairments := range interfaces {
+			if err := impairments.Validate(); err != nil {
+				return fmt.Errorf("Invalid impairments of %s on node %s: %v", interface_, node, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseScenario decodes a YAML scenario, unknown keys are rejected to catch typos before anything is applied
+func ParseScenario(data []byte) (*Scenario, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	scenario := &Scenario{}
+	if err := decoder.Decode(scenario); err != nil {
+		return nil, fmt.Errorf("Invalid scenario: %v", err)
+	}
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func LoadScenario(fileName string) (*Scenario, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read scenario: %v", err)
+	}
+	return ParseScenario(data)
+}
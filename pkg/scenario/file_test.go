@@ -0,0 +1,74 @@
+package scenario
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScenario(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Scenario
+		wantErr bool
+	}{
+		{
+			name: "Test valid scenario",
+			data: "nodes:\n  XR-1:\n    GigabitEthernet0/0/0/0:\n      delay: 10\n      jitter: 2\n      loss: 5\n      rate: 100000\n      background-load: 20\n  XR-2:\n    Gi0-0-0-1: {}\n",
+			want: &Scenario{Nodes: map[string]map[string]Impairments{
+				"XR-1": {"GigabitEthernet0/0/0/0": {Delay: 10, Jitter: 2, Loss: 5, Rate: 100000, BackgroundLoad: 20}},
+				"XR-2": {"Gi0-0-0-1": {}},
+			}},
+		},
+		{
+			name:    "Test scenario with unknown key",
+			data:    "nodes:\n  XR-1:\n    Gi0-0-0-0:\n      delays: 10\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test scenario without nodes",
+			data:    "nodes: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test scenario with invalid loss",
+			data:    "nodes:\n  XR-1:\n    Gi0-0-0-0:\n      loss: 101\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test scenario with invalid background load",
+			data:    "nodes:\n  XR-1:\n    Gi0-0-0-0:\n      background-load: -1\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid YAML",
+			data:    "nodes: [",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario, err := ParseScenario([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, scenario)
+		})
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	directory := t.TempDir()
+	fileName := filepath.Join(directory, "scenario.yaml")
+	assert.NoError(t, os.WriteFile(fileName, []byte("nodes:\n  XR-1:\n    Gi0-0-0-0:\n      delay: 10\n"), 0644))
+	scenario, err := LoadScenario(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), scenario.Nodes["XR-1"]["Gi0-0-0-0"].Delay)
+	_, err = LoadScenario(filepath.Join(directory, "missing.yaml"))
+	assert.Error(t, err)
+}
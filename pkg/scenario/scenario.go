@@ -0,0 +1,7 @@
+package scenario
+
+var subsystem = "scenario"
+
+type Applier interface {
+	Apply(*Scenario) ([]Change, error)
+}
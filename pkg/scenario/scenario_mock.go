@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: scenario.go
+//
+// Generated by this command:
+//
+//	mockgen -source=scenario.go -destination=scenario_mock.go -package=scenario
+//
+
+// Package scenario is a generated GoMock package.
+package scenario
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockApplier is a mock of Applier interface.
+type MockApplier struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplierMockRecorder
+}
+
+// MockApplierMockRecorder is the mock recorder for MockApplier.
+type MockApplierMockRecorder struct {
+	mock *MockApplier
+}
+
+// NewMockApplier creates a new mock instance.
+func NewMockApplier(ctrl *gomock.Controller) *MockApplier {
+	mock := &MockApplier{ctrl: ctrl}
+	mock.recorder = &MockApplierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplier) EXPECT() *MockApplierMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockApplier) Apply(arg0 *Scenario) ([]Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", arg0)
+	ret0, _ := ret[0].([]Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockApplierMockRecorder) Apply(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockApplier)(nil).Apply), arg0)
+}
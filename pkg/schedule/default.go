@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/sirupsen/logrus"
+)
+
+type scheduledInterface struct {
+	node       string
+	interface_ string
+	profile    profile
+}
+
+type DefaultScheduler struct {
+	log        *logrus.Entry
+	interfaces []scheduledInterface
+	applier    scenario.Applier
+	quitChan   chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewDefaultScheduler applies the schedule with the configured backend, interface names are mapped like in set
+func NewDefaultScheduler(config config.Config, helper helpers.Helper, mapper naming.Mapper, schedule *Schedule) (*DefaultScheduler, error) {
+	scheduler := &DefaultScheduler{
+		log:      logging.DefaultLogger.WithField("subsystem", subsystem),
+		applier:  scenario.NewDefaultApplier(config, helper, mapper, false),
+		quitChan: make(chan struct{}),
+	}
+	for i := range schedule.Entries {
+		entry := &schedule.Entries[i]
+		profile, err := entry.createProfile()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid schedule entry %d: %v", i+1, err)
+		}
+		interface_, err := mapper.ToClabName(entry.Interface)
+		if err != nil {
+			scheduler.log.Debugf("Use interface name %s as given: %v\n", entry.Interface, err)
+			interface_ = entry.Interface
+		}
+		scheduler.interfaces = append(scheduler.interfaces, scheduledInterface{node: entry.Node, interface_: interface_, profile: profile})
+	}
+	return scheduler, nil
+}
+
+// apply sets the impairments in one transaction of the applier, which writes the config so the service publishes the new
+// impairments. The transaction is serialized with the changes of the HTTP and gRPC API running in the same process
+func (scheduler *DefaultScheduler) apply(scheduled scheduledInterface, values scenario.Impairments) error {
+	_, err := scheduler.applier.Apply(&scenario.Scenario{Nodes: map[string]map[string]scenario.Impairments{scheduled.node: {scheduled.interface_: values}}})
+	return err
+}
+
+// wait returns false if the scheduler is stopped before the deadline
+func (scheduler *DefaultScheduler) wait(deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-scheduler.quitChan:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// nextChange returns the index of the interface with the earliest change after its cursor, -1 if all profiles have ended
+func (scheduler *DefaultScheduler) nextChange(cursors []time.Duration) (int, time.Duration, scenario.Impairments) {
+	index, next, nextValues := -1, time.Duration(0), scenario.Impairments{}
+	for i, scheduled := range scheduler.interfaces {
+		at, values, ok := scheduled.profile.next(cursors[i])
+		if ok && (index == -1 || at < next) {
+			index, next, nextValues = i, at, values
+		}
+	}
+	return index, next, nextValues
+}
+
+// Run applies all changes at their offset after the start and returns once all profiles have ended or the scheduler is stopped,
+// failed changes are logged and do not stop the schedule
+func (scheduler *DefaultScheduler) Run() error {
+	start := time.Now()
+	cursors := make([]time.Duration, len(scheduler.interfaces))
+	failures := 0
+	for {
+		index, at, values := scheduler.nextChange(cursors)
+		if index == -1 {
+			scheduler.log.Infoln("Schedule finished")
+			break
+		}
+		if !scheduler.wait(start.Add(at)) {
+			scheduler.log.Infoln("Schedule stopped")
+			break
+		}
+		scheduled := scheduler.interfaces[index]
+		if err := scheduler.apply(scheduled, values); err != nil {
+			failures++
+			scheduler.log.Errorf("Unable to set impairments of %s on node %s at %s: %v\n", scheduled.interface_, scheduled.node, at, err)
+		} else {
+			scheduler.log.Infof("Set impairments of %s on node %s at %s: delay %dms, jitter %dms, loss %.2f%%, rate %d kbit/s, background load %.2f%%\n", scheduled.interface_, scheduled.node, at, values.Delay, values.Jitter, values.Loss, values.Rate, values.BackgroundLoad)
+		}
+		cursors[index] = at + 1
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d scheduled changes failed", failures)
+	}
+	return nil
+}
+
+// Start runs the schedule as task of the service
+func (scheduler *DefaultScheduler) Start() {
+	if err := scheduler.Run(); err != nil {
+		scheduler.log.Errorln(err)
+	}
+}
+
+func (scheduler *DefaultScheduler) Stop() {
+	scheduler.stopOnce.Do(func() { close(scheduler.quitChan) })
+}
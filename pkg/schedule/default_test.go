@@ -0,0 +1,128 @@
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/naming"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var defaultMapper, _ = naming.NewDefaultMapper(nil)
+
+func TestNewDefaultScheduler(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "Test scheduler maps interface names",
+			schedule: &Schedule{Entries: []Entry{
+				{Node: "XR-1", Interface: "GigabitEthernet0/0/0/1", Profile: StepProfile, Steps: []Step{{At: time.Second}}},
+				{Node: "XR-1", Interface: "eth1", Profile: StepProfile, Steps: []Step{{At: time.Second}}},
+			}},
+			want: []string{"Gi0-0-0-1", "eth1"},
+		},
+		{
+			name:     "Test scheduler with invalid entry",
+			schedule: &Schedule{Entries: []Entry{{Node: "XR-1", Interface: "Gi0-0-0-0", Profile: RampProfile}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			scheduler, err := NewDefaultScheduler(config.NewMockConfig(ctrl), helpers.NewDefaultHelper(), defaultMapper, tt.schedule)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var interfaces []string
+			for _, scheduled := range scheduler.interfaces {
+				interfaces = append(interfaces, scheduled.interface_)
+			}
+			assert.Equal(t, tt.want, interfaces)
+		})
+	}
+}
+
+func TestDefaultScheduler_Run(t *testing.T) {
+	degraded := scenario.Impairments{Delay: 50}
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		applyErr error
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "Test run applies the changes of all interfaces in order",
+			schedule: &Schedule{Entries: []Entry{
+				{Node: "XR-1", Interface: "Gi0-0-0-0", Profile: StepProfile, Steps: []Step{{At: 20 * time.Millisecond}, {At: 0, Impairments: degraded}}},
+				{Node: "XR-2", Interface: "Gi0-0-0-0", Profile: FlapProfile, Start: 5 * time.Millisecond, Period: 10 * time.Millisecond, Duration: 5 * time.Millisecond, Count: 1, Impairments: degraded},
+			}},
+			want: []string{"XR-1/Gi0-0-0-0=50", "XR-2/Gi0-0-0-0=50", "XR-2/Gi0-0-0-0=0", "XR-1/Gi0-0-0-0=0"},
+		},
+		{
+			name: "Test run continues after failed changes",
+			schedule: &Schedule{Entries: []Entry{
+				{Node: "XR-1", Interface: "Gi0-0-0-0", Profile: StepProfile, Steps: []Step{{At: 0, Impairments: degraded}, {At: time.Millisecond}}},
+			}},
+			applyErr: fmt.Errorf("node not found"),
+			want:     []string{"XR-1/Gi0-0-0-0=50", "XR-1/Gi0-0-0-0=0"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			scheduler, err := NewDefaultScheduler(config.NewMockConfig(ctrl), helpers.NewDefaultHelper(), defaultMapper, tt.schedule)
+			assert.NoError(t, err)
+			var applied []string
+			applier := scenario.NewMockApplier(ctrl)
+			applier.EXPECT().Apply(gomock.Any()).DoAndReturn(func(changes *scenario.Scenario) ([]scenario.Change, error) {
+				for node, interfaces := range changes.Nodes {
+					for interface_, values := range interfaces {
+						applied = append(applied, fmt.Sprintf("%s/%s=%d", node, interface_, values.Delay))
+					}
+				}
+				return nil, tt.applyErr
+			}).AnyTimes()
+			scheduler.applier = applier
+			err = scheduler.Run()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, applied)
+		})
+	}
+}
+
+func TestDefaultScheduler_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	schedule := &Schedule{Entries: []Entry{
+		{Node: "XR-1", Interface: "Gi0-0-0-0", Profile: FlapProfile, Start: time.Hour, Period: 2 * time.Hour, Duration: time.Hour},
+	}}
+	scheduler, err := NewDefaultScheduler(config.NewMockConfig(ctrl), helpers.NewDefaultHelper(), defaultMapper, schedule)
+	assert.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scheduler.Start()
+	}()
+	scheduler.Stop()
+	scheduler.Stop()
+	wg.Wait()
+}
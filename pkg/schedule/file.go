@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"gopkg.in/yaml.v3"
+)
+
+// Step sets the impairments at an offset after the start of the entry
+type Step struct {
+	At                   time.Duration `yaml:"at"`
+	scenario.Impairments `yaml:",inline"`
+}
+
+// Entry changes the impairments of one interface according to its profile, durations are given like 30s or 5m
+type Entry struct {
+	Node      string        `yaml:"node"`
+	Interface string        `yaml:"interface"`
+	Profile   string        `yaml:"profile"`
+	Start     time.Duration `yaml:"start"`
+	// step profile
+	Steps []Step `yaml:"steps"`
+	// ramp profile, duration is also the impaired time per period of the flap profile
+	Duration time.Duration        `yaml:"duration"`
+	Interval time.Duration        `yaml:"interval"`
+	From     scenario.Impairments `yaml:"from"`
+	To       scenario.Impairments `yaml:"to"`
+	// flap profile
+	Period      time.Duration        `yaml:"period"`
+	Count       int                  `yaml:"count"`
+	Impairments scenario.Impairments `yaml:"impairments"`
+	Recovered   scenario.Impairments `yaml:"recovered"`
+}
+
+type Schedule struct {
+	Entries []Entry `yaml:"schedules"`
+}
+
+func validateImpairments(values ...scenario.Impairments) error {
+	for _, value := range values {
+		if err := value.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (entry *Entry) createStepProfile() (profile, error) {
+	if len(entry.Steps) == 0 {
+		return nil, fmt.Errorf("Step profile requires at least one step")
+	}
+	steps := make([]Step, len(entry.Steps))
+	copy(steps, entry.Steps)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].At < steps[j].At })
+	for _, step := range steps {
+		if step.At < 0 {
+			return nil, fmt.Errorf("Step offset %s is negative", step.At)
+		}
+		if err := step.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &stepProfile{start: entry.Start, steps: steps}, nil
+}
+
+func (entry *Entry) createRampProfile() (profile, error) {
+	if entry.Duration <= 0 || entry.Interval <= 0 || entry.Interval > entry.Duration {
+		return nil, fmt.Errorf("Ramp profile requires a duration and an interval between 0 and the duration")
+	}
+	if err := validateImpairments(entry.From, entry.To); err != nil {
+		return nil, err
+	}
+	return &rampProfile{start: entry.Start, duration: entry.Duration, interval: entry.Interval, from: entry.From, to: entry.To}, nil
+}
+
+func (entry *Entry) createFlapProfile() (profile, error) {
+	if entry.Period <= 0 || entry.Duration <= 0 || entry.Duration >= entry.Period {
+		return nil, fmt.Errorf("Flap profile requires a period and a duration between 0 and the period")
+	}
+	if entry.Count < 0 {
+		return nil, fmt.Errorf("Flap count %d is negative", entry.Count)
+	}
+	if err := validateImpairments(entry.Impairments, entry.Recovered); err != nil {
+		return nil, err
+	}
+	return &flapProfile{start: entry.Start, period: entry.Period, duration: entry.Duration, count: entry.Count, impairments: entry.Impairments, recovered: entry.Recovered}, nil
+}
+
+func (entry *Entry) createProfile() (profile, error) {
+	if entry.Node == "" || entry.Interface == "" {
+		return nil, fmt.Errorf("Node and interface are required")
+	}
+	if entry.Start < 0 {
+		return nil, fmt.Errorf("Start %s is negative", entry.Start)
+	}
+	switch entry.Profile {
+	case StepProfile:
+		return entry.createStepProfile()
+	case RampProfile:
+		return entry.createRampProfile()
+	case FlapProfile:
+		return entry.createFlapProfile()
+	default:
+		return nil, fmt.Errorf("Unknown profile %q, use %s, %s or %s", entry.Profile, StepProfile, RampProfile, FlapProfile)
+	}
+}
+
+func (schedule *Schedule) validate() error {
+	if len(schedule.Entries) == 0 {
+		return fmt.Errorf("Schedule does not contain any entries")
+	}
+	for i := range schedule.Entries {
+		if _, err := schedule.Entries[i].createProfile(); err != nil {
+			return fmt.Errorf("Invalid schedule entry %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ParseSchedule decodes a YAML schedule, unknown keys are rejected
+func ParseSchedule(data []byte) (*Schedule, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	schedule := &Schedule{}
+	if err := decoder.Decode(schedule); err != nil {
+		return nil, fmt.Errorf("Invalid schedule: %v", err)
+	}
+	if err := schedule.validate(); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func LoadSchedule(fileName string) (*Schedule, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read schedule: %v", err)
+	}
+	return ParseSchedule(data)
+}
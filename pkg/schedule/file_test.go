@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Schedule
+		wantErr bool
+	}{
+		{
+			name: "Test valid schedule",
+			data: `schedules:
+  - node: XR-1
+    interface: GigabitEthernet0/0/0/0
+    profile: step
+    steps:
+      - at: 5m
+        delay: 50
+        loss: 2
+      - at: 10m
+  - node: XR-2
+    interface: Gi0-0-0-1
+    profile: ramp
+    start: 1m
+    duration: 4m
+    interval: 30s
+    from:
+      delay: 10
+    to:
+      delay: 100
+  - node: XR-3
+    interface: Gi0-0-0-0
+    profile: flap
+    period: 1m
+    duration: 10s
+    count: 5
+    impairments:
+      loss: 100
+`,
+			want: &Schedule{Entries: []Entry{
+				{Node: "XR-1", Interface: "GigabitEthernet0/0/0/0", Profile: StepProfile, Steps: []Step{
+					{At: 5 * time.Minute, Impairments: scenario.Impairments{Delay: 50, Loss: 2}},
+					{At: 10 * time.Minute},
+				}},
+				{Node: "XR-2", Interface: "Gi0-0-0-1", Profile: RampProfile, Start: time.Minute, Duration: 4 * time.Minute, Interval: 30 * time.Second, From: scenario.Impairments{Delay: 10}, To: scenario.Impairments{Delay: 100}},
+				{Node: "XR-3", Interface: "Gi0-0-0-0", Profile: FlapProfile, Period: time.Minute, Duration: 10 * time.Second, Count: 5, Impairments: scenario.Impairments{Loss: 100}},
+			}},
+		},
+		{
+			name:    "Test schedule without entries",
+			data:    "schedules: []\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test schedule with unknown key",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: step\n    steps:\n      - at: 1m\n        delays: 5\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test schedule with unknown profile",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: sine\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test schedule without interface",
+			data:    "schedules:\n  - node: XR-1\n    profile: step\n    steps:\n      - at: 1m\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test step profile without steps",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: step\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test ramp profile with interval larger than duration",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: ramp\n    duration: 1m\n    interval: 2m\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test flap profile with duration of the whole period",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: flap\n    period: 1m\n    duration: 1m\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test flap profile with invalid loss",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: flap\n    period: 1m\n    duration: 10s\n    impairments:\n      loss: 200\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid duration",
+			data:    "schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: flap\n    period: often\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, schedule)
+		})
+	}
+}
+
+func TestLoadSchedule(t *testing.T) {
+	directory := t.TempDir()
+	fileName := filepath.Join(directory, "schedule.yaml")
+	assert.NoError(t, os.WriteFile(fileName, []byte("schedules:\n  - node: XR-1\n    interface: Gi0-0-0-0\n    profile: step\n    steps:\n      - at: 1m\n        delay: 5\n"), 0644))
+	schedule, err := LoadSchedule(fileName)
+	assert.NoError(t, err)
+	assert.Len(t, schedule.Entries, 1)
+	_, err = LoadSchedule(filepath.Join(directory, "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"math"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+)
+
+const (
+	StepProfile = "step"
+	RampProfile = "ramp"
+	FlapProfile = "flap"
+)
+
+// profile describes how the impairments of an interface change over time, offsets are relative to the start of the schedule
+type profile interface {
+	// next returns the first change at or after offset, ok is false if the profile has no further changes
+	next(offset time.Duration) (at time.Duration, impairments scenario.Impairments, ok bool)
+}
+
+// stepProfile sets the impairments of each step at its offset, steps are sorted by offset
+type stepProfile struct {
+	start time.Duration
+	steps []Step
+}
+
+func (profile *stepProfile) next(offset time.Duration) (time.Duration, scenario.Impairments, bool) {
+	for _, step := range profile.steps {
+		if at := profile.start + step.At; at >= offset {
+			return at, step.Impairments, true
+		}
+	}
+	return 0, scenario.Impairments{}, false
+}
+
+// rampProfile changes the impairments linearly from from to to, in steps of interval
+type rampProfile struct {
+	start    time.Duration
+	duration time.Duration
+	interval time.Duration
+	from     scenario.Impairments
+	to       scenario.Impairments
+}
+
+func interpolateUint(from, to uint64, fraction float64) uint64 {
+	return uint64(math.Round(float64(from) + (float64(to)-float64(from))*fraction))
+}
+
+// interpolateFloat rounds to two decimals like the show output
+func interpolateFloat(from, to, fraction float64) float64 {
+	return math.Round((from+(to-from)*fraction)*100) / 100
+}
+
+func interpolate(from, to scenario.Impairments, fraction float64) scenario.Impairments {
+	return scenario.Impairments{
+		Delay:          interpolateUint(from.Delay, to.Delay, fraction),
+		Jitter:         interpolateUint(from.Jitter, to.Jitter, fraction),
+		Loss:           interpolateFloat(from.Loss, to.Loss, fraction),
+		Rate:           interpolateUint(from.Rate, to.Rate, fraction),
+		BackgroundLoad: interpolateFloat(from.BackgroundLoad, to.BackgroundLoad, fraction),
+	}
+}
+
+func (profile *rampProfile) next(offset time.Duration) (time.Duration, scenario.Impairments, bool) {
+	end := profile.start + profile.duration
+	if offset > end {
+		return 0, scenario.Impairments{}, false
+	}
+	at := profile.start
+	if offset > profile.start {
+		steps := (offset - profile.start + profile.interval - 1) / profile.interval
+		at = profile.start + steps*profile.interval
+		if at > end {
+			at = end
+		}
+	}
+	return at, interpolate(profile.from, profile.to, float64(at-profile.start)/float64(profile.duration)), true
+}
+
+// flapProfile sets the impairments at the beginning of each period and the recovered impairments after duration,
+// without count it repeats until the schedule is stopped
+type flapProfile struct {
+	start       time.Duration
+	period      time.Duration
+	duration    time.Duration
+	count       int
+	impairments scenario.Impairments
+	recovered   scenario.Impairments
+}
+
+func (profile *flapProfile) next(offset time.Duration) (time.Duration, scenario.Impairments, bool) {
+	if offset < profile.start {
+		offset = profile.start
+	}
+	cycle := (offset - profile.start) / profile.period
+	within := (offset - profile.start) % profile.period
+	impaired := true
+	switch {
+	case within == 0:
+	case within <= profile.duration:
+		impaired = false
+	default:
+		cycle++
+	}
+	if profile.count > 0 && cycle >= time.Duration(profile.count) {
+		return 0, scenario.Impairments{}, false
+	}
+	at := profile.start + cycle*profile.period
+	if !impaired {
+		return at + profile.duration, profile.recovered, true
+	}
+	return at, profile.impairments, true
+}
@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/scenario"
+	"github.com/stretchr/testify/assert"
+)
+
+type change struct {
+	at          time.Duration
+	impairments scenario.Impairments
+}
+
+// collectChanges returns all changes of a profile up to limit
+func collectChanges(profile profile, limit int) []change {
+	var changes []change
+	cursor := time.Duration(0)
+	for len(changes) < limit {
+		at, values, ok := profile.next(cursor)
+		if !ok {
+			break
+		}
+		changes = append(changes, change{at: at, impairments: values})
+		cursor = at + 1
+	}
+	return changes
+}
+
+func TestProfile_next(t *testing.T) {
+	degraded := scenario.Impairments{Delay: 50, Loss: 2}
+	down := scenario.Impairments{Loss: 100}
+	tests := []struct {
+		name    string
+		profile profile
+		limit   int
+		want    []change
+	}{
+		{
+			name: "Test step profile",
+			profile: &stepProfile{start: time.Minute, steps: []Step{
+				{At: 4 * time.Minute, Impairments: degraded},
+				{At: 9 * time.Minute},
+			}},
+			limit: 10,
+			want: []change{
+				{at: 5 * time.Minute, impairments: degraded},
+				{at: 10 * time.Minute},
+			},
+		},
+		{
+			name: "Test ramp profile ends at the target also if the duration is no multiple of the interval",
+			profile: &rampProfile{
+				start:    time.Minute,
+				duration: 50 * time.Second,
+				interval: 20 * time.Second,
+				from:     scenario.Impairments{Delay: 10, Loss: 1},
+				to:       scenario.Impairments{Delay: 110, Loss: 2},
+			},
+			limit: 10,
+			want: []change{
+				{at: time.Minute, impairments: scenario.Impairments{Delay: 10, Loss: 1}},
+				{at: 80 * time.Second, impairments: scenario.Impairments{Delay: 50, Loss: 1.4}},
+				{at: 100 * time.Second, impairments: scenario.Impairments{Delay: 90, Loss: 1.8}},
+				{at: 110 * time.Second, impairments: scenario.Impairments{Delay: 110, Loss: 2}},
+			},
+		},
+		{
+			name: "Test ramp profile downwards",
+			profile: &rampProfile{
+				duration: 2 * time.Second,
+				interval: time.Second,
+				from:     scenario.Impairments{Rate: 100000},
+				to:       scenario.Impairments{Rate: 1000},
+			},
+			limit: 10,
+			want: []change{
+				{at: 0, impairments: scenario.Impairments{Rate: 100000}},
+				{at: time.Second, impairments: scenario.Impairments{Rate: 50500}},
+				{at: 2 * time.Second, impairments: scenario.Impairments{Rate: 1000}},
+			},
+		},
+		{
+			name:    "Test flap profile with count",
+			profile: &flapProfile{start: 10 * time.Second, period: time.Minute, duration: 5 * time.Second, count: 2, impairments: down},
+			limit:   10,
+			want: []change{
+				{at: 10 * time.Second, impairments: down},
+				{at: 15 * time.Second},
+				{at: 70 * time.Second, impairments: down},
+				{at: 75 * time.Second},
+			},
+		},
+		{
+			name:    "Test flap profile without count repeats",
+			profile: &flapProfile{period: time.Minute, duration: 30 * time.Second, impairments: down, recovered: degraded},
+			limit:   5,
+			want: []change{
+				{at: 0, impairments: down},
+				{at: 30 * time.Second, impairments: degraded},
+				{at: time.Minute, impairments: down},
+				{at: 90 * time.Second, impairments: degraded},
+				{at: 2 * time.Minute, impairments: down},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, collectChanges(tt.profile, tt.limit))
+		})
+	}
+}
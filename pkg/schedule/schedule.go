@@ -0,0 +1,9 @@
+package schedule
+
+var subsystem = "schedule"
+
+type Scheduler interface {
+	Run() error
+	Start()
+	Stop()
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"sync"
+	"time"
 
+	"github.com/hawkv6/clab-telemetry-linker/pkg/backoff"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/consumer"
 	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
@@ -11,13 +13,55 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-type DefaultService struct {
-	log       *logrus.Entry
-	config    config.Config
-	consumer  consumer.Consumer
-	processor processor.Processor
+const (
+	consumerName  = "consumer"
+	processorName = "processor"
+	publisherName = "publisher"
+)
+
+const (
+	defaultInitialBackoff = 1 * time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// component is implemented by the consumer and the publisher which connect to Kafka
+type component interface {
+	Init() error
+	Start() error
+	Stop() error
+}
+
+// Task is a background job like the periodic reconciler, Start blocks until Stop is called
+type Task interface {
+	Start()
+	Stop()
+}
+
+type namedTask struct {
+	name string
+	task Task
+}
+
+type namedPublisher struct {
+	name      string
 	publisher publisher.Publisher
-	wg        sync.WaitGroup
+}
+
+type DefaultService struct {
+	log            *logrus.Entry
+	config         config.Config
+	consumer       consumer.Consumer
+	processor      processor.Processor
+	publisher      publisher.Publisher
+	wg             sync.WaitGroup
+	mutex          sync.Mutex
+	quitChan       chan struct{}
+	health         map[string]HealthState
+	active         map[string]component
+	tasks          []namedTask
+	sinks          []namedPublisher
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
 }
 
 func NewDefaultService(config config.Config, receiver consumer.Consumer, processor processor.Processor, publisher publisher.Publisher) *DefaultService {
@@ -28,32 +72,207 @@ func NewDefaultService(config config.Config, receiver consumer.Consumer, process
 		processor: processor,
 		publisher: publisher,
 		wg:        sync.WaitGroup{},
+		quitChan:  make(chan struct{}),
+		health: map[string]HealthState{
+			consumerName:  HealthStopped,
+			processorName: HealthStopped,
+			publisherName: HealthStopped,
+		},
+		active:         make(map[string]component),
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+}
+
+func (service *DefaultService) setHealth(name string, state HealthState) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	if service.health[name] != state {
+		service.log.Infof("%s is %s", name, state)
+		service.health[name] = state
+	}
+}
+
+// Health returns the worst health state of all components
+func (service *DefaultService) Health() HealthState {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	return aggregateHealth(service.health)
+}
+
+// ComponentHealth returns a copy of the health state of every component
+func (service *DefaultService) ComponentHealth() map[string]HealthState {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	health := make(map[string]HealthState, len(service.health))
+	for name, state := range service.health {
+		health[name] = state
+	}
+	return health
+}
+
+func (service *DefaultService) isQuitting() bool {
+	select {
+	case <-service.quitChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForRetry returns false if the service is stopped while waiting
+func (service *DefaultService) waitForRetry(interval time.Duration) bool {
+	select {
+	case <-service.quitChan:
+		return false
+	case <-time.After(interval):
+		return true
+	}
+}
+
+// activate registers an initialized component so that Stop closes it, it returns false if the service is already stopping
+func (service *DefaultService) activate(name string, component component) bool {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	if service.isQuitting() {
+		return false
+	}
+	service.active[name] = component
+	return true
+}
+
+// deactivate unregisters a component, it returns false if the service is already stopping and therefore closed the component
+func (service *DefaultService) deactivate(name string) bool {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	if service.isQuitting() {
+		return false
+	}
+	delete(service.active, name)
+	return true
+}
+
+func (service *DefaultService) releaseComponent(name string, component component) {
+	if err := component.Stop(); err != nil {
+		service.log.Debugf("Error releasing %s: %v", name, err)
 	}
 }
+
+// supervise initializes and runs the component until the service is stopped, connection errors are retried with exponential backoff
+func (service *DefaultService) supervise(name string, component component) {
+	defer service.wg.Done()
+	retryBackoff := backoff.NewExponentialBackoff(service.initialBackoff, service.maxBackoff)
+	for !service.isQuitting() {
+		if err := component.Init(); err != nil {
+			service.setHealth(name, HealthDegraded)
+			service.releaseComponent(name, component)
+			interval := retryBackoff.Next()
+			service.log.Warnf("Unable to initialize %s, retrying in %s: %v", name, interval, err)
+			if !service.waitForRetry(interval) {
+				break
+			}
+			continue
+		}
+		if !service.activate(name, component) {
+			service.releaseComponent(name, component)
+			break
+		}
+		retryBackoff.Reset()
+		service.setHealth(name, HealthHealthy)
+		err := component.Start()
+		if !service.deactivate(name) {
+			break
+		}
+		service.releaseComponent(name, component)
+		if err == nil {
+			break
+		}
+		service.setHealth(name, HealthDegraded)
+		interval := retryBackoff.Next()
+		service.log.Warnf("Lost connection of %s, reconnecting in %s: %v", name, interval, err)
+		if !service.waitForRetry(interval) {
+			break
+		}
+	}
+	service.setHealth(name, HealthStopped)
+}
+
+// AddTask registers a task which runs alongside the components, it has to be called before Start
+func (service *DefaultService) AddTask(name string, task Task) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.tasks = append(service.tasks, namedTask{name: name, task: task})
+	service.health[name] = HealthStopped
+}
+
+func (service *DefaultService) runTask(name string, task Task) {
+	defer service.wg.Done()
+	service.setHealth(name, HealthHealthy)
+	task.Start()
+	service.setHealth(name, HealthStopped)
+}
+
+// AddPublisher registers the publisher of a sink which is fed by a fan-out publisher, it is supervised independently of the
+// other publishers and its health is reported as publisher-<name>, it has to be called before Start
+func (service *DefaultService) AddPublisher(name string, sink publisher.Publisher) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	name = publisherName + "-" + name
+	service.sinks = append(service.sinks, namedPublisher{name: name, publisher: sink})
+	service.health[name] = HealthStopped
+}
+
 func (service *DefaultService) Start() {
 	service.log.Infoln("Start all services")
 	service.wg.Add(3)
+	service.setHealth(consumerName, HealthStarting)
+	service.setHealth(publisherName, HealthStarting)
+	go service.supervise(consumerName, service.consumer)
 	go func() {
 		defer service.wg.Done()
-		service.consumer.Start()
-	}()
-	go func() {
-		defer service.wg.Done()
+		service.setHealth(processorName, HealthHealthy)
 		service.processor.Start()
+		service.setHealth(processorName, HealthStopped)
 	}()
-	go func() {
-		defer service.wg.Done()
-		service.publisher.Start()
-	}()
+	go service.supervise(publisherName, service.publisher)
+	for _, sink := range service.sinks {
+		service.wg.Add(1)
+		service.setHealth(sink.name, HealthStarting)
+		go service.supervise(sink.name, sink.publisher)
+	}
+	for _, task := range service.tasks {
+		service.wg.Add(1)
+		go service.runTask(task.name, task.task)
+	}
 }
+
 func (service *DefaultService) Stop() {
 	service.log.Infoln("Stopping all services")
-	if err := service.consumer.Stop(); err != nil {
-		service.log.Errorln("Error stopping consumer: ", err)
+	service.mutex.Lock()
+	close(service.quitChan)
+	for _, task := range service.tasks {
+		task.task.Stop()
+	}
+	if consumer, ok := service.active[consumerName]; ok {
+		if err := consumer.Stop(); err != nil {
+			service.log.Errorln("Error stopping consumer: ", err)
+		}
 	}
+	service.mutex.Unlock()
 	service.processor.Stop()
-	if err := service.publisher.Stop(); err != nil {
-		service.log.Errorln("Error stopping publisher: ", err)
+	service.mutex.Lock()
+	if publisher, ok := service.active[publisherName]; ok {
+		if err := publisher.Stop(); err != nil {
+			service.log.Errorln("Error stopping publisher: ", err)
+		}
+	}
+	for _, sink := range service.sinks {
+		if publisher, ok := service.active[sink.name]; ok {
+			if err := publisher.Stop(); err != nil {
+				service.log.Errorf("Error stopping %s: %v", sink.name, err)
+			}
+		}
 	}
+	service.mutex.Unlock()
 	service.wg.Wait()
 }
@@ -34,13 +34,31 @@ func TestNewDefaultService(t *testing.T) {
 	}
 }
 
+// blockingStart returns a Start implementation which blocks until the component is stopped
+func blockingStart(stopChan chan struct{}) func() error {
+	return func() error {
+		<-stopChan
+		return nil
+	}
+}
+
 func TestDefaultService_Start(t *testing.T) {
 	tests := []struct {
-		name string
+		name            string
+		consumerInitErr error
+		publisherErr    error
 	}{
 		{
 			name: "Test Starting Default Service",
 		},
+		{
+			name:            "Test Starting Default Service retries Init until Kafka is available",
+			consumerInitErr: fmt.Errorf("kafka: client has run out of available brokers to talk to"),
+		},
+		{
+			name:         "Test Starting Default Service reconnects after connection loss",
+			publisherErr: fmt.Errorf("kafka: client has run out of available brokers to talk to"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,14 +67,83 @@ func TestDefaultService_Start(t *testing.T) {
 			consumer := consumer.NewMockConsumer(ctrl)
 			processor := processor.NewMockProcessor(ctrl)
 			publisher := publisher.NewMockPublisher(ctrl)
-			consumer.EXPECT().Start().Return()
-			processor.EXPECT().Start().Return()
-			publisher.EXPECT().Start().Return()
+			consumerStopChan := make(chan struct{})
+			publisherStopChan := make(chan struct{})
+			processorStopChan := make(chan struct{})
+			if tt.consumerInitErr != nil {
+				first := consumer.EXPECT().Init().Return(tt.consumerInitErr)
+				consumer.EXPECT().Stop().Return(nil).After(first)
+			}
+			consumer.EXPECT().Init().Return(nil)
+			consumer.EXPECT().Start().DoAndReturn(blockingStart(consumerStopChan))
+			consumer.EXPECT().Stop().DoAndReturn(func() error {
+				close(consumerStopChan)
+				return nil
+			})
+			if tt.publisherErr != nil {
+				first := publisher.EXPECT().Init().Return(nil)
+				failed := publisher.EXPECT().Start().Return(tt.publisherErr).After(first)
+				publisher.EXPECT().Stop().Return(nil).After(failed)
+			}
+			publisher.EXPECT().Init().Return(nil)
+			publisher.EXPECT().Start().DoAndReturn(blockingStart(publisherStopChan))
+			publisher.EXPECT().Stop().DoAndReturn(func() error {
+				close(publisherStopChan)
+				return nil
+			})
+			processor.EXPECT().Start().Do(func() { <-processorStopChan })
+			processor.EXPECT().Stop().Do(func() { close(processorStopChan) })
 			defaultService := NewDefaultService(config, consumer, processor, publisher)
+			defaultService.initialBackoff = 10 * time.Millisecond
+			defaultService.maxBackoff = 20 * time.Millisecond
 			assert.NotPanics(t, func() {
 				defaultService.Start()
-				time.Sleep(3 * time.Second)
 			})
+			assert.Eventually(t, func() bool {
+				return defaultService.Health() == HealthHealthy
+			}, time.Second, 10*time.Millisecond)
+			defaultService.Stop()
+			assert.Equal(t, HealthStopped, defaultService.Health())
+		})
+	}
+}
+
+func TestDefaultService_Health(t *testing.T) {
+	tests := []struct {
+		name   string
+		health map[string]HealthState
+		want   HealthState
+	}{
+		{
+			name:   "Test health of stopped service",
+			health: map[string]HealthState{consumerName: HealthStopped, processorName: HealthStopped, publisherName: HealthStopped},
+			want:   HealthStopped,
+		},
+		{
+			name:   "Test health of starting service",
+			health: map[string]HealthState{consumerName: HealthStarting, processorName: HealthHealthy, publisherName: HealthHealthy},
+			want:   HealthStarting,
+		},
+		{
+			name:   "Test health of healthy service",
+			health: map[string]HealthState{consumerName: HealthHealthy, processorName: HealthHealthy, publisherName: HealthHealthy},
+			want:   HealthHealthy,
+		},
+		{
+			name:   "Test health of degraded service",
+			health: map[string]HealthState{consumerName: HealthHealthy, processorName: HealthHealthy, publisherName: HealthDegraded},
+			want:   HealthDegraded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defaultService := NewDefaultService(config.NewMockConfig(ctrl), consumer.NewMockConsumer(ctrl), processor.NewMockProcessor(ctrl), publisher.NewMockPublisher(ctrl))
+			for name, state := range tt.health {
+				defaultService.setHealth(name, state)
+			}
+			assert.Equal(t, tt.want, defaultService.Health())
+			assert.Equal(t, tt.health, defaultService.ComponentHealth())
 		})
 	}
 }
@@ -71,11 +158,7 @@ func TestDefaultService_Stop(t *testing.T) {
 			wantsError: false,
 		},
 		{
-			name:       "Test Stopping Default Service with error in consumer",
-			wantsError: true,
-		},
-		{
-			name:       "Test Stopping Default Service with error in producer",
+			name:       "Test Stopping Default Service with error in consumer and publisher",
 			wantsError: true,
 		},
 	}
@@ -88,14 +171,170 @@ func TestDefaultService_Stop(t *testing.T) {
 			publisher := publisher.NewMockPublisher(ctrl)
 			defaultService := NewDefaultService(config, consumer, processor, publisher)
 			processor.EXPECT().Stop().Return()
+			var stopErr error
 			if tt.wantsError {
-				consumer.EXPECT().Stop().Return(fmt.Errorf("error stopping consumer"))
-				publisher.EXPECT().Stop().Return(fmt.Errorf("error error stopping publisher"))
-			} else {
-				consumer.EXPECT().Stop().Return(nil)
-				publisher.EXPECT().Stop().Return(nil)
+				stopErr = fmt.Errorf("error stopping component")
 			}
+			consumer.EXPECT().Stop().Return(stopErr)
+			publisher.EXPECT().Stop().Return(stopErr)
+			defaultService.active[consumerName] = consumer
+			defaultService.active[publisherName] = publisher
+			defaultService.Stop()
+		})
+	}
+}
+
+func TestDefaultService_Stop_WhileRetrying(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test Stopping Default Service while Kafka is unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			config := config.NewMockConfig(ctrl)
+			consumer := consumer.NewMockConsumer(ctrl)
+			processor := processor.NewMockProcessor(ctrl)
+			publisher := publisher.NewMockPublisher(ctrl)
+			processorStopChan := make(chan struct{})
+			initErr := fmt.Errorf("kafka: client has run out of available brokers to talk to")
+			consumer.EXPECT().Init().Return(initErr).MinTimes(1)
+			consumer.EXPECT().Stop().Return(nil).MinTimes(1)
+			publisher.EXPECT().Init().Return(initErr).MinTimes(1)
+			publisher.EXPECT().Stop().Return(nil).MinTimes(1)
+			processor.EXPECT().Start().Do(func() { <-processorStopChan })
+			processor.EXPECT().Stop().Do(func() { close(processorStopChan) })
+			defaultService := NewDefaultService(config, consumer, processor, publisher)
+			defaultService.initialBackoff = time.Hour
+			defaultService.Start()
+			assert.Eventually(t, func() bool {
+				return defaultService.Health() == HealthDegraded
+			}, time.Second, 10*time.Millisecond)
+			defaultService.Stop()
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()[consumerName])
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()[publisherName])
+		})
+	}
+}
+
+// blockingTask runs until it is stopped
+type blockingTask struct {
+	stopChan chan struct{}
+}
+
+func (task *blockingTask) Start() {
+	<-task.stopChan
+}
+
+func (task *blockingTask) Stop() {
+	close(task.stopChan)
+}
+
+func TestDefaultService_AddTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		taskName string
+	}{
+		{
+			name:     "Test task runs until the service is stopped",
+			taskName: "reconciler",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			consumer := consumer.NewMockConsumer(ctrl)
+			processor := processor.NewMockProcessor(ctrl)
+			publisher := publisher.NewMockPublisher(ctrl)
+			consumerStopChan := make(chan struct{})
+			publisherStopChan := make(chan struct{})
+			processorStopChan := make(chan struct{})
+			consumer.EXPECT().Init().Return(nil)
+			consumer.EXPECT().Start().DoAndReturn(blockingStart(consumerStopChan))
+			consumer.EXPECT().Stop().DoAndReturn(func() error {
+				close(consumerStopChan)
+				return nil
+			})
+			publisher.EXPECT().Init().Return(nil)
+			publisher.EXPECT().Start().DoAndReturn(blockingStart(publisherStopChan))
+			publisher.EXPECT().Stop().DoAndReturn(func() error {
+				close(publisherStopChan)
+				return nil
+			})
+			processor.EXPECT().Start().Do(func() { <-processorStopChan })
+			processor.EXPECT().Stop().Do(func() { close(processorStopChan) })
+			defaultService := NewDefaultService(config.NewMockConfig(ctrl), consumer, processor, publisher)
+			defaultService.AddTask(tt.taskName, &blockingTask{stopChan: make(chan struct{})})
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()[tt.taskName])
+			defaultService.Start()
+			assert.Eventually(t, func() bool {
+				return defaultService.ComponentHealth()[tt.taskName] == HealthHealthy && defaultService.Health() == HealthHealthy
+			}, time.Second, 10*time.Millisecond)
+			defaultService.Stop()
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()[tt.taskName])
+		})
+	}
+}
+
+func TestDefaultService_AddPublisher(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{
+			name: "Test broken sink is retried without affecting the other sink",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			consumer := consumer.NewMockConsumer(ctrl)
+			processor := processor.NewMockProcessor(ctrl)
+			fanout := publisher.NewMockPublisher(ctrl)
+			healthySink := publisher.NewMockPublisher(ctrl)
+			brokenSink := publisher.NewMockPublisher(ctrl)
+			consumerStopChan := make(chan struct{})
+			fanoutStopChan := make(chan struct{})
+			sinkStopChan := make(chan struct{})
+			processorStopChan := make(chan struct{})
+			consumer.EXPECT().Init().Return(nil)
+			consumer.EXPECT().Start().DoAndReturn(blockingStart(consumerStopChan))
+			consumer.EXPECT().Stop().DoAndReturn(func() error {
+				close(consumerStopChan)
+				return nil
+			})
+			fanout.EXPECT().Init().Return(nil)
+			fanout.EXPECT().Start().DoAndReturn(blockingStart(fanoutStopChan))
+			fanout.EXPECT().Stop().DoAndReturn(func() error {
+				close(fanoutStopChan)
+				return nil
+			})
+			healthySink.EXPECT().Init().Return(nil)
+			healthySink.EXPECT().Start().DoAndReturn(blockingStart(sinkStopChan))
+			healthySink.EXPECT().Stop().DoAndReturn(func() error {
+				close(sinkStopChan)
+				return nil
+			})
+			brokenSink.EXPECT().Init().Return(fmt.Errorf("InfluxDB unavailable")).MinTimes(1)
+			brokenSink.EXPECT().Stop().Return(nil).MinTimes(1)
+			processor.EXPECT().Start().Do(func() { <-processorStopChan })
+			processor.EXPECT().Stop().Do(func() { close(processorStopChan) })
+			defaultService := NewDefaultService(config.NewMockConfig(ctrl), consumer, processor, fanout)
+			defaultService.initialBackoff = time.Hour
+			defaultService.AddPublisher("file", healthySink)
+			defaultService.AddPublisher("influx", brokenSink)
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()["publisher-file"])
+			defaultService.Start()
+			assert.Eventually(t, func() bool {
+				health := defaultService.ComponentHealth()
+				return health["publisher-file"] == HealthHealthy && health["publisher-influx"] == HealthDegraded && health[publisherName] == HealthHealthy
+			}, time.Second, 10*time.Millisecond)
+			assert.Equal(t, HealthDegraded, defaultService.Health())
 			defaultService.Stop()
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()["publisher-file"])
+			assert.Equal(t, HealthStopped, defaultService.ComponentHealth()["publisher-influx"])
 		})
 	}
 }
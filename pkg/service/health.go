@@ -0,0 +1,29 @@
+package service
+
+// HealthState describes whether a component of the service is connected and processing messages
+type HealthState string
+
+const (
+	HealthStarting HealthState = "starting"
+	HealthHealthy  HealthState = "healthy"
+	HealthDegraded HealthState = "degraded"
+	HealthStopped  HealthState = "stopped"
+)
+
+// aggregateHealth returns the worst state of all components
+func aggregateHealth(states map[string]HealthState) HealthState {
+	health := HealthStopped
+	for _, state := range states {
+		switch state {
+		case HealthDegraded:
+			return HealthDegraded
+		case HealthStarting:
+			health = HealthStarting
+		case HealthHealthy:
+			if health == HealthStopped {
+				health = HealthHealthy
+			}
+		}
+	}
+	return health
+}
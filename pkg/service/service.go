@@ -1,8 +1,14 @@
 package service
 
+import "github.com/hawkv6/clab-telemetry-linker/pkg/publisher"
+
 var subsystem = "service"
 
 type Service interface {
+	AddTask(name string, task Task)
+	AddPublisher(name string, sink publisher.Publisher)
 	Start()
-	Stop() error
+	Stop()
+	Health() HealthState
+	ComponentHealth() map[string]HealthState
 }
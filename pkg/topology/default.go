@@ -0,0 +1,137 @@
+package topology
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const topologyKey = "topology"
+
+// storedTopology is the config layout of the topology, links are stored as pair of node:interface endpoints
+type storedTopology struct {
+	Name  string     `koanf:"name"`
+	Nodes []string   `koanf:"nodes"`
+	Links [][]string `koanf:"links"`
+}
+
+// Store replaces the topology in the config and sets the clab name to the prefix of the lab, the config is not written
+func Store(config config.Config, helper helpers.Helper, topology *Topology) error {
+	links := make([][]string, 0, len(topology.Links))
+	for _, topologyLink := range topology.Links {
+		links = append(links, []string{topologyLink.A.String(), topologyLink.B.String()})
+	}
+	config.DeleteValue(topologyKey)
+	values := map[string]interface{}{
+		topologyKey + ".name":          topology.Name,
+		topologyKey + ".nodes":         topology.Nodes,
+		topologyKey + ".links":         links,
+		helper.GetDefaultClabNameKey(): topology.ClabName,
+	}
+	for key, value := range values {
+		if err := config.SetValue(key, value); err != nil {
+			return fmt.Errorf("Unable to store topology: %v", err)
+		}
+	}
+	return nil
+}
+
+// Load returns the topology stored in the config or nil if no topology was imported
+func Load(config config.Config, helper helpers.Helper) (*Topology, error) {
+	stored := storedTopology{}
+	if err := config.Unmarshal(topologyKey, &stored); err != nil {
+		return nil, fmt.Errorf("Unable to read topology from config: %v", err)
+	}
+	if stored.Name == "" {
+		return nil, nil
+	}
+	topology := &Topology{Name: stored.Name, ClabName: config.GetValue(helper.GetDefaultClabNameKey()), Nodes: stored.Nodes}
+	for _, endpoints := range stored.Links {
+		if len(endpoints) != 2 {
+			return nil, fmt.Errorf("Invalid link %v in config", endpoints)
+		}
+		a, err := link.ParseEndpoint(endpoints[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := link.ParseEndpoint(endpoints[1])
+		if err != nil {
+			return nil, err
+		}
+		topology.Links = append(topology.Links, link.Link{A: a, B: b})
+	}
+	return topology, nil
+}
+
+type DefaultValidator struct {
+	log      *logrus.Entry
+	topology *Topology
+}
+
+// NewDefaultValidator validates against the topology, without topology all arguments are accepted
+func NewDefaultValidator(topology *Topology) *DefaultValidator {
+	return &DefaultValidator{
+		log:      logging.DefaultLogger.WithField("subsystem", subsystem),
+		topology: topology,
+	}
+}
+
+func (validator *DefaultValidator) ValidateNode(node string) error {
+	if validator.topology == nil {
+		validator.log.Debugln("No topology imported, skip validation of node ", node)
+		return nil
+	}
+	for _, name := range validator.topology.Nodes {
+		if name == node {
+			return nil
+		}
+	}
+	return fmt.Errorf("Node %s is not part of lab %s, nodes: %s", node, validator.topology.Name, strings.Join(validator.topology.Nodes, ", "))
+}
+
+// ValidateInterface accepts every interface of nodes without links, e.g. if the topology was imported with containerlab inspect
+func (validator *DefaultValidator) ValidateInterface(node, interface_ string) error {
+	if err := validator.ValidateNode(node); err != nil || validator.topology == nil {
+		return err
+	}
+	var interfaces []string
+	for _, topologyLink := range validator.topology.Links {
+		for _, endpoint := range []link.Endpoint{topologyLink.A, topologyLink.B} {
+			if endpoint.Node != node {
+				continue
+			}
+			if endpoint.Interface == interface_ {
+				return nil
+			}
+			interfaces = append(interfaces, endpoint.Interface)
+		}
+	}
+	if len(interfaces) == 0 {
+		return nil
+	}
+	return fmt.Errorf("Interface %s is not linked on node %s, interfaces: %s", interface_, node, strings.Join(interfaces, ", "))
+}
+
+// ValidateLink requires both endpoints to be connected if the topology contains links
+func (validator *DefaultValidator) ValidateLink(link link.Link) error {
+	if err := validator.ValidateInterface(link.A.Node, link.A.Interface); err != nil {
+		return err
+	}
+	if err := validator.ValidateInterface(link.B.Node, link.B.Interface); err != nil {
+		return err
+	}
+	if validator.topology == nil || len(validator.topology.Links) == 0 {
+		return nil
+	}
+	for _, topologyLink := range validator.topology.Links {
+		if (topologyLink.A == link.A && topologyLink.B == link.B) || (topologyLink.A == link.B && topologyLink.B == link.A) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Link %s is not part of lab %s", link, validator.topology.Name)
+}
@@ -0,0 +1,140 @@
+package topology
+
+import (
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/config"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/helpers"
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMemoryConfig(t *testing.T, content string) *config.MemoryConfig {
+	memoryConfig, err := config.NewMemoryConfig(content)
+	assert.NoError(t, err)
+	return memoryConfig
+}
+
+var testTopology = &Topology{
+	Name:     "hawkv6",
+	ClabName: "lab-hawkv6",
+	Nodes:    []string{"XR-1", "XR-2", "XR-3"},
+	Links: []link.Link{
+		{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}},
+		{A: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}, B: link.Endpoint{Node: "XR-3", Interface: "Gi0-0-0-0"}},
+	},
+}
+
+func TestStoreAndLoad(t *testing.T) {
+	helper := helpers.NewDefaultHelper()
+	memoryConfig := newMemoryConfig(t, "clab-name: clab-hawkv6\ntopology:\n  name: old\n  nodes: [XR-9]\n")
+	assert.NoError(t, Store(memoryConfig, helper, testTopology))
+	topology, err := Load(memoryConfig, helper)
+	assert.NoError(t, err)
+	assert.Equal(t, testTopology, topology)
+
+	// the topology is still valid after it was written to and read from the config file
+	content, err := memoryConfig.Content()
+	assert.NoError(t, err)
+	topology, err = Load(newMemoryConfig(t, content), helper)
+	assert.NoError(t, err)
+	assert.Equal(t, testTopology, topology)
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Topology
+		wantErr bool
+	}{
+		{
+			name: "Test config without topology",
+			data: "clab-name: clab-hawkv6\n",
+			want: nil,
+		},
+		{
+			name:    "Test config with invalid link",
+			data:    "topology:\n  name: hawkv6\n  links:\n    - [XR-1:Gi0-0-0-0]\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test config with invalid endpoint",
+			data:    "topology:\n  name: hawkv6\n  links:\n    - [XR-1, XR-2:Gi0-0-0-0]\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topology, err := Load(newMemoryConfig(t, tt.data), helpers.NewDefaultHelper())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, topology)
+		})
+	}
+}
+
+func TestDefaultValidator(t *testing.T) {
+	withoutLinks := &Topology{Name: "hawkv6", Nodes: []string{"XR-1", "XR-2"}}
+	tests := []struct {
+		name          string
+		topology      *Topology
+		node          string
+		interface_    string
+		link          link.Link
+		wantNode      bool
+		wantInterface bool
+		wantLink      bool
+	}{
+		{
+			name:       "Test without topology everything is valid",
+			node:       "XR-9",
+			interface_: "Gi0-0-0-9",
+			link:       link.Link{A: link.Endpoint{Node: "XR-8", Interface: "eth1"}, B: link.Endpoint{Node: "XR-9", Interface: "eth1"}},
+		},
+		{
+			name:       "Test linked interface and link in reverse order",
+			topology:   testTopology,
+			node:       "XR-2",
+			interface_: "Gi0-0-0-1",
+			link:       link.Link{A: link.Endpoint{Node: "XR-3", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}},
+		},
+		{
+			name:          "Test unknown node",
+			topology:      testTopology,
+			node:          "XR-9",
+			interface_:    "Gi0-0-0-0",
+			link:          link.Link{A: link.Endpoint{Node: "XR-9", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}},
+			wantNode:      true,
+			wantInterface: true,
+			wantLink:      true,
+		},
+		{
+			name:          "Test interface which is not linked and endpoints which are not connected",
+			topology:      testTopology,
+			node:          "XR-1",
+			interface_:    "Gi0-0-0-5",
+			link:          link.Link{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-3", Interface: "Gi0-0-0-0"}},
+			wantInterface: true,
+			wantLink:      true,
+		},
+		{
+			name:       "Test topology without links accepts every interface of known nodes",
+			topology:   withoutLinks,
+			node:       "XR-1",
+			interface_: "Gi0-0-0-5",
+			link:       link.Link{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewDefaultValidator(tt.topology)
+			assert.Equal(t, tt.wantNode, validator.ValidateNode(tt.node) != nil)
+			assert.Equal(t, tt.wantInterface, validator.ValidateInterface(tt.node, tt.interface_) != nil)
+			assert.Equal(t, tt.wantLink, validator.ValidateLink(tt.link) != nil)
+		})
+	}
+}
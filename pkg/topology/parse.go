@@ -0,0 +1,199 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultPrefix is used by containerlab if the topology file does not set a prefix
+const defaultPrefix = "clab"
+
+// labNamePrefix makes containerlab use the lab name as prefix
+const labNamePrefix = "__lab-name"
+
+// Topology contains the nodes and links of a lab, ClabName is the prefix of the container names e.g. clab-hawkv6
+type Topology struct {
+	Name     string      `json:"name" yaml:"name"`
+	ClabName string      `json:"clab_name" yaml:"clab_name"`
+	Nodes    []string    `json:"nodes" yaml:"nodes"`
+	Links    []link.Link `json:"links" yaml:"links"`
+}
+
+// clabFile is the part of a containerlab topology file used by the linker
+type clabFile struct {
+	Name     string  `yaml:"name"`
+	Prefix   *string `yaml:"prefix"`
+	Topology struct {
+		Nodes map[string]interface{} `yaml:"nodes"`
+		Links []struct {
+			Endpoints []interface{} `yaml:"endpoints"`
+		} `yaml:"links"`
+	} `yaml:"topology"`
+}
+
+// getClabName returns the container name prefix like containerlab does
+func getClabName(name string, prefix *string) string {
+	switch {
+	case prefix == nil:
+		return defaultPrefix + "-" + name
+	case *prefix == "":
+		return ""
+	case *prefix == labNamePrefix:
+		return name
+	default:
+		return *prefix + "-" + name
+	}
+}
+
+// parseEndpoint accepts the short node:interface and the extended node / interface format of containerlab
+func parseEndpoint(value interface{}) (link.Endpoint, error) {
+	switch endpoint := value.(type) {
+	case string:
+		return link.ParseEndpoint(endpoint)
+	case map[string]interface{}:
+		node, _ := endpoint["node"].(string)
+		interface_, _ := endpoint["interface"].(string)
+		if node == "" || interface_ == "" {
+			return link.Endpoint{}, fmt.Errorf("Invalid link endpoint %v, node and interface are required", endpoint)
+		}
+		return link.Endpoint{Node: node, Interface: interface_}, nil
+	default:
+		return link.Endpoint{}, fmt.Errorf("Invalid link endpoint %v", value)
+	}
+}
+
+// ParseTopologyFile reads a containerlab topology file, links to the host or management network are skipped
+func ParseTopologyFile(data []byte) (*Topology, error) {
+	file := clabFile{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("Invalid containerlab topology: %v", err)
+	}
+	if file.Name == "" {
+		return nil, fmt.Errorf("Containerlab topology has no name")
+	}
+	topology := &Topology{Name: file.Name, ClabName: getClabName(file.Name, file.Prefix)}
+	for node := range file.Topology.Nodes {
+		topology.Nodes = append(topology.Nodes, node)
+	}
+	sort.Strings(topology.Nodes)
+	for i, fileLink := range file.Topology.Links {
+		if len(fileLink.Endpoints) != 2 {
+			continue
+		}
+		var endpoints [2]link.Endpoint
+		for j, value := range fileLink.Endpoints {
+			endpoint, err := parseEndpoint(value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid link %d: %v", i+1, err)
+			}
+			endpoints[j] = endpoint
+		}
+		if _, ok := file.Topology.Nodes[endpoints[0].Node]; !ok {
+			continue
+		}
+		if _, ok := file.Topology.Nodes[endpoints[1].Node]; !ok {
+			continue
+		}
+		topology.Links = append(topology.Links, link.Link{A: endpoints[0], B: endpoints[1]})
+	}
+	return topology, nil
+}
+
+func LoadTopologyFile(fileName string) (*Topology, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read containerlab topology: %v", err)
+	}
+	return ParseTopologyFile(data)
+}
+
+// inspectContainer is a container of containerlab inspect --format json
+type inspectContainer struct {
+	LabName string `json:"lab_name"`
+	Name    string `json:"name"`
+}
+
+// splitContainerName splits a container name like clab-hawkv6-XR-1 into the prefix and the node name
+func splitContainerName(name, lab string) (string, string) {
+	if index := strings.Index(name, "-"+lab+"-"); index >= 0 {
+		return name[:index+1+len(lab)], name[index+len(lab)+2:]
+	}
+	if strings.HasPrefix(name, lab+"-") {
+		return lab, name[len(lab)+1:]
+	}
+	return "", name
+}
+
+// parseInspectContainers accepts the output of older containerlab versions {"containers": [...]} and of newer ones {"<lab>": [...]}
+func parseInspectContainers(data []byte) ([]inspectContainer, error) {
+	labs := make(map[string][]inspectContainer)
+	if err := json.Unmarshal(data, &labs); err != nil {
+		return nil, fmt.Errorf("Invalid containerlab inspect output: %v", err)
+	}
+	var containers []inspectContainer
+	for _, labContainers := range labs {
+		containers = append(containers, labContainers...)
+	}
+	return containers, nil
+}
+
+// ParseInspectOutput reads the nodes of a deployed lab, the output does not contain links
+func ParseInspectOutput(data []byte, lab string) (*Topology, error) {
+	containers, err := parseInspectContainers(data)
+	if err != nil {
+		return nil, err
+	}
+	labs := make(map[string]bool)
+	for _, container := range containers {
+		if lab == "" || container.LabName == lab {
+			labs[container.LabName] = true
+		}
+	}
+	if len(labs) != 1 {
+		names := make([]string, 0, len(labs))
+		for name := range labs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("Expected exactly one deployed lab but found %d %v, select the lab by name", len(labs), names)
+	}
+	topology := &Topology{}
+	for name := range labs {
+		topology.Name = name
+	}
+	for _, container := range containers {
+		if container.LabName != topology.Name {
+			continue
+		}
+		clabName, node := splitContainerName(container.Name, topology.Name)
+		if len(topology.Nodes) > 0 && clabName != topology.ClabName {
+			return nil, fmt.Errorf("Containers of lab %s use different prefixes %q and %q", topology.Name, topology.ClabName, clabName)
+		}
+		topology.ClabName = clabName
+		topology.Nodes = append(topology.Nodes, node)
+	}
+	sort.Strings(topology.Nodes)
+	return topology, nil
+}
+
+// Inspect reads the nodes of a deployed lab with containerlab inspect, without lab all labs are inspected
+func Inspect(lab string) (*Topology, error) {
+	command := exec.Command("containerlab", "inspect", "--format", "json")
+	if lab != "" {
+		command.Args = append(command.Args, "--name", lab)
+	} else {
+		command.Args = append(command.Args, "--all")
+	}
+	data, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to inspect containerlab: %v", err)
+	}
+	return ParseInspectOutput(data, lab)
+}
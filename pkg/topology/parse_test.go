@@ -0,0 +1,172 @@
+package topology
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTopologyFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Topology
+		wantErr bool
+	}{
+		{
+			name: "Test topology with short and extended links",
+			data: `name: hawkv6
+mgmt:
+  network: hawkv6-mgmt
+topology:
+  kinds:
+    cisco_xrd:
+      image: ios-xr/xrd-control-plane:7.10.2
+  nodes:
+    XR-2:
+      kind: cisco_xrd
+    XR-1:
+      kind: cisco_xrd
+    XR-3:
+      kind: cisco_xrd
+  links:
+    - endpoints: ["XR-1:Gi0-0-0-0", "XR-2:Gi0-0-0-0"]
+    - type: veth
+      endpoints:
+        - node: XR-2
+          interface: Gi0-0-0-1
+        - node: XR-3
+          interface: Gi0-0-0-0
+    - endpoints: ["XR-3:Gi0-0-0-1", "host:xr3-eth"]
+    - type: mgmt-net
+      endpoint:
+        node: XR-1
+        interface: eth5
+`,
+			want: &Topology{
+				Name:     "hawkv6",
+				ClabName: "clab-hawkv6",
+				Nodes:    []string{"XR-1", "XR-2", "XR-3"},
+				Links: []link.Link{
+					{A: link.Endpoint{Node: "XR-1", Interface: "Gi0-0-0-0"}, B: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-0"}},
+					{A: link.Endpoint{Node: "XR-2", Interface: "Gi0-0-0-1"}, B: link.Endpoint{Node: "XR-3", Interface: "Gi0-0-0-0"}},
+				},
+			},
+		},
+		{
+			name: "Test topology with custom prefix",
+			data: "name: hawkv6\nprefix: lab\ntopology:\n  nodes:\n    XR-1: {}\n",
+			want: &Topology{Name: "hawkv6", ClabName: "lab-hawkv6", Nodes: []string{"XR-1"}},
+		},
+		{
+			name: "Test topology with empty prefix",
+			data: "name: hawkv6\nprefix: \"\"\ntopology:\n  nodes:\n    XR-1: {}\n",
+			want: &Topology{Name: "hawkv6", ClabName: "", Nodes: []string{"XR-1"}},
+		},
+		{
+			name: "Test topology with lab name prefix",
+			data: "name: hawkv6\nprefix: __lab-name\ntopology:\n  nodes:\n    XR-1: {}\n",
+			want: &Topology{Name: "hawkv6", ClabName: "hawkv6", Nodes: []string{"XR-1"}},
+		},
+		{
+			name:    "Test topology without name",
+			data:    "topology:\n  nodes:\n    XR-1: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test topology with invalid endpoint",
+			data:    "name: hawkv6\ntopology:\n  nodes:\n    XR-1: {}\n  links:\n    - endpoints: [\"XR-1\", \"XR-2:Gi0-0-0-0\"]\n",
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid YAML",
+			data:    "name: [",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topology, err := ParseTopologyFile([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, topology)
+		})
+	}
+}
+
+func TestLoadTopologyFile(t *testing.T) {
+	directory := t.TempDir()
+	fileName := filepath.Join(directory, "hawkv6.clab.yml")
+	assert.NoError(t, os.WriteFile(fileName, []byte("name: hawkv6\ntopology:\n  nodes:\n    XR-1: {}\n"), 0644))
+	topology, err := LoadTopologyFile(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "clab-hawkv6", topology.ClabName)
+	_, err = LoadTopologyFile(filepath.Join(directory, "missing.clab.yml"))
+	assert.Error(t, err)
+}
+
+func TestParseInspectOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		lab     string
+		want    *Topology
+		wantErr bool
+	}{
+		{
+			name: "Test output of older containerlab versions",
+			data: `{"containers": [
+				{"lab_name": "hawkv6", "name": "clab-hawkv6-XR-2", "state": "running"},
+				{"lab_name": "hawkv6", "name": "clab-hawkv6-XR-1", "state": "running"}
+			]}`,
+			want: &Topology{Name: "hawkv6", ClabName: "clab-hawkv6", Nodes: []string{"XR-1", "XR-2"}},
+		},
+		{
+			name: "Test output of newer containerlab versions with several labs",
+			data: `{
+				"hawkv6": [{"lab_name": "hawkv6", "name": "hawkv6-XR-1"}],
+				"other": [{"lab_name": "other", "name": "clab-other-srl"}]
+			}`,
+			lab:  "hawkv6",
+			want: &Topology{Name: "hawkv6", ClabName: "hawkv6", Nodes: []string{"XR-1"}},
+		},
+		{
+			name: "Test lab without prefix",
+			data: `{"containers": [{"lab_name": "hawkv6", "name": "XR-1"}]}`,
+			want: &Topology{Name: "hawkv6", ClabName: "", Nodes: []string{"XR-1"}},
+		},
+		{
+			name:    "Test several labs without selection",
+			data:    `{"hawkv6": [{"lab_name": "hawkv6", "name": "clab-hawkv6-XR-1"}], "other": [{"lab_name": "other", "name": "clab-other-srl"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test unknown lab",
+			data:    `{"containers": [{"lab_name": "hawkv6", "name": "clab-hawkv6-XR-1"}]}`,
+			lab:     "other",
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid output",
+			data:    `[`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topology, err := ParseInspectOutput([]byte(tt.data), tt.lab)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, topology)
+		})
+	}
+}
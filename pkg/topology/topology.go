@@ -0,0 +1,11 @@
+package topology
+
+import "github.com/hawkv6/clab-telemetry-linker/pkg/link"
+
+var subsystem = "topology"
+
+type Validator interface {
+	ValidateNode(node string) error
+	ValidateInterface(node, interface_ string) error
+	ValidateLink(link link.Link) error
+}
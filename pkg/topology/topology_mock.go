@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: topology.go
+//
+// Generated by this command:
+//
+//	mockgen -source=topology.go -destination=topology_mock.go -package=topology
+//
+
+// Package topology is a generated GoMock package.
+package topology
+
+import (
+	reflect "reflect"
+
+	link "github.com/hawkv6/clab-telemetry-linker/pkg/link"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockValidator is a mock of Validator interface.
+type MockValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockValidatorMockRecorder
+}
+
+// MockValidatorMockRecorder is the mock recorder for MockValidator.
+type MockValidatorMockRecorder struct {
+	mock *MockValidator
+}
+
+// NewMockValidator creates a new mock instance.
+func NewMockValidator(ctrl *gomock.Controller) *MockValidator {
+	mock := &MockValidator{ctrl: ctrl}
+	mock.recorder = &MockValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValidator) EXPECT() *MockValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateInterface mocks base method.
+func (m *MockValidator) ValidateInterface(node, interface_ string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateInterface", node, interface_)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateInterface indicates an expected call of ValidateInterface.
+func (mr *MockValidatorMockRecorder) ValidateInterface(node, interface_ any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateInterface", reflect.TypeOf((*MockValidator)(nil).ValidateInterface), node, interface_)
+}
+
+// ValidateLink mocks base method.
+func (m *MockValidator) ValidateLink(link link.Link) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateLink", link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateLink indicates an expected call of ValidateLink.
+func (mr *MockValidatorMockRecorder) ValidateLink(link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateLink", reflect.TypeOf((*MockValidator)(nil).ValidateLink), link)
+}
+
+// ValidateNode mocks base method.
+func (m *MockValidator) ValidateNode(node string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateNode", node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateNode indicates an expected call of ValidateNode.
+func (mr *MockValidatorMockRecorder) ValidateNode(node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateNode", reflect.TypeOf((*MockValidator)(nil).ValidateNode), node)
+}